@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"tbunny/internal/cluster"
+	"tbunny/internal/config"
+	"tbunny/internal/ui"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputWide  outputFormat = "wide"
+	outputJSON  outputFormat = "json"
+	outputYAML  outputFormat = "yaml"
+)
+
+var (
+	output      string
+	clusterFlag string
+	vhostFlag   string
+)
+
+// addCommands registers non-interactive subcommands of the root command.
+func addCommands() {
+	rootCmd.AddCommand(
+		newClustersCmd(),
+		newQueuesCmd(),
+		newQueueCmd(),
+		newExchangesCmd(),
+		newMessageCmd(),
+	)
+}
+
+// addOutputFlag adds the -o/--output flag to a command and all its subcommands.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&output, "output", "o", string(outputTable), "Output format: table, wide, json or yaml")
+}
+
+// addClusterFlags adds --cluster and --vhost flags to a command and all its subcommands.
+func addClusterFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&clusterFlag, "cluster", "", "Cluster to use instead of the active one")
+	cmd.PersistentFlags().StringVar(&vhostFlag, "vhost", "", "Virtual host to use instead of the cluster's active one")
+}
+
+// initCli prepares logging and configuration the same way the TUI does.
+func initCli() (func(), error) {
+	closeLog, err := initLogging()
+	if err != nil {
+		return nil, err
+	}
+
+	config.Init(configDir)
+
+	if err := unlockCredentials(); err != nil {
+		closeLog()
+		return nil, err
+	}
+
+	cluster.Init(config.RootDirectory())
+
+	return closeLog, nil
+}
+
+// withCluster connects to the cluster selected by --cluster (or the active
+// one), runs fn and disconnects. The active cluster is left untouched.
+func withCluster(fn func(c *cluster.Cluster) error) error {
+	closeLog, err := initCli()
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	name := clusterFlag
+	if name == "" {
+		name = cluster.ActiveClusterName()
+	}
+
+	if name == "" {
+		return fmt.Errorf("no cluster configured, use --cluster or add one in the UI")
+	}
+
+	c, err := cluster.Open(name)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return fn(c)
+}
+
+// listVhost returns the virtual host to list resources in; an empty string
+// means all virtual hosts.
+func listVhost(c *cluster.Cluster) string {
+	if vhostFlag != "" {
+		return vhostFlag
+	}
+
+	return c.ActiveVirtualHost()
+}
+
+// targetVhost returns the virtual host a single resource lives in.
+func targetVhost(c *cluster.Cluster) string {
+	if vhost := listVhost(c); vhost != "" {
+		return vhost
+	}
+
+	return "/"
+}
+
+func parseOutputFormat() (outputFormat, error) {
+	switch f := outputFormat(strings.ToLower(output)); f {
+	case outputTable, outputWide, outputJSON, outputYAML:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected table, wide, json or yaml", output)
+	}
+}
+
+// printOutput writes data as JSON or YAML, or rows as a table built from
+// the columns returned by columnsFn.
+func printOutput[R ui.TableRow](data any, rows []R, columnsFn func(wide bool) []ui.TableColumn) error {
+	format, err := parseOutputFormat()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case outputJSON:
+		return printJSON(os.Stdout, data)
+	case outputYAML:
+		return printYAML(os.Stdout, data)
+	default:
+		return printTable(os.Stdout, rows, columnsFn(format == outputWide))
+	}
+}
+
+func printJSON(w io.Writer, data any) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(data)
+}
+
+// printYAML converts data to YAML through its JSON representation, so that
+// field names and their order are the same as in the JSON output.
+func printYAML(w io.Writer, data any) error {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err = yaml.Unmarshal(content, &node); err != nil {
+		return err
+	}
+
+	resetYAMLStyle(&node)
+
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+
+	if err = e.Encode(&node); err != nil {
+		return err
+	}
+
+	return e.Close()
+}
+
+// resetYAMLStyle switches nodes parsed from JSON to the block style.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
+func printTable[R ui.TableRow](w io.Writer, rows []R, columns []ui.TableColumn) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	titles := make([]string, len(columns))
+	for i, c := range columns {
+		titles[i] = c.Title
+	}
+	_, _ = fmt.Fprintln(tw, strings.Join(titles, "\t"))
+
+	for _, r := range rows {
+		values := make([]string, len(columns))
+		for i, c := range columns {
+			values[i] = r.GetTableColumnValue(c.Name)
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"tbunny/internal/cluster"
+	"tbunny/internal/ui"
+	"tbunny/internal/view/clusters"
+
+	"github.com/spf13/cobra"
+)
+
+// clusterSummary is what gets printed for a saved cluster. Credentials are
+// deliberately left out, so the output is safe to share.
+type clusterSummary struct {
+	Name       string `json:"name"`
+	Active     bool   `json:"active"`
+	Connection string `json:"connection"`
+	Username   string `json:"username"`
+	Vhost      string `json:"vhost,omitempty"`
+}
+
+func newClustersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "Manage saved clusters",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List saved clusters",
+		Args:  cobra.NoArgs,
+		RunE:  listClusters,
+	})
+
+	addOutputFlag(cmd)
+
+	return cmd
+}
+
+func listClusters(*cobra.Command, []string) error {
+	closeLog, err := initCli()
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	configs := cluster.Clusters()
+	active := cluster.ActiveClusterName()
+
+	var summaries []clusterSummary
+	var rows []*clusters.ClusterResource
+
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		cfg := configs[name]
+
+		summaries = append(summaries, clusterSummary{
+			Name:       name,
+			Active:     name == active,
+			Connection: cfg.Connection.String(),
+			Username:   cfg.Connection.Username,
+			Vhost:      cfg.Vhost,
+		})
+		rows = append(rows, clusters.NewClusterResource(name, cfg, name == active))
+	}
+
+	return printOutput(summaries, rows, func(bool) []ui.TableColumn {
+		return []ui.TableColumn{
+			{Name: "name", Title: "NAME"},
+			{Name: "connection", Title: "CONNECTION"},
+			{Name: "username", Title: "USER"},
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"tbunny/internal/config"
+	"tbunny/internal/credentials"
+
+	"golang.org/x/term"
+)
+
+const (
+	// passphraseEnv holds the master passphrase of the encrypted password
+	// store when it cannot be prompted, e.g. in scripts.
+	passphraseEnv = "TBUNNY_PASSPHRASE"
+
+	// maxPassphraseAttempts is the number of times the master passphrase is prompted.
+	maxPassphraseAttempts = 3
+)
+
+// unlockCredentials unlocks the encrypted password store with the master
+// passphrase when it is used. It must be called before clusters are loaded,
+// as their passwords may be moved to the store.
+func unlockCredentials() error {
+	credentials.Init(config.RootDirectory())
+
+	exists := credentials.EncryptedFileExists()
+	if !exists && config.Current().Credentials.Store != credentials.EncryptedStore {
+		return nil
+	}
+
+	if passphrase, ok := os.LookupEnv(passphraseEnv); ok {
+		return credentials.Unlock(passphrase)
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("the master passphrase of the encrypted password store must be set in %s", passphraseEnv)
+	}
+
+	for attempt := 1; ; attempt++ {
+		passphrase, err := readPassphrase(fd, "Master passphrase: ")
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			confirmation, err := readPassphrase(fd, "Confirm the new master passphrase: ")
+			if err != nil {
+				return err
+			}
+
+			if confirmation != passphrase {
+				fmt.Fprintln(os.Stderr, "Passphrases do not match.")
+				continue
+			}
+		}
+
+		err = credentials.Unlock(passphrase)
+		if errors.Is(err, credentials.ErrWrongPassphrase) && attempt < maxPassphraseAttempts {
+			fmt.Fprintln(os.Stderr, "Wrong passphrase, try again.")
+			continue
+		}
+
+		return err
+	}
+}
+
+func readPassphrase(fd int, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+
+	passphrase, err := term.ReadPassword(fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	return string(passphrase), nil
+}
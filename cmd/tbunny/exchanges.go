@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"tbunny/internal/cluster"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view/exchanges"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/spf13/cobra"
+)
+
+func newExchangesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exchanges",
+		Short: "Inspect exchanges",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List exchanges",
+		Args:  cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return withCluster(listExchanges)
+		},
+	})
+
+	addOutputFlag(cmd)
+	addClusterFlags(cmd)
+
+	return cmd
+}
+
+func listExchanges(c *cluster.Cluster) error {
+	var list []rabbithole.ExchangeInfo
+	var err error
+
+	vhost := listVhost(c)
+	if vhost == "" {
+		list, err = c.ListExchanges()
+	} else {
+		list, err = c.ListExchangesIn(vhost)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to list exchanges: %w", err)
+	}
+
+	rows := utils.Map(list, func(i rabbithole.ExchangeInfo) *exchanges.ExchangeResource {
+		return &exchanges.ExchangeResource{ExchangeInfo: i}
+	})
+
+	return printOutput(list, rows, func(bool) []ui.TableColumn {
+		return exchanges.Columns(vhost == "")
+	})
+}
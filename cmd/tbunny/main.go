@@ -9,6 +9,7 @@ import (
 	"runtime/debug"
 	"tbunny/internal/cluster"
 	"tbunny/internal/config"
+	"tbunny/internal/publish"
 	"tbunny/internal/sl"
 	"tbunny/internal/view/application"
 	"time"
@@ -22,10 +23,11 @@ import (
 var (
 	version = "dev"
 	rootCmd = &cobra.Command{
-		Use:     "tbunny",
-		Short:   "A fast, keyboard-driven terminal UI for managing RabbitMQ clusters",
-		Version: version,
-		RunE:    run,
+		Use:          "tbunny",
+		Short:        "A fast, keyboard-driven terminal UI for managing RabbitMQ clusters",
+		Version:      version,
+		RunE:         run,
+		SilenceUsage: true,
 	}
 	logFilePath string
 	configDir   string
@@ -39,6 +41,8 @@ func initFlags() {
 	rootCmd.SetVersionTemplate("TBunny version {{.Version}}\n")
 	rootCmd.PersistentFlags().StringVar(&logFilePath, "log-file", "", "Specify the log file")
 	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", "", "Specify the configuration directory")
+
+	addCommands()
 }
 
 func main() {
@@ -48,28 +52,11 @@ func main() {
 }
 
 func run(*cobra.Command, []string) error {
-	var logHandler slog.Handler
-
-	if len(logFilePath) > 0 {
-		logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-		if err != nil {
-			return fmt.Errorf("failed to open log file %q: %w", logFilePath, err)
-		}
-
-		defer func() {
-			if logFile != nil {
-				_ = logFile.Close()
-			}
-		}()
-
-		logHandler = tint.NewHandler(logFile, &tint.Options{
-			Level:      slog.LevelDebug,
-			TimeFormat: time.TimeOnly,
-			NoColor:    true,
-		})
-	} else {
-		logHandler = slog.DiscardHandler
+	closeLog, err := initLogging()
+	if err != nil {
+		return err
 	}
+	defer closeLog()
 
 	var initialized bool
 
@@ -85,17 +72,14 @@ func run(*cobra.Command, []string) error {
 		}
 	}()
 
-	slog.SetDefault(slog.New(logHandler))
+	config.Init(configDir)
 
-	// Silence third-party loggers that would corrupt TUI output.
-	// klog.SetLogger with logr.Discard() covers all klog calls, including structured ErrorS/InfoS
-	// variants that klog.SetOutput alone does not intercept.
-	// stdlog.SetOutput silences stdlib log.Printf calls from HTTP/transport layers.
-	klog.SetLogger(logr.Discard())
-	stdlog.SetOutput(io.Discard)
+	if err := unlockCredentials(); err != nil {
+		return err
+	}
 
-	config.Init(configDir)
 	cluster.Init(config.RootDirectory())
+	publish.Init(config.RootDirectory())
 
 	activeClusterName := cluster.ActiveClusterName()
 	if activeClusterName != "" {
@@ -118,3 +102,41 @@ func run(*cobra.Command, []string) error {
 
 	return nil
 }
+
+// initLogging sets up the application logger and silences third-party
+// loggers. The returned function closes the log file, if any.
+func initLogging() (func(), error) {
+	var logHandler slog.Handler
+
+	closeLog := func() {}
+
+	if len(logFilePath) > 0 {
+		logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file %q: %w", logFilePath, err)
+		}
+
+		closeLog = func() {
+			_ = logFile.Close()
+		}
+
+		logHandler = tint.NewHandler(logFile, &tint.Options{
+			Level:      slog.LevelDebug,
+			TimeFormat: time.TimeOnly,
+			NoColor:    true,
+		})
+	} else {
+		logHandler = slog.DiscardHandler
+	}
+
+	slog.SetDefault(slog.New(logHandler))
+
+	// Silence third-party loggers that would corrupt TUI output.
+	// klog.SetLogger with logr.Discard() covers all klog calls, including structured ErrorS/InfoS
+	// variants that klog.SetOutput alone does not intercept.
+	// stdlog.SetOutput silences stdlib log.Printf calls from HTTP/transport layers.
+	klog.SetLogger(logr.Discard())
+	stdlog.SetOutput(io.Discard)
+
+	return closeLog, nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"tbunny/internal/cluster"
+	"tbunny/internal/rmq"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view/queues"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/spf13/cobra"
+)
+
+type messageGetOptions struct {
+	count    int
+	ackMode  string
+	encoding string
+}
+
+type messagePublishOptions struct {
+	exchange        string
+	routingKey      string
+	payload         string
+	file            string
+	payloadEncoding string
+	persistent      bool
+	headers         []string
+	properties      []string
+}
+
+// messageRow adds the payload to the columns of a message table.
+type messageRow struct {
+	*queues.MessageResource
+}
+
+func (r messageRow) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "redelivered":
+		return strconv.FormatBool(r.Redelivered)
+	case "payload":
+		return strings.ReplaceAll(r.Payload, "\n", "\\n")
+	default:
+		return r.MessageResource.GetTableColumnValue(columnName)
+	}
+}
+
+func newMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "message",
+		Short: "Get or publish messages",
+	}
+
+	var getOpts messageGetOptions
+
+	getCmd := &cobra.Command{
+		Use:   "get <queue>",
+		Short: "Get messages from a queue",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return withCluster(func(c *cluster.Cluster) error {
+				return getMessages(c, args[0], getOpts)
+			})
+		},
+	}
+
+	getCmd.Flags().IntVarP(&getOpts.count, "count", "n", 1, "Number of messages to get")
+	getCmd.Flags().StringVar(&getOpts.ackMode, "ack-mode", string(rmq.AckModeAckRequeueTrue), "Ack mode: ack_requeue_true, ack_requeue_false, reject_requeue_true or reject_requeue_false")
+	getCmd.Flags().StringVar(&getOpts.encoding, "encoding", string(rmq.RequestedMessageEncodingAuto), "Payload encoding: auto or base64")
+	addOutputFlag(getCmd)
+
+	var publishOpts messagePublishOptions
+
+	publishCmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Publish a message to an exchange",
+		Args:  cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return withCluster(func(c *cluster.Cluster) error {
+				return publishMessage(c, publishOpts)
+			})
+		},
+	}
+
+	publishCmd.Flags().StringVarP(&publishOpts.exchange, "exchange", "e", "", "Exchange to publish to, the default exchange if omitted")
+	publishCmd.Flags().StringVarP(&publishOpts.routingKey, "routing-key", "k", "", "Routing key")
+	publishCmd.Flags().StringVarP(&publishOpts.payload, "payload", "p", "", "Message payload")
+	publishCmd.Flags().StringVarP(&publishOpts.file, "file", "f", "", "Read the message payload from a file, - for standard input")
+	publishCmd.Flags().StringVar(&publishOpts.payloadEncoding, "payload-encoding", string(rmq.PayloadEncodingString), "Payload encoding: string or base64")
+	publishCmd.Flags().BoolVar(&publishOpts.persistent, "persistent", false, "Publish a persistent message")
+	publishCmd.Flags().StringArrayVarP(&publishOpts.headers, "header", "H", nil, "Message header as name=value, may be repeated")
+	publishCmd.Flags().StringArrayVar(&publishOpts.properties, "property", nil, "Message property as name=value, e.g. content_type=application/json, may be repeated")
+	publishCmd.MarkFlagsMutuallyExclusive("payload", "file")
+
+	cmd.AddCommand(getCmd, publishCmd)
+
+	addClusterFlags(cmd)
+
+	return cmd
+}
+
+func getMessages(c *cluster.Cluster, queue string, opts messageGetOptions) error {
+	vhost := targetVhost(c)
+
+	messages, err := c.GetQueueMessages(vhost, queue, rmq.AckMode(opts.ackMode), rmq.RequestedMessageEncoding(opts.encoding), opts.count)
+	if err != nil {
+		return fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	rows := utils.MapWithIndex(messages, func(idx int, m *rmq.FetchedMessage) messageRow {
+		return messageRow{queues.NewMessageResource(m, idx)}
+	})
+
+	return printOutput(messages, rows, func(wide bool) []ui.TableColumn {
+		c := []ui.TableColumn{
+			{Name: "index", Title: "IDX"},
+			{Name: "exchange", Title: "EXCHANGE"},
+			{Name: "routingKey", Title: "ROUTING KEY"},
+		}
+
+		if wide {
+			c = append(c, []ui.TableColumn{
+				{Name: "deliveryMode", Title: "MODE"},
+				{Name: "contentType", Title: "CONTENT TYPE"},
+				{Name: "redelivered", Title: "REDELIVERED"},
+			}...)
+		}
+
+		return append(c, []ui.TableColumn{
+			{Name: "length", Title: "LENGTH"},
+			{Name: "payload", Title: "PAYLOAD"},
+		}...)
+	})
+}
+
+func publishMessage(c *cluster.Cluster, opts messagePublishOptions) error {
+	payload := opts.payload
+
+	if opts.file != "" {
+		content, err := readPayloadFile(opts.file)
+		if err != nil {
+			return fmt.Errorf("failed to read payload: %w", err)
+		}
+
+		payload = string(content)
+	}
+
+	props, err := parseNameValues(opts.properties)
+	if err != nil {
+		return fmt.Errorf("invalid property: %w", err)
+	}
+
+	// The management API expects numeric properties as numbers.
+	for _, name := range []string{"priority", "timestamp"} {
+		if value, ok := props[name]; ok {
+			if props[name], err = strconv.Atoi(value.(string)); err != nil {
+				return fmt.Errorf("invalid property %s: %w", name, err)
+			}
+		}
+	}
+
+	headers, err := parseNameValues(opts.headers)
+	if err != nil {
+		return fmt.Errorf("invalid header: %w", err)
+	}
+
+	if len(headers) > 0 {
+		props["headers"] = headers
+	}
+
+	if opts.persistent {
+		props["delivery_mode"] = rmq.MessageDeliveryModePersistent
+	} else {
+		props["delivery_mode"] = rmq.MessageDeliveryModeNonPersistent
+	}
+
+	exchange := opts.exchange
+	if exchange == "" {
+		exchange = "amq.default"
+	}
+
+	res, err := c.PublishToExchange(targetVhost(c), exchange, rabbithole.PublishOptions{
+		RoutingKey:      opts.routingKey,
+		Properties:      props,
+		Payload:         payload,
+		PayloadEncoding: opts.payloadEncoding,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	if !res.Routed {
+		return fmt.Errorf("message published to %s but not routed to any queue", exchange)
+	}
+
+	fmt.Printf("Message published to %s\n", exchange)
+
+	return nil
+}
+
+func readPayloadFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+// parseNameValues parses name=value pairs into a map.
+func parseNameValues(pairs []string) (map[string]any, error) {
+	result := make(map[string]any, len(pairs))
+
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is not in name=value form", pair)
+		}
+
+		result[name] = value
+	}
+
+	return result, nil
+}
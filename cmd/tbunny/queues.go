@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"tbunny/internal/cluster"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view/queues"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/spf13/cobra"
+)
+
+func newQueuesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queues",
+		Short: "Inspect queues",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List queues",
+		Args:  cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return withCluster(listQueues)
+		},
+	})
+
+	addOutputFlag(cmd)
+	addClusterFlags(cmd)
+
+	return cmd
+}
+
+func newQueueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Operate on a single queue",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "purge <queue>",
+		Short: "Remove all ready messages from a queue",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return withCluster(func(c *cluster.Cluster) error {
+				return purgeQueue(c, args[0])
+			})
+		},
+	})
+
+	addClusterFlags(cmd)
+
+	return cmd
+}
+
+func listQueues(c *cluster.Cluster) error {
+	var list []rabbithole.QueueInfo
+	var err error
+
+	vhost := listVhost(c)
+	if vhost == "" {
+		list, err = c.ListQueues()
+	} else {
+		list, err = c.ListQueuesIn(vhost)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to list queues: %w", err)
+	}
+
+	rows := utils.Map(list, func(i rabbithole.QueueInfo) *queues.QueueResource {
+		return &queues.QueueResource{QueueInfo: i}
+	})
+
+	return printOutput(list, rows, func(wide bool) []ui.TableColumn {
+		return queues.Columns(wide, vhost == "")
+	})
+}
+
+func purgeQueue(c *cluster.Cluster, queue string) error {
+	vhost := targetVhost(c)
+
+	if _, err := c.PurgeQueue(vhost, queue); err != nil {
+		return fmt.Errorf("failed to purge queue: %w", err)
+	}
+
+	fmt.Printf("Queue %s purged\n", queue)
+
+	return nil
+}
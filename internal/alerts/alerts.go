@@ -0,0 +1,227 @@
+// Package alerts evaluates the alert rules of the configuration on resources,
+// e.g. "queues where consumers == 0 and messages > 0 are yellow".
+package alerts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"tbunny/internal/config"
+	"tbunny/internal/jsonpath"
+	"tbunny/internal/skins"
+)
+
+// Resource types alert rules apply to.
+const (
+	Queues      = "queues"
+	Nodes       = "nodes"
+	Connections = "connections"
+	VHosts      = "vhosts"
+)
+
+var resources = []string{Queues, Nodes, Connections, VHosts}
+
+// keywordRegexp splits conditions on the and/or keywords, outside of quoted strings.
+var keywordRegexp = regexp.MustCompile(`(?i)\s+(and|or)\s+`)
+
+// Rule is a compiled alert rule.
+type Rule struct {
+	name     string
+	resource string
+	color    skins.Color
+	// anyOf holds the alternatives separated by "or", each of them made of
+	// expressions separated by "and".
+	anyOf [][]*condition
+}
+
+// condition is an expression on the fields of resources, negated by "not".
+type condition struct {
+	expr   *jsonpath.Expression
+	negate bool
+}
+
+func (c *condition) matches(doc any) bool {
+	return c.expr.Match(doc) != c.negate
+}
+
+// Compile compiles the rules applying to a type of resources.
+func Compile(rules []config.AlertRule, resource string) ([]*Rule, error) {
+	var compiled []*Rule
+
+	for _, r := range rules {
+		if !isResource(r.Resource) {
+			return nil, fmt.Errorf("alert rule %s: unknown resource %q, expected one of %s", ruleName(r), r.Resource, strings.Join(resources, ", "))
+		}
+
+		if !strings.EqualFold(r.Resource, resource) {
+			continue
+		}
+
+		rule, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %s: %w", ruleName(r), err)
+		}
+
+		compiled = append(compiled, rule)
+	}
+
+	return compiled, nil
+}
+
+func compile(r config.AlertRule) (*Rule, error) {
+	if r.Color == "" {
+		return nil, fmt.Errorf("missing color")
+	}
+
+	rule := Rule{
+		name:     ruleName(r),
+		resource: strings.ToLower(r.Resource),
+		color:    skins.NewColor(r.Color),
+	}
+
+	for _, alternative := range splitKeyword(r.When, "or") {
+		var all []*condition
+
+		for _, text := range splitKeyword(alternative, "and") {
+			c, err := parseCondition(text)
+			if err != nil {
+				return nil, err
+			}
+
+			all = append(all, c)
+		}
+
+		rule.anyOf = append(rule.anyOf, all)
+	}
+
+	return &rule, nil
+}
+
+// parseCondition parses a comparison of a field of the resources with a
+// value, e.g. messages_ready > 10000 or message_stats.publish_details.rate > 100.
+// A field alone, e.g. mem_alarm, is true when its value is true. Preceded by
+// "not", it is true when its value is not true, or when the field is missing.
+func parseCondition(text string) (*condition, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("missing condition")
+	}
+
+	expr, err := jsonpath.Parse("$." + text)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.Operator() != jsonpath.OpExists {
+		return &condition{expr: expr}, nil
+	}
+
+	field, negate := strings.CutPrefix(text, "not ")
+
+	if expr, err = jsonpath.Parse("$." + strings.TrimSpace(field) + " == true"); err != nil {
+		return nil, err
+	}
+
+	return &condition{expr: expr, negate: negate}, nil
+}
+
+// splitKeyword splits s on a keyword surrounded by spaces, outside of quoted strings.
+func splitKeyword(s, keyword string) []string {
+	var parts []string
+
+	start := 0
+
+	for _, m := range keywordRegexp.FindAllStringSubmatchIndex(s, -1) {
+		if !strings.EqualFold(s[m[2]:m[3]], keyword) || isQuoted(s[:m[0]]) {
+			continue
+		}
+
+		parts = append(parts, s[start:m[0]])
+		start = m[1]
+	}
+
+	return append(parts, s[start:])
+}
+
+// isQuoted reports whether the end of s is inside a quoted string.
+func isQuoted(s string) bool {
+	var quote rune
+
+	for _, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		}
+	}
+
+	return quote != 0
+}
+
+func isResource(resource string) bool {
+	for _, r := range resources {
+		if strings.EqualFold(r, resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ruleName(r config.AlertRule) string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	return r.When
+}
+
+// Name returns the name of the rule, or its condition when it has none.
+func (r *Rule) Name() string {
+	return r.name
+}
+
+// Color returns the color of the rows of resources matching the rule.
+func (r *Rule) Color() skins.Color {
+	return r.color
+}
+
+// Matches reports whether a resource document, see Document, matches the rule.
+func (r *Rule) Matches(doc any) bool {
+	for _, all := range r.anyOf {
+		matches := true
+
+		for _, c := range all {
+			if !c.matches(doc) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Evaluate returns the first rule matching a resource, or nil.
+func Evaluate(rules []*Rule, resource any) *Rule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	doc := Document(resource)
+
+	for _, r := range rules {
+		if r.Matches(doc) {
+			return r
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package alerts
+
+import (
+	"tbunny/internal/config"
+	"testing"
+)
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		when string
+		doc  map[string]any
+		want bool
+	}{
+		{"messages_ready > 10", map[string]any{"messages_ready": 11.0}, true},
+		{"messages_ready > 10", map[string]any{"messages_ready": 10.0}, false},
+		{"messages_ready > 10", map[string]any{}, false},
+		{"mem_alarm", map[string]any{"mem_alarm": true}, true},
+		{"mem_alarm", map[string]any{"mem_alarm": false}, false},
+		{"mem_alarm", map[string]any{}, false},
+		{"not running", map[string]any{"running": true}, false},
+		{"not running", map[string]any{"running": false}, true},
+		{"not running", map[string]any{}, true},
+		{"mem_alarm or not running", map[string]any{"running": true}, false},
+		{"mem_alarm or not running", map[string]any{"mem_alarm": true, "running": true}, true},
+		{"consumers == 0 and messages > 0", map[string]any{"consumers": 0.0, "messages": 1.0}, true},
+		{"consumers == 0 and messages > 0", map[string]any{"consumers": 1.0, "messages": 1.0}, false},
+		{"state != 'running'", map[string]any{"state": "blocked"}, true},
+		{"name == 'a and b'", map[string]any{"name": "a and b"}, true},
+	}
+
+	for _, tt := range tests {
+		rules, err := Compile([]config.AlertRule{{Resource: Queues, When: tt.when, Color: "red"}}, Queues)
+		if err != nil {
+			t.Fatalf("Compile(%q) failed: %s", tt.when, err)
+		}
+
+		if got := rules[0].Matches(tt.doc); got != tt.want {
+			t.Errorf("%q matches %v = %t, want %t", tt.when, tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []config.AlertRule{
+		{Resource: "exchanges", When: "messages > 0", Color: "red"},
+		{Resource: Queues, When: "messages > 0"},
+		{Resource: Queues, When: "messages > 0 and ", Color: "red"},
+		{Resource: Queues, When: "messages >", Color: "red"},
+	}
+
+	for _, r := range tests {
+		if _, err := Compile([]config.AlertRule{r}, Queues); err == nil {
+			t.Errorf("Compile(%+v) succeeded, want an error", r)
+		}
+	}
+}
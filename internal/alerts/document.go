@@ -0,0 +1,100 @@
+package alerts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Document returns a resource, e.g. a rabbithole.QueueInfo, as a JSON-like
+// document whose members are named after the JSON fields of the management
+// API. Unlike a JSON round-trip, it keeps zero values of omitempty fields,
+// so that conditions such as consumers == 0 can be evaluated.
+func Document(resource any) any {
+	return document(reflect.ValueOf(resource))
+}
+
+func document(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return document(v.Elem())
+	case reflect.Struct:
+		doc := map[string]any{}
+		addFields(doc, v)
+
+		return doc
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+
+		doc := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			doc[it.Key().String()] = document(it.Value())
+		}
+
+		return doc
+	case reflect.Slice, reflect.Array:
+		values := make([]any, v.Len())
+		for i := range values {
+			values[i] = document(v.Index(i))
+		}
+
+		return values
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// addFields adds the exported fields of a struct to doc, with the fields of
+// embedded structs promoted as encoding/json does.
+func addFields(doc map[string]any, v reflect.Value) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		if f.Anonymous && name == "" {
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct {
+				addFields(doc, fv)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		doc[name] = document(fv)
+	}
+}
@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"tbunny/internal/config"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// defaultAmqpPort is used when the cluster does not report an AMQP listener.
+	defaultAmqpPort = 5672
+
+	// amqpHeartbeat is the heartbeat interval of AMQP connections.
+	amqpHeartbeat = 10 * time.Second
+
+	// amqpConnectionName is reported to the broker, so that the connection is
+	// recognizable in the connections list.
+	amqpConnectionName = "tbunny"
+)
+
+// ErrAmqpNotForwarded is returned when the AMQP port of a Kubernetes cluster
+// is not forwarded, because its Service doesn't expose it.
+var ErrAmqpNotForwarded = errors.New("AMQP port not forwarded")
+
+// DialAMQP opens an AMQP 0-9-1 connection to the given virtual host with the
+// credentials of the cluster.
+func (c *Cluster) DialAMQP(vhost string) (*amqp.Connection, error) {
+	uri, err := c.amqpUri()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mx.RLock()
+	username := c.config.Connection.Username
+	password := c.Password
+	c.mx.RUnlock()
+
+	// The OAuth 2 backend takes the access token as the password.
+	if c.bearer != nil {
+		token, err := c.bearer.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		password = token.AccessToken
+	}
+
+	cfg := amqp.Config{
+		Vhost:      vhost,
+		SASL:       []amqp.Authentication{&amqp.PlainAuth{Username: username, Password: password}},
+		Heartbeat:  amqpHeartbeat,
+		Locale:     "en_US",
+		Properties: amqp.NewConnectionProperties(),
+		Dial:       amqp.DefaultDial(config.Current().ConnectionTimeout),
+	}
+	cfg.Properties.SetClientConnectionName(amqpConnectionName)
+
+	if uri.Scheme == "amqps" {
+		cfg.TLSClientConfig = c.tlsConfig.Clone()
+		if cfg.TLSClientConfig.ServerName == "" {
+			cfg.TLSClientConfig.ServerName = uri.Hostname()
+		}
+	}
+
+	conn, err := amqp.DialConfig(uri.String(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", uri.Host, err)
+	}
+
+	return conn, nil
+}
+
+// amqpUri returns the AMQP endpoint of the connection or, if it is not known,
+// the management host with the port of the AMQP listener.
+func (c *Cluster) amqpUri() (*url.URL, error) {
+	if uri := c.connection.AmqpUri(); uri != "" {
+		return url.Parse(uri)
+	}
+
+	// The management host of a port-forward doesn't accept AMQP connections.
+	if _, ok := c.connection.(*k8sConnection); ok {
+		return nil, ErrAmqpNotForwarded
+	}
+
+	endpoint, err := url.Parse(c.connection.Uri())
+	if err != nil {
+		return nil, err
+	}
+
+	scheme, port := "amqp", defaultAmqpPort
+
+	overview, err := c.Overview()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover AMQP listener: %w", err)
+	}
+
+	for _, l := range overview.Listeners {
+		if l.Protocol == "amqp" {
+			port = int(l.Port)
+			break
+		}
+	}
+
+	// Prefer TLS when the management API is reached over HTTPS as well.
+	if endpoint.Scheme == "https" {
+		for _, l := range overview.Listeners {
+			if l.Protocol == "amqp/ssl" {
+				scheme, port = "amqps", int(l.Port)
+				break
+			}
+		}
+	}
+
+	return &url.URL{Scheme: scheme, Host: net.JoinHostPort(endpoint.Hostname(), strconv.Itoa(port))}, nil
+}
@@ -3,6 +3,7 @@ package cluster
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"tbunny/internal/config"
 	"tbunny/internal/rmq"
 	"tbunny/internal/sl"
 	"time"
@@ -45,8 +47,12 @@ type Cluster struct {
 	informationListeners       []InformationListener
 	connectionsListeners       []ConnectionListener
 	errorCount                 atomic.Int32
+	lastError                  error
 	pollChan                   chan struct{}
 	connection                 connection
+	tlsConfig                  *tls.Config
+	tlsState                   *tlsState
+	bearer                     *rmq.BearerTransport
 	mx                         sync.RWMutex
 }
 
@@ -57,6 +63,8 @@ type Information struct {
 	RabbitMQVersion   string
 	ErlangVersion     string
 	ManagementVersion string
+	// TLS describes the TLS connection to the management API, if any.
+	TLS string
 }
 
 const (
@@ -70,28 +78,64 @@ const (
 func NewCluster(ctx context.Context, cfg *Config) (c *Cluster, err error) {
 	var client *rmq.Client
 
+	password, err := cfg.password(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password: %w", err)
+	}
+
 	conn, err := cfg.Connection.createConnection(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	tlsConfig, err := conn.TLSParameters().tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &tlsState{}
+
+	var transport http.RoundTripper
+
 	uri := conn.Uri()
 	if strings.HasPrefix(strings.ToLower(uri), "https://") {
-		transport := &http.Transport{TLSClientConfig: &tls.Config{}}
-		client, err = rmq.NewTLSClient(uri, cfg.Connection.Username, cfg.Connection.Password, transport)
+		httpsConfig := tlsConfig.Clone()
+		state.watch(httpsConfig)
+
+		transport = &http.Transport{TLSClientConfig: httpsConfig}
+	}
+
+	var bearer *rmq.BearerTransport
+
+	if p := cfg.Connection.OAuth2; p != nil {
+		source, err := p.tokenSource(password, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		bearer = rmq.NewBearerTransport(source, transport)
+		transport = bearer
+	}
+
+	if transport != nil {
+		client, err = rmq.NewTLSClient(uri, cfg.Connection.Username, password, transport)
 	} else {
-		client, err = rmq.NewClient(uri, cfg.Connection.Username, cfg.Connection.Password)
+		client, err = rmq.NewClient(uri, cfg.Connection.Username, password)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	info, err := getClusterInfo(client, cfg)
+	info, err := getClusterInfo(client, cfg, state)
 	if err != nil {
 		return nil, err
 	}
 
+	if info.TLS != "" {
+		slog.Info("Connected over TLS", sl.Cluster, cfg.name, "tls", info.TLS)
+	}
+
 	vhosts, err := client.ListVhosts()
 	if err != nil {
 		return nil, err
@@ -109,6 +153,9 @@ func NewCluster(ctx context.Context, cfg *Config) (c *Cluster, err error) {
 	c = &Cluster{
 		Client:       client,
 		connection:   conn,
+		tlsConfig:    tlsConfig,
+		tlsState:     state,
+		bearer:       bearer,
 		config:       cfg,
 		info:         info,
 		virtualHosts: vhosts,
@@ -132,6 +179,14 @@ func (c *Cluster) IsAvailable() bool {
 	return c.errorCount.Load() == 0
 }
 
+// LastError returns the error of the last probe of the connection, if any.
+func (c *Cluster) LastError() error {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.lastError
+}
+
 func (c *Cluster) AddVirtualHostsListener(l VirtualHostsListener) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
@@ -234,6 +289,12 @@ func (c *Cluster) FavoriteVhosts() []string {
 	return c.config.FavoriteVhosts
 }
 
+// AlertRules returns the alert rules of config.yaml, overridden by the rules
+// of the cluster configuration with the same name.
+func (c *Cluster) AlertRules() []config.AlertRule {
+	return mergeAlertRules(config.Current().Alerts, c.config.Alerts)
+}
+
 func (c *Cluster) ActiveVirtualHost() string {
 	c.mx.RLock()
 	defer c.mx.RUnlock()
@@ -289,6 +350,12 @@ func (c *Cluster) Refresh() {
 	}
 }
 
+// Close releases the cluster connection. It is only meant for clusters
+// obtained with Open; the active cluster is closed when it gets replaced.
+func (c *Cluster) Close() {
+	c.stop()
+}
+
 func (c *Cluster) start() {
 	c.startPolling()
 }
@@ -344,12 +411,20 @@ func (c *Cluster) probeConnection() {
 	var vhosts []rabbithole.VhostInfo
 	var err error
 
-	info, err = getClusterInfo(c.Client, c.config)
+	info, err = getClusterInfo(c.Client, c.config, c.tlsState)
 	if err == nil {
 		vhosts, err = c.ListVhosts()
 	}
 
+	c.mx.Lock()
+	c.lastError = err
+	c.mx.Unlock()
+
 	if err != nil {
+		if errors.Is(err, rmq.ErrTokenRejected) {
+			slog.Warn("OAuth 2 token rejected", sl.Cluster, c.config.name, sl.Error, err)
+		}
+
 		if c.errorCount.Add(1) == connectionLostErrorsThreshold {
 			slog.Debug("Cluster connection lost", sl.Cluster, c.config.name)
 			c.notifyConnectionLost()
@@ -492,7 +567,7 @@ func isEqualVhosts(vhosts1 []rabbithole.VhostInfo, vhosts2 []rabbithole.VhostInf
 	return true
 }
 
-func getClusterInfo(client *rmq.Client, config *Config) (Information, error) {
+func getClusterInfo(client *rmq.Client, config *Config, tlsState *tlsState) (Information, error) {
 	clusterName, err := client.GetClusterName()
 	if err != nil {
 		return Information{}, err
@@ -510,7 +585,28 @@ func getClusterInfo(client *rmq.Client, config *Config) (Information, error) {
 		RabbitMQVersion:   overview.RabbitMQVersion,
 		ErlangVersion:     overview.ErlangVersion,
 		ManagementVersion: overview.ManagementVersion,
+		TLS:               tlsState.describe(),
 	}
 
 	return info, nil
 }
+
+func mergeAlertRules(global, cluster []config.AlertRule) []config.AlertRule {
+	rules := make([]config.AlertRule, 0, len(global)+len(cluster))
+
+	for _, r := range global {
+		overridden := r.Name != "" && slices.ContainsFunc(cluster, func(cr config.AlertRule) bool {
+			return cr.Name == r.Name
+		})
+
+		if !overridden {
+			rules = append(rules, r)
+		}
+	}
+
+	rules = append(rules, cluster...)
+
+	return slices.DeleteFunc(rules, func(r config.AlertRule) bool {
+		return r.Disabled
+	})
+}
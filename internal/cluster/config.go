@@ -2,8 +2,11 @@ package cluster
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"tbunny/internal/config"
+	"tbunny/internal/sl"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,6 +15,7 @@ type Config struct {
 	Connection     ConnectionParameters `yaml:"connection" json:"connection"`
 	Vhost          string               `yaml:"vhost" json:"vhost"`
 	FavoriteVhosts []string             `yaml:"favoriteVhosts" json:"favoriteVhosts"`
+	Alerts         []config.AlertRule   `yaml:"alerts,omitempty" json:"alerts,omitempty"`
 
 	name     string
 	fileName string
@@ -40,6 +44,15 @@ func (c *Config) save() error {
 	return nil
 }
 
+// migrate updates configurations of former versions and moves clear text
+// passwords to the password store, if one is configured. It must be called
+// once the name of the cluster is set.
 func (c *Config) migrate() {
 	c.Connection = c.Connection.migrate()
+
+	if c.movePasswordToStore() {
+		if err := c.save(); err != nil {
+			slog.Error("Failed to save migrated cluster config", sl.Cluster, c.name, sl.Error, err)
+		}
+	}
 }
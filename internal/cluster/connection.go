@@ -6,6 +6,12 @@ type connectionListener interface {
 
 type connection interface {
 	Uri() string
+	// AmqpUri returns the AMQP 0-9-1 URI (without credentials) or an empty
+	// string if it has to be discovered from the cluster listeners.
+	AmqpUri() string
+	// TLSParameters returns the TLS parameters of the connection, or nil for
+	// the default ones.
+	TLSParameters() *TLSParameters
 	AddListener(l connectionListener)
 	Close()
 }
@@ -10,17 +10,47 @@ type ConnectionParameters struct {
 	Direct   *DirectConnectionParameters `yaml:"direct,omitempty" json:"direct,omitempty"`
 	K8s      *K8sConnectionParameters    `yaml:"k8s,omitempty" json:"k8s,omitempty"`
 	Username string                      `yaml:"username" json:"username"`
-	Password string                      `yaml:"password" json:"password"`
+	// Password is kept in clear text, unless one of the sources below is used.
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	// PasswordEnv names the environment variable holding the password.
+	PasswordEnv string `yaml:"passwordEnv,omitempty" json:"passwordEnv,omitempty"`
+	// PasswordCommand is a shell command printing the password, e.g. pass show rmq/prod.
+	PasswordCommand string `yaml:"passwordCommand,omitempty" json:"passwordCommand,omitempty"`
+	// PasswordStore is the store the password is kept in: keyring or encrypted.
+	PasswordStore string `yaml:"passwordStore,omitempty" json:"passwordStore,omitempty"`
+	// OAuth2 replaces basic authentication with bearer tokens.
+	OAuth2 *OAuth2Parameters `yaml:"oauth2,omitempty" json:"oauth2,omitempty"`
 }
 
 type DirectConnectionParameters struct {
 	Uri string `yaml:"uri" json:"uri"`
+	// AmqpUri overrides the AMQP 0-9-1 endpoint, which is otherwise derived
+	// from the management URI host and the cluster listeners.
+	AmqpUri string `yaml:"amqpUri,omitempty" json:"amqpUri,omitempty"`
+	// TLS configures https:// and amqps:// connections.
+	TLS *TLSParameters `yaml:"tls,omitempty" json:"tls,omitempty"`
 }
 
 type K8sConnectionParameters struct {
 	Context   string `yaml:"context" json:"context"`
 	Namespace string `yaml:"namespace" json:"namespace"`
 	Name      string `yaml:"name" json:"name"`
+	// Selector selects the pods of the instance, instead of the labels
+	// app.kubernetes.io/instance=rabbitmq and app.kubernetes.io/name=<name>.
+	Selector string `yaml:"selector,omitempty" json:"selector,omitempty"`
+	// Service forwards to the pods behind a Service, whose ports are then
+	// service ports.
+	Service string `yaml:"service,omitempty" json:"service,omitempty"`
+	// Port is the management port, 15672 by default or 15671 for TLS.
+	Port int `yaml:"port,omitempty" json:"port,omitempty"`
+	// AmqpPort is the AMQP 0-9-1 port, 5672 by default or 5671 for TLS.
+	AmqpPort int `yaml:"amqpPort,omitempty" json:"amqpPort,omitempty"`
+	// PodPolicy chooses among the Ready pods: first-ready, ordinal or round-robin.
+	PodPolicy string `yaml:"podPolicy,omitempty" json:"podPolicy,omitempty"`
+	// Ordinal is the ordinal of the pod of the ordinal policy, e.g. 1 for rabbitmq-1.
+	Ordinal int `yaml:"ordinal,omitempty" json:"ordinal,omitempty"`
+	// TLS configures the forwarded TLS ports.
+	TLS *TLSParameters `yaml:"tls,omitempty" json:"tls,omitempty"`
 }
 
 func (p ConnectionParameters) String() string {
@@ -38,6 +68,10 @@ func (p *DirectConnectionParameters) String() string {
 }
 
 func (p *K8sConnectionParameters) String() string {
+	if p.Service != "" {
+		return fmt.Sprintf("K8s connection, context %s, namespace %s, service %s", p.Context, p.Namespace, p.Service)
+	}
+
 	return fmt.Sprintf("K8s connection, context %s, namespace %s, instance %s", p.Context, p.Namespace, p.Name)
 }
 
@@ -57,8 +91,12 @@ func (p ConnectionParameters) migrate() ConnectionParameters {
 			Direct: &DirectConnectionParameters{
 				Uri: p.Uri,
 			},
-			Username: p.Username,
-			Password: p.Password,
+			Username:        p.Username,
+			Password:        p.Password,
+			PasswordEnv:     p.PasswordEnv,
+			PasswordCommand: p.PasswordCommand,
+			PasswordStore:   p.PasswordStore,
+			OAuth2:          p.OAuth2,
 		}
 	}
 
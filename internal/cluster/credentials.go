@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"tbunny/internal/config"
+	"tbunny/internal/credentials"
+	"tbunny/internal/sl"
+)
+
+// password returns the password of the cluster from its source: an
+// environment variable, a command, a password store or the cluster file.
+func (c *Config) password(ctx context.Context) (string, error) {
+	p := c.Connection
+
+	switch {
+	case p.PasswordEnv != "":
+		return credentials.FromEnv(p.PasswordEnv)
+	case p.PasswordCommand != "":
+		return credentials.FromCommand(ctx, p.PasswordCommand)
+	case p.PasswordStore != "":
+		store, err := credentials.Open(p.PasswordStore)
+		if err != nil {
+			return "", err
+		}
+
+		password, err := store.Get(c.name)
+		if errors.Is(err, credentials.ErrNotFound) {
+			return "", fmt.Errorf("no password of cluster %s in the %s store", c.name, p.PasswordStore)
+		}
+
+		return password, err
+	default:
+		return p.Password, nil
+	}
+}
+
+// movePasswordToStore moves the clear text password of the cluster to the
+// store configured in config.yaml, if any. It returns true if the password
+// was moved.
+func (c *Config) movePasswordToStore() bool {
+	p := &c.Connection
+
+	storeName := config.Current().Credentials.Store
+	if storeName == "" || p.Password == "" || p.PasswordEnv != "" || p.PasswordCommand != "" || p.PasswordStore != "" {
+		return false
+	}
+
+	store, err := credentials.Open(storeName)
+	if err == nil {
+		err = store.Set(c.name, p.Password)
+	}
+
+	if err != nil {
+		slog.Error("Failed to move password to store", sl.Cluster, c.name, sl.Error, err)
+		return false
+	}
+
+	p.Password = ""
+	p.PasswordStore = storeName
+
+	slog.Info("Moved password to store", sl.Cluster, c.name, "store", storeName)
+
+	return true
+}
+
+// deletePasswordFromStore removes the password of a deleted cluster from its store.
+func (c *Config) deletePasswordFromStore() {
+	if c.Connection.PasswordStore == "" {
+		return
+	}
+
+	store, err := credentials.Open(c.Connection.PasswordStore)
+	if err == nil {
+		err = store.Delete(c.name)
+	}
+
+	if err != nil {
+		slog.Error("Failed to delete password from store", sl.Cluster, c.name, sl.Error, err)
+	}
+}
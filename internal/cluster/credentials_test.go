@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"tbunny/internal/config"
+	"tbunny/internal/credentials"
+	"testing"
+)
+
+// initEncryptedStore configures the encrypted store as the store passwords
+// are moved to, in a temporary directory.
+func initEncryptedStore(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("credentials:\n  store: encrypted\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config.Init(dir)
+	credentials.Init(dir)
+
+	if err := credentials.Unlock("master"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMovePasswordToStore(t *testing.T) {
+	initEncryptedStore(t)
+
+	c := &Config{name: "prod", Connection: ConnectionParameters{Username: "admin", Password: "s3cret"}}
+
+	if !c.movePasswordToStore() {
+		t.Fatal("movePasswordToStore() = false, want the password moved")
+	}
+
+	if c.Connection.Password != "" || c.Connection.PasswordStore != credentials.EncryptedStore {
+		t.Errorf("password = %q in store %q, want none in the encrypted store", c.Connection.Password, c.Connection.PasswordStore)
+	}
+
+	if password, err := c.password(context.Background()); err != nil || password != "s3cret" {
+		t.Errorf("password() = %q, %v, want s3cret", password, err)
+	}
+}
+
+func TestMovePasswordToStoreFromEnv(t *testing.T) {
+	initEncryptedStore(t)
+	t.Setenv("TBUNNY_TEST_PASSWORD", "from-env")
+
+	c := &Config{name: "dev", Connection: ConnectionParameters{Password: "s3cret", PasswordEnv: "TBUNNY_TEST_PASSWORD"}}
+
+	if c.movePasswordToStore() {
+		t.Fatal("movePasswordToStore() = true, want a password read from the environment left alone")
+	}
+
+	if c.Connection.Password != "s3cret" || c.Connection.PasswordStore != "" {
+		t.Errorf("password = %q in store %q, want it unchanged", c.Connection.Password, c.Connection.PasswordStore)
+	}
+
+	if password, err := c.password(context.Background()); err != nil || password != "from-env" {
+		t.Errorf("password() = %q, %v, want from-env", password, err)
+	}
+}
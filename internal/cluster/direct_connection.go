@@ -12,6 +12,14 @@ func (c *directConnection) Uri() string {
 	return c.parameters.Uri
 }
 
+func (c *directConnection) AmqpUri() string {
+	return c.parameters.AmqpUri
+}
+
+func (c *directConnection) TLSParameters() *TLSParameters {
+	return c.parameters.TLS
+}
+
 func (c *directConnection) AddListener(connectionListener) {}
 
 func (c *directConnection) Close() {}
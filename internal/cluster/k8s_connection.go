@@ -7,19 +7,18 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
-	"sort"
 	"sync"
 	"tbunny/internal/config"
 	"tbunny/internal/sl"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/transport/spdy"
 )
@@ -30,8 +29,12 @@ type k8sConnection struct {
 	clientSet  *kubernetes.Clientset
 
 	uri       string
+	amqpUri   string
 	listeners []connectionListener
 
+	// attempts counts the port-forward sessions, for the round-robin pod policy.
+	attempts int
+
 	mx     sync.RWMutex
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -39,6 +42,7 @@ type k8sConnection struct {
 
 type portForwardSession struct {
 	uri      string
+	amqpUri  string
 	stopChan chan struct{}
 	done     <-chan error
 }
@@ -52,22 +56,15 @@ const (
 )
 
 func newK8sConnection(ctx context.Context, params *K8sConnectionParameters) (*k8sConnection, error) {
-	slog.Info(fmt.Sprintf("Creating k8s connection for context %s, namespace %s, instance %s", params.Context, params.Namespace, params.Name))
-
-	kubeConfigFile := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-	loadingRules := &clientcmd.ClientConfigLoadingRules{
-		ExplicitPath: kubeConfigFile,
-	}
+	slog.Info(fmt.Sprintf("Creating k8s connection for context %s, %s", params.Context, params.target()))
 
-	configOverrides := &clientcmd.ConfigOverrides{
-		CurrentContext: params.Context,
+	if err := params.validate(); err != nil {
+		return nil, err
 	}
 
-	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-
-	restConfig, err := clientConfig.ClientConfig()
+	restConfig, err := k8sRestConfig(params.Context)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create k8s client config: %w", err)
+		return nil, err
 	}
 
 	clientSet, err := kubernetes.NewForConfig(restConfig)
@@ -101,6 +98,28 @@ func newK8sConnection(ctx context.Context, params *K8sConnectionParameters) (*k8
 	}
 }
 
+// LoadKubeConfig loads the kubeconfig files listed in KUBECONFIG, merged, or
+// ~/.kube/config.
+func LoadKubeConfig() (*api.Config, error) {
+	return clientcmd.NewDefaultClientConfigLoadingRules().Load()
+}
+
+// k8sRestConfig returns the client configuration of a kubeconfig context.
+func k8sRestConfig(context string) (*rest.Config, error) {
+	configOverrides := &clientcmd.ConfigOverrides{
+		CurrentContext: context,
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), configOverrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s client config: %w", err)
+	}
+
+	return restConfig, nil
+}
+
 func (c *k8sConnection) Uri() string {
 	c.mx.RLock()
 	defer c.mx.RUnlock()
@@ -108,6 +127,34 @@ func (c *k8sConnection) Uri() string {
 	return c.uri
 }
 
+func (c *k8sConnection) AmqpUri() string {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.amqpUri
+}
+
+// TLSParameters returns the TLS parameters of the connection. As the ports
+// are forwarded to 127.0.0.1, the server name defaults to the host of the
+// Service in the cluster, which broker certificates usually cover.
+func (c *k8sConnection) TLSParameters() *TLSParameters {
+	var p TLSParameters
+	if c.parameters.TLS != nil {
+		p = *c.parameters.TLS
+	}
+
+	if p.ServerName == "" {
+		service := c.parameters.Service
+		if service == "" {
+			service = c.parameters.Name
+		}
+
+		p.ServerName = fmt.Sprintf("%s.%s.svc", service, c.parameters.Namespace)
+	}
+
+	return &p
+}
+
 func (c *k8sConnection) Close() {
 	slog.Info("Closing k8s connection")
 	c.cancel()
@@ -131,39 +178,86 @@ func (c *k8sConnection) notifyConnectionUriChanged(uri string) {
 	}
 }
 
-// findPod resolves the name of the first (alphabetically) pod for the RabbitMQ instance.
-// Called on every connection attempt so that pod restarts are handled transparently.
-func (c *k8sConnection) findPod() (string, error) {
-	labelSelector := map[string]string{
-		"app.kubernetes.io/instance": "rabbitmq",
-		"app.kubernetes.io/name":     c.parameters.Name,
+// findPod resolves the pod to forward to, among the pods of the RabbitMQ
+// instance or of the Service, and the Service if any. Called on every
+// connection attempt so that pod restarts are handled transparently.
+func (c *k8sConnection) findPod() (*corev1.Pod, *corev1.Service, error) {
+	var svc *corev1.Service
+
+	labelSelector := c.parameters.Selector
+	if c.parameters.Service != "" {
+		var err error
+
+		svc, err = c.clientSet.CoreV1().
+			Services(c.parameters.Namespace).
+			Get(c.ctx, c.parameters.Service, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get service: %w", err)
+		}
+
+		if len(svc.Spec.Selector) == 0 {
+			return nil, nil, fmt.Errorf("service %s has no selector", svc.Name)
+		}
+
+		labelSelector = labels.Set(svc.Spec.Selector).String()
+	} else if labelSelector == "" {
+		labelSelector = labels.Set{
+			"app.kubernetes.io/instance": "rabbitmq",
+			"app.kubernetes.io/name":     c.parameters.Name,
+		}.String()
 	}
 
 	pods, err := c.clientSet.CoreV1().
 		Pods(c.parameters.Namespace).
 		List(c.ctx, metav1.ListOptions{
-			LabelSelector: labels.Set(labelSelector).String(),
+			LabelSelector: labelSelector,
 		})
 	if err != nil {
-		return "", fmt.Errorf("failed to list pods: %w", err)
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
 	}
 
-	if len(pods.Items) == 0 {
-		return "", fmt.Errorf("no pods found for RabbitMQ instance %s in namespace %s", c.parameters.Name, c.parameters.Namespace)
+	pod, err := c.parameters.choosePod(pods.Items, c.attempts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.attempts++
+
+	return pod, svc, nil
+}
+
+// remotePorts returns the management and AMQP ports of the pod, translated
+// from the ports of the Service if any. The AMQP port is only needed to tail,
+// publish and requeue messages: it is 0 when the Service doesn't expose it.
+func (c *k8sConnection) remotePorts(pod *corev1.Pod, svc *corev1.Service) (int, int, error) {
+	port, amqpPort := c.parameters.port(), c.parameters.amqpPort()
+	if svc == nil {
+		return port, amqpPort, nil
+	}
+
+	port, err := containerPort(svc, pod, port)
+	if err != nil {
+		return 0, 0, err
 	}
 
-	// Sorting pods by name to hit rabbitmq-0
-	sort.Slice(pods.Items, func(i, j int) bool {
-		return pods.Items[i].Name < pods.Items[j].Name
-	})
+	amqpPort, err = containerPort(svc, pod, amqpPort)
+	if err != nil {
+		slog.Warn("AMQP port not forwarded", sl.Error, err)
+		return port, 0, nil
+	}
 
-	return pods.Items[0].Name, nil
+	return port, amqpPort, nil
 }
 
 // startSession resolves the current pod, then creates a port-forward session, and waits until it is ready.
 // The caller is responsible for closing session.stopChan when done.
 func (c *k8sConnection) startSession() (*portForwardSession, error) {
-	podName, err := c.findPod()
+	pod, svc, err := c.findPod()
+	if err != nil {
+		return nil, err
+	}
+
+	port, amqpPort, err := c.remotePorts(pod, svc)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +267,7 @@ func (c *k8sConnection) startSession() (*portForwardSession, error) {
 		return nil, fmt.Errorf("failed to create spdy round tripper: %w", err)
 	}
 
-	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/portforward", c.parameters.Namespace, podName)
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/portforward", c.parameters.Namespace, pod.Name)
 	serverURL, err := url.Parse(c.restConfig.Host + path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse server URL: %w", err)
@@ -186,7 +280,13 @@ func (c *k8sConnection) startSession() (*portForwardSession, error) {
 
 	// io.Discard suppresses port forward's own stdout/stderr output.
 	// Port 0 lets the OS assign a free local port on each reconnection.
-	forwarder, err := portforward.New(dialer, []string{"0:15672"}, stopChan, readyChan, io.Discard, io.Discard)
+	// The AMQP port is forwarded as well, if any, for live message tailing.
+	ports := []string{fmt.Sprintf("0:%d", port)}
+	if amqpPort != 0 {
+		ports = append(ports, fmt.Sprintf("0:%d", amqpPort))
+	}
+
+	forwarder, err := portforward.New(dialer, ports, stopChan, readyChan, io.Discard, io.Discard)
 	if err != nil {
 		close(stopChan)
 		return nil, fmt.Errorf("failed to create port-forward: %w", err)
@@ -216,11 +316,28 @@ func (c *k8sConnection) startSession() (*portForwardSession, error) {
 		return nil, fmt.Errorf("failed to get forwarded ports: %w", err)
 	}
 
-	return &portForwardSession{
-		uri:      fmt.Sprintf("http://127.0.0.1:%d", forwardedPorts[0].Local),
+	scheme, amqpScheme := "http", "amqp"
+	if c.parameters.usesTLS() {
+		scheme = "https"
+	}
+
+	if c.parameters.amqpPort() == tlsK8sAmqpPort {
+		amqpScheme = "amqps"
+	}
+
+	slog.Info("Forwarding to pod", "pod", pod.Name, "port", port, "amqpPort", amqpPort)
+
+	session := portForwardSession{
+		uri:      fmt.Sprintf("%s://127.0.0.1:%d", scheme, forwardedPorts[0].Local),
 		stopChan: stopChan,
 		done:     done,
-	}, nil
+	}
+
+	if len(forwardedPorts) > 1 {
+		session.amqpUri = fmt.Sprintf("%s://127.0.0.1:%d", amqpScheme, forwardedPorts[1].Local)
+	}
+
+	return &session, nil
 }
 
 // keepAlive monitors the active port-forward session and reconnects on failure.
@@ -252,6 +369,7 @@ func (c *k8sConnection) keepAlive(firstReady chan<- error) {
 
 		c.mx.Lock()
 		c.uri = session.uri
+		c.amqpUri = session.amqpUri
 		c.mx.Unlock()
 
 		c.notifyConnectionUriChanged(session.uri)
@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestRemotePorts(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-server-0"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Ports: []corev1.ContainerPort{{Name: "management", ContainerPort: 15672}, {Name: "amqp", ContainerPort: 5672}},
+		}}},
+	}
+
+	service := func(ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "orders"}, Spec: corev1.ServiceSpec{Ports: ports}}
+	}
+
+	tests := []struct {
+		name         string
+		svc          *corev1.Service
+		wantPort     int
+		wantAmqpPort int
+		wantErr      bool
+	}{
+		{"no service", nil, 15672, 5672, false},
+		{
+			"named target ports",
+			service(
+				corev1.ServicePort{Port: 15672, TargetPort: intstr.FromString("management")},
+				corev1.ServicePort{Port: 5672, TargetPort: intstr.FromString("amqp")},
+			),
+			15672, 5672, false,
+		},
+		{
+			"management only",
+			service(corev1.ServicePort{Port: 15672, TargetPort: intstr.FromInt32(15672)}),
+			15672, 0, false,
+		},
+		{
+			"no management port",
+			service(corev1.ServicePort{Port: 5672}),
+			0, 0, true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &k8sConnection{parameters: &K8sConnectionParameters{}}
+
+			port, amqpPort, err := c.remotePorts(pod, tt.svc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("remotePorts() error = %v, want error %t", err, tt.wantErr)
+			}
+
+			if port != tt.wantPort || amqpPort != tt.wantAmqpPort {
+				t.Errorf("remotePorts() = %d, %d, want %d, %d", port, amqpPort, tt.wantPort, tt.wantAmqpPort)
+			}
+		})
+	}
+}
+
+func TestDialAMQPWithoutForwardedPort(t *testing.T) {
+	c := &Cluster{connection: &k8sConnection{uri: "http://127.0.0.1:40000"}}
+
+	if _, err := c.DialAMQP("/"); !errors.Is(err, ErrAmqpNotForwarded) {
+		t.Errorf("DialAMQP() error = %v, want ErrAmqpNotForwarded", err)
+	}
+}
@@ -0,0 +1,291 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"tbunny/internal/sl"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Kinds of the discovered RabbitMQ instances.
+const (
+	RabbitmqClusterKind = "RabbitmqCluster"
+	StatefulSetKind     = "StatefulSet"
+)
+
+var (
+	rabbitmqClustersResource = schema.GroupVersionResource{Group: "rabbitmq.com", Version: "v1beta1", Resource: "rabbitmqclusters"}
+	statefulSetsResource     = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+	secretsResource          = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+// statefulSetSelectors select the StatefulSets of the Bitnami Helm chart and
+// of older charts.
+var statefulSetSelectors = []string{
+	"app.kubernetes.io/name=rabbitmq",
+	"app=rabbitmq",
+}
+
+// DiscoveredInstance is a RabbitMQ instance found in a Kubernetes cluster,
+// with the credentials of its default user when they could be read.
+type DiscoveredInstance struct {
+	Kind       string
+	Parameters K8sConnectionParameters
+	Username   string
+	Password   string
+}
+
+func (i *DiscoveredInstance) String() string {
+	return fmt.Sprintf("%s/%s (%s)", i.Parameters.Namespace, i.Parameters.Name, i.Kind)
+}
+
+// DiscoverK8sInstances lists the RabbitmqCluster resources of the RabbitMQ
+// cluster operator and the StatefulSets of Helm charts across the namespaces
+// of a kubeconfig context.
+func DiscoverK8sInstances(ctx context.Context, k8sContext string) ([]DiscoveredInstance, error) {
+	restConfig, err := k8sRestConfig(k8sContext)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s client: %w", err)
+	}
+
+	return discoverK8sInstances(ctx, client, k8sContext)
+}
+
+func discoverK8sInstances(ctx context.Context, client dynamic.Interface, k8sContext string) ([]DiscoveredInstance, error) {
+	instances, err := discoverRabbitmqClusters(ctx, client, k8sContext)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSets, err := discoverStatefulSets(ctx, client, k8sContext)
+	if err != nil {
+		return nil, err
+	}
+
+	instances = append(instances, statefulSets...)
+
+	slices.SortFunc(instances, func(a, b DiscoveredInstance) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return instances, nil
+}
+
+// discoverRabbitmqClusters lists the clusters of the operator, reached
+// through their Service, with the credentials of their default user Secret.
+func discoverRabbitmqClusters(ctx context.Context, client dynamic.Interface, k8sContext string) ([]DiscoveredInstance, error) {
+	list, err := client.Resource(rabbitmqClustersResource).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		// The operator is not installed.
+		return nil, nil
+	} else if apierrors.IsForbidden(err) {
+		slog.Warn("Not allowed to list RabbitMQ clusters", sl.Error, err)
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list RabbitMQ clusters: %w", err)
+	}
+
+	instances := make([]DiscoveredInstance, 0, len(list.Items))
+
+	for _, item := range list.Items {
+		instance := DiscoveredInstance{
+			Kind: RabbitmqClusterKind,
+			Parameters: K8sConnectionParameters{
+				Context:   k8sContext,
+				Namespace: item.GetNamespace(),
+				Name:      item.GetName(),
+				Service:   item.GetName(),
+			},
+		}
+
+		if service, _, _ := unstructured.NestedString(item.Object, "status", "defaultUser", "serviceReference", "name"); service != "" {
+			instance.Parameters.Service = service
+		}
+
+		if tlsOnly, _, _ := unstructured.NestedBool(item.Object, "spec", "tls", "disableNonTLSListeners"); tlsOnly {
+			instance.Parameters.Port = tlsK8sPort
+			instance.Parameters.AmqpPort = tlsK8sAmqpPort
+		}
+
+		secretName := nestedStringOr(item.Object, item.GetName()+"-default-user", "status", "defaultUser", "secretReference", "name")
+		usernameKey := nestedStringOr(item.Object, "username", "status", "defaultUser", "secretReference", "keys", "username")
+		passwordKey := nestedStringOr(item.Object, "password", "status", "defaultUser", "secretReference", "keys", "password")
+
+		secret, err := getSecret(ctx, client, item.GetNamespace(), secretName)
+		if err != nil {
+			slog.Warn("Failed to read default user of RabbitMQ cluster", "cluster", item.GetName(), sl.Error, err)
+		} else {
+			instance.Username = string(secret.Data[usernameKey])
+			instance.Password = string(secret.Data[passwordKey])
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// discoverStatefulSets lists the StatefulSets of Helm charts, reached
+// through the pods they select, with the credentials of their containers.
+func discoverStatefulSets(ctx context.Context, client dynamic.Interface, k8sContext string) ([]DiscoveredInstance, error) {
+	var instances []DiscoveredInstance
+
+	seen := map[string]bool{}
+
+	for _, selector := range statefulSetSelectors {
+		list, err := client.Resource(statefulSetsResource).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if apierrors.IsForbidden(err) {
+			slog.Warn("Not allowed to list stateful sets", sl.Error, err)
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to list stateful sets: %w", err)
+		}
+
+		for _, item := range list.Items {
+			var sts appsv1.StatefulSet
+			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &sts); err != nil {
+				return nil, fmt.Errorf("failed to read stateful set %s: %w", item.GetName(), err)
+			}
+
+			key := sts.Namespace + "/" + sts.Name
+			if seen[key] || isOwnedByRabbitmqCluster(&sts) || sts.Spec.Selector == nil {
+				continue
+			}
+
+			seen[key] = true
+
+			instance := DiscoveredInstance{
+				Kind: StatefulSetKind,
+				Parameters: K8sConnectionParameters{
+					Context:   k8sContext,
+					Namespace: sts.Namespace,
+					Name:      sts.Name,
+					Selector:  labels.Set(sts.Spec.Selector.MatchLabels).String(),
+				},
+			}
+
+			if release := sts.Labels["app.kubernetes.io/instance"]; release != "" {
+				instance.Parameters.Name = release
+			}
+
+			instance.Parameters.Port, instance.Parameters.AmqpPort = statefulSetPorts(&sts)
+			instance.Username, instance.Password = statefulSetCredentials(ctx, client, &sts)
+
+			instances = append(instances, instance)
+		}
+	}
+
+	return instances, nil
+}
+
+func isOwnedByRabbitmqCluster(sts *appsv1.StatefulSet) bool {
+	return slices.ContainsFunc(sts.OwnerReferences, func(ref metav1.OwnerReference) bool {
+		return ref.Kind == RabbitmqClusterKind
+	})
+}
+
+// statefulSetPorts returns the TLS ports when the containers only expose them,
+// or zero for the default ports.
+func statefulSetPorts(sts *appsv1.StatefulSet) (int, int) {
+	ports := map[int32]bool{}
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		for _, p := range c.Ports {
+			ports[p.ContainerPort] = true
+		}
+	}
+
+	var port, amqpPort int
+	if !ports[defaultK8sPort] && ports[tlsK8sPort] {
+		port = tlsK8sPort
+	}
+
+	if !ports[defaultK8sAmqpPort] && ports[tlsK8sAmqpPort] {
+		amqpPort = tlsK8sAmqpPort
+	}
+
+	return port, amqpPort
+}
+
+// statefulSetCredentials returns the default user set in the environment of
+// the containers, by the Bitnami chart or the official image, reading the
+// referenced Secrets. The password falls back to the Secret of the Bitnami
+// chart, which is mounted as a file by its recent versions.
+func statefulSetCredentials(ctx context.Context, client dynamic.Interface, sts *appsv1.StatefulSet) (string, string) {
+	var username, password string
+
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		for _, env := range c.Env {
+			switch env.Name {
+			case "RABBITMQ_USERNAME", "RABBITMQ_DEFAULT_USER":
+				username = envValue(ctx, client, sts.Namespace, env)
+			case "RABBITMQ_PASSWORD", "RABBITMQ_DEFAULT_PASS":
+				password = envValue(ctx, client, sts.Namespace, env)
+			}
+		}
+	}
+
+	if password == "" {
+		if secret, err := getSecret(ctx, client, sts.Namespace, sts.Name); err == nil {
+			password = string(secret.Data["rabbitmq-password"])
+		}
+	}
+
+	return username, password
+}
+
+// envValue returns the value of an environment variable, reading the Secret
+// it refers to if any.
+func envValue(ctx context.Context, client dynamic.Interface, namespace string, env corev1.EnvVar) string {
+	if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+		return env.Value
+	}
+
+	ref := env.ValueFrom.SecretKeyRef
+
+	secret, err := getSecret(ctx, client, namespace, ref.Name)
+	if err != nil {
+		slog.Warn("Failed to read secret of stateful set", "secret", ref.Name, sl.Error, err)
+		return ""
+	}
+
+	return string(secret.Data[ref.Key])
+}
+
+func getSecret(ctx context.Context, client dynamic.Interface, namespace, name string) (*corev1.Secret, error) {
+	item, err := client.Resource(secretsResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var secret corev1.Secret
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &secret); err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+
+	return &secret, nil
+}
+
+func nestedStringOr(obj map[string]any, defaultValue string, fields ...string) string {
+	if value, _, _ := unstructured.NestedString(obj, fields...); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
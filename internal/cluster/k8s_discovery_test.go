@@ -0,0 +1,230 @@
+package cluster
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFakeDynamicClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		rabbitmqClustersResource: "RabbitmqClusterList",
+		statefulSetsResource:     "StatefulSetList",
+		secretsResource:          "SecretList",
+	}, objects...)
+}
+
+func rabbitmqCluster(namespace, name string, spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "rabbitmq.com/v1beta1",
+		"kind":       RabbitmqClusterKind,
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+func secret(namespace, name string, data map[string]string) *unstructured.Unstructured {
+	encoded := map[string]any{}
+	for k, v := range data {
+		encoded[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+		"data":       encoded,
+	}}
+}
+
+func bitnamiStatefulSet(namespace, name, release string, owners ...any) *unstructured.Unstructured {
+	selector := map[string]any{
+		"app.kubernetes.io/name":     "rabbitmq",
+		"app.kubernetes.io/instance": release,
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       StatefulSetKind,
+		"metadata": map[string]any{
+			"name":            name,
+			"namespace":       namespace,
+			"labels":          selector,
+			"ownerReferences": owners,
+		},
+		"spec": map[string]any{
+			"selector": map[string]any{"matchLabels": selector},
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{
+							"name": "rabbitmq",
+							"ports": []any{
+								map[string]any{"name": "amqp", "containerPort": int64(5672)},
+								map[string]any{"name": "http-stats", "containerPort": int64(15672)},
+							},
+							"env": []any{
+								map[string]any{"name": "RABBITMQ_USERNAME", "value": "user"},
+								map[string]any{
+									"name": "RABBITMQ_PASSWORD",
+									"valueFrom": map[string]any{
+										"secretKeyRef": map[string]any{"name": name, "key": "rabbitmq-password"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func discoverByName(t *testing.T, client *fake.FakeDynamicClient) map[string]DiscoveredInstance {
+	t.Helper()
+
+	instances, err := discoverK8sInstances(context.Background(), client, "test")
+	if err != nil {
+		t.Fatalf("discoverK8sInstances() failed: %s", err)
+	}
+
+	byName := map[string]DiscoveredInstance{}
+	for _, instance := range instances {
+		if instance.Parameters.Context != "test" {
+			t.Errorf("%s: context = %q, want test", instance.String(), instance.Parameters.Context)
+		}
+
+		byName[instance.String()] = instance
+	}
+
+	return byName
+}
+
+func TestDiscoverRabbitmqClusters(t *testing.T) {
+	client := newFakeDynamicClient(
+		rabbitmqCluster("prod", "orders", map[string]any{}),
+		secret("prod", "orders-default-user", map[string]string{"username": "default_user_abc", "password": "s3cret"}),
+		rabbitmqCluster("prod", "payments", map[string]any{"tls": map[string]any{"secretName": "tls", "disableNonTLSListeners": true}}),
+	)
+
+	instances := discoverByName(t, client)
+	if len(instances) != 2 {
+		t.Fatalf("discovered %d instances, want 2: %v", len(instances), instances)
+	}
+
+	orders := instances["prod/orders (RabbitmqCluster)"]
+	if orders.Parameters.Service != "orders" || orders.Parameters.Selector != "" {
+		t.Errorf("orders: service = %q, selector = %q, want the orders service", orders.Parameters.Service, orders.Parameters.Selector)
+	}
+
+	if orders.Parameters.Port != 0 || orders.Parameters.AmqpPort != 0 {
+		t.Errorf("orders: ports = %d/%d, want the defaults", orders.Parameters.Port, orders.Parameters.AmqpPort)
+	}
+
+	if orders.Username != "default_user_abc" || orders.Password != "s3cret" {
+		t.Errorf("orders: credentials = %q/%q, want those of the default user secret", orders.Username, orders.Password)
+	}
+
+	payments := instances["prod/payments (RabbitmqCluster)"]
+	if payments.Parameters.Port != tlsK8sPort || payments.Parameters.AmqpPort != tlsK8sAmqpPort {
+		t.Errorf("payments: ports = %d/%d, want the TLS ports", payments.Parameters.Port, payments.Parameters.AmqpPort)
+	}
+
+	// The default user secret is missing, discovery goes on without credentials.
+	if payments.Username != "" || payments.Password != "" {
+		t.Errorf("payments: credentials = %q/%q, want none", payments.Username, payments.Password)
+	}
+}
+
+func TestDiscoverStatefulSets(t *testing.T) {
+	owner := map[string]any{"apiVersion": "rabbitmq.com/v1beta1", "kind": RabbitmqClusterKind, "name": "orders", "uid": "1"}
+
+	client := newFakeDynamicClient(
+		bitnamiStatefulSet("dev", "mq-rabbitmq", "mq"),
+		secret("dev", "mq-rabbitmq", map[string]string{"rabbitmq-password": "bitnami"}),
+		bitnamiStatefulSet("prod", "orders-server", "orders", owner),
+	)
+
+	instances := discoverByName(t, client)
+	if len(instances) != 1 {
+		t.Fatalf("discovered %d instances, want 1 without the operator-owned one: %v", len(instances), instances)
+	}
+
+	mq, ok := instances["dev/mq (StatefulSet)"]
+	if !ok {
+		t.Fatalf("stateful set mq not discovered: %v", instances)
+	}
+
+	if want := "app.kubernetes.io/instance=mq,app.kubernetes.io/name=rabbitmq"; mq.Parameters.Selector != want {
+		t.Errorf("selector = %q, want %q", mq.Parameters.Selector, want)
+	}
+
+	if mq.Parameters.Service != "" || mq.Parameters.Port != 0 || mq.Parameters.AmqpPort != 0 {
+		t.Errorf("parameters = %+v, want the pods and default ports", mq.Parameters)
+	}
+
+	if mq.Username != "user" || mq.Password != "bitnami" {
+		t.Errorf("credentials = %q/%q, want user/bitnami", mq.Username, mq.Password)
+	}
+}
+
+func TestDiscoverWithoutOperator(t *testing.T) {
+	client := newFakeDynamicClient(
+		bitnamiStatefulSet("dev", "mq-rabbitmq", "mq"),
+	)
+
+	client.PrependReactor("list", rabbitmqClustersResource.Resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(rabbitmqClustersResource.GroupResource(), "")
+	})
+
+	instances := discoverByName(t, client)
+	if _, ok := instances["dev/mq (StatefulSet)"]; !ok || len(instances) != 1 {
+		t.Errorf("discovered %v, want only the stateful set", instances)
+	}
+}
+
+func TestDiscoverForbidden(t *testing.T) {
+	tests := []struct {
+		forbidden schema.GroupVersionResource
+		want      string
+	}{
+		{rabbitmqClustersResource, "dev/mq (StatefulSet)"},
+		{statefulSetsResource, "prod/orders (RabbitmqCluster)"},
+	}
+
+	for _, tt := range tests {
+		client := newFakeDynamicClient(
+			rabbitmqCluster("prod", "orders", map[string]any{}),
+			bitnamiStatefulSet("dev", "mq-rabbitmq", "mq"),
+		)
+
+		client.PrependReactor("list", tt.forbidden.Resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(tt.forbidden.GroupResource(), "", nil)
+		})
+
+		instances := discoverByName(t, client)
+		if _, ok := instances[tt.want]; !ok || len(instances) != 1 {
+			t.Errorf("discovered %v with %s forbidden, want only %s", instances, tt.forbidden.Resource, tt.want)
+		}
+	}
+}
+
+func TestDiscoverListError(t *testing.T) {
+	client := newFakeDynamicClient()
+
+	client.PrependReactor("list", rabbitmqClustersResource.Resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewInternalError(errors.New("etcd unavailable"))
+	})
+
+	if _, err := discoverK8sInstances(context.Background(), client, "test"); err == nil {
+		t.Error("discoverK8sInstances() succeeded, want the list error")
+	}
+}
@@ -0,0 +1,170 @@
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Pod selection policies of k8s connections.
+const (
+	// PodPolicyFirstReady picks the Ready pod with the lowest ordinal.
+	PodPolicyFirstReady = "first-ready"
+	// PodPolicyOrdinal picks the pod with the configured ordinal, if Ready.
+	PodPolicyOrdinal = "ordinal"
+	// PodPolicyRoundRobin picks the next Ready pod on each reconnection.
+	PodPolicyRoundRobin = "round-robin"
+)
+
+// PodPolicies lists the pod selection policies.
+var PodPolicies = []string{PodPolicyFirstReady, PodPolicyOrdinal, PodPolicyRoundRobin}
+
+const (
+	defaultK8sPort     = 15672
+	defaultK8sAmqpPort = 5672
+	tlsK8sPort         = 15671
+	tlsK8sAmqpPort     = 5671
+)
+
+func (p *K8sConnectionParameters) port() int {
+	if p.Port == 0 {
+		return defaultK8sPort
+	}
+
+	return p.Port
+}
+
+func (p *K8sConnectionParameters) amqpPort() int {
+	if p.AmqpPort == 0 {
+		return defaultK8sAmqpPort
+	}
+
+	return p.AmqpPort
+}
+
+// usesTLS reports whether the management port is reached over https.
+func (p *K8sConnectionParameters) usesTLS() bool {
+	return p.TLS != nil || p.port() == tlsK8sPort
+}
+
+// target describes the pods the connection forwards to, for messages.
+func (p *K8sConnectionParameters) target() string {
+	if p.Service != "" {
+		return fmt.Sprintf("service %s in namespace %s", p.Service, p.Namespace)
+	}
+
+	return fmt.Sprintf("RabbitMQ instance %s in namespace %s", p.Name, p.Namespace)
+}
+
+func (p *K8sConnectionParameters) validate() error {
+	if p.PodPolicy != "" && !slices.Contains(PodPolicies, p.PodPolicy) {
+		return fmt.Errorf("unknown pod policy %q, expected one of %s", p.PodPolicy, strings.Join(PodPolicies, ", "))
+	}
+
+	return nil
+}
+
+// choosePod picks a pod among the given ones according to the policy,
+// skipping the pods that are not Ready. attempt counts the connections, for
+// the round-robin policy.
+func (p *K8sConnectionParameters) choosePod(pods []corev1.Pod, attempt int) (*corev1.Pod, error) {
+	ready := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if isPodReady(&pod) {
+			ready = append(ready, pod)
+		}
+	}
+
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods found for %s", p.target())
+	} else if len(ready) == 0 {
+		return nil, fmt.Errorf("no ready pod among the %d pods of %s", len(pods), p.target())
+	}
+
+	// Sorting pods by ordinal to hit rabbitmq-0 first, and rabbitmq-2 before rabbitmq-10.
+	sort.Slice(ready, func(i, j int) bool {
+		oi, iok := podOrdinal(ready[i].Name)
+		oj, jok := podOrdinal(ready[j].Name)
+		if iok && jok && oi != oj {
+			return oi < oj
+		}
+
+		return ready[i].Name < ready[j].Name
+	})
+
+	switch p.PodPolicy {
+	case PodPolicyOrdinal:
+		for i := range ready {
+			if ordinal, ok := podOrdinal(ready[i].Name); ok && ordinal == p.Ordinal {
+				return &ready[i], nil
+			}
+		}
+
+		return nil, fmt.Errorf("no ready pod with ordinal %d for %s", p.Ordinal, p.target())
+	case PodPolicyRoundRobin:
+		return &ready[attempt%len(ready)], nil
+	default:
+		return &ready[0], nil
+	}
+}
+
+// isPodReady reports whether the pod is running, Ready and not terminating.
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// podOrdinal returns the ordinal of a StatefulSet pod, e.g. 2 for rabbitmq-server-2.
+func podOrdinal(name string) (int, bool) {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return 0, false
+	}
+
+	ordinal, err := strconv.Atoi(name[i+1:])
+
+	return ordinal, err == nil
+}
+
+// containerPort translates a port of the Service to the container port of
+// the pod it targets, as kubectl port-forward does.
+func containerPort(svc *corev1.Service, pod *corev1.Pod, port int) (int, error) {
+	for _, sp := range svc.Spec.Ports {
+		if int(sp.Port) != port {
+			continue
+		}
+
+		switch {
+		case sp.TargetPort.Type == intstr.Int && sp.TargetPort.IntVal == 0:
+			return port, nil
+		case sp.TargetPort.Type == intstr.Int:
+			return int(sp.TargetPort.IntVal), nil
+		}
+
+		for _, c := range pod.Spec.Containers {
+			for _, cp := range c.Ports {
+				if cp.Name == sp.TargetPort.StrVal {
+					return int(cp.ContainerPort), nil
+				}
+			}
+		}
+
+		return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, sp.TargetPort.StrVal)
+	}
+
+	return 0, fmt.Errorf("service %s has no port %d", svc.Name, port)
+}
@@ -53,12 +53,26 @@ func ActiveClusterName() string {
 }
 
 func Connect(name string) (*Cluster, error) {
+	newCluster, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	setCluster(newCluster)
+
+	return newCluster, nil
+}
+
+// Open connects to a saved cluster without making it the active one and
+// without starting the availability monitoring. The caller must Close the
+// returned cluster when it is no longer needed.
+func Open(name string) (*Cluster, error) {
 	mx.RLock()
 	cfg, ok := clusters[name]
 	mx.RUnlock()
 
 	if !ok {
-		return nil, fmt.Errorf("active cluster %s not found", name)
+		return nil, fmt.Errorf("cluster %s not found", name)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.Current().ConnectionTimeout)
@@ -69,8 +83,6 @@ func Connect(name string) (*Cluster, error) {
 		return nil, fmt.Errorf("failed to connect to cluster %s: %w", name, err)
 	}
 
-	setCluster(newCluster)
-
 	return newCluster, nil
 }
 
@@ -88,6 +100,8 @@ func Create(name string, parameters ConnectionParameters) error {
 		fileName:   path.Join(clustersDir, name+".yaml"),
 	}
 
+	clusterConfig.movePasswordToStore()
+
 	err := clusterConfig.save()
 	if err != nil {
 		return err
@@ -111,6 +125,8 @@ func Delete(name string) error {
 
 	_ = os.Remove(c.fileName)
 
+	c.deletePasswordFromStore()
+
 	return nil
 }
 
@@ -173,11 +189,11 @@ func loadClusters(configFile, clustersDir string) (clusters map[string]*Config,
 					continue
 				}
 
-				clusterConfig.migrate()
-
 				clusterConfig.name = name
 				clusterConfig.fileName = clusterFile
 
+				clusterConfig.migrate()
+
 				clusters[name] = clusterConfig
 			}
 		}
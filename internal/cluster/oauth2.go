@@ -0,0 +1,139 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"tbunny/internal/credentials"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuth2Parameters authenticate to the management API with an OAuth 2 bearer
+// token, for brokers using the rabbitmq_auth_backend_oauth2 plugin. The token
+// is either static, printed by a command, or obtained with the client
+// credentials flow from a token endpoint.
+type OAuth2Parameters struct {
+	// Token is a static access token.
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+	// TokenCommand is a shell command printing an access token.
+	TokenCommand string `yaml:"tokenCommand,omitempty" json:"tokenCommand,omitempty"`
+	// TokenUrl is the token endpoint of the client credentials flow.
+	TokenUrl string `yaml:"tokenUrl,omitempty" json:"tokenUrl,omitempty"`
+	ClientId string `yaml:"clientId,omitempty" json:"clientId,omitempty"`
+	// ClientSecret defaults to the password of the cluster, which may come
+	// from any of its sources.
+	ClientSecret string   `yaml:"clientSecret,omitempty" json:"clientSecret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// Audience is sent to token endpoints requiring one.
+	Audience string `yaml:"audience,omitempty" json:"audience,omitempty"`
+}
+
+// tokenSource returns the source of the access tokens. The tokens it returns
+// are cached by the caller, so it obtains a new one on each call. The token
+// endpoint is reached with the TLS configuration of the cluster.
+func (p *OAuth2Parameters) tokenSource(password string, tlsConfig *tls.Config) (oauth2.TokenSource, error) {
+	switch {
+	case p.Token != "":
+		return oauth2.StaticTokenSource(newToken(p.Token)), nil
+	case p.TokenCommand != "":
+		return commandTokenSource(p.TokenCommand), nil
+	case p.TokenUrl != "":
+		cfg := clientcredentials.Config{
+			ClientID:     p.ClientId,
+			ClientSecret: p.ClientSecret,
+			TokenURL:     p.TokenUrl,
+			Scopes:       p.Scopes,
+		}
+
+		if cfg.ClientSecret == "" {
+			cfg.ClientSecret = password
+		}
+
+		if p.Audience != "" {
+			cfg.EndpointParams = map[string][]string{"audience": {p.Audience}}
+		}
+
+		// The server name of the cluster doesn't apply to the token endpoint.
+		endpointConfig := tlsConfig.Clone()
+		endpointConfig.ServerName = ""
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: endpointConfig}}
+
+		return clientCredentialsTokenSource{cfg, client}, nil
+	default:
+		return nil, errors.New("oauth2 requires a token, a tokenCommand or a tokenUrl")
+	}
+}
+
+// clientCredentialsTokenSource requests a new token from the endpoint on each
+// call, unlike the source of clientcredentials.Config, which reuses it until
+// its expiry even when it was rejected.
+type clientCredentialsTokenSource struct {
+	cfg    clientcredentials.Config
+	client *http.Client
+}
+
+func (s clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, s.client)
+
+	return s.cfg.Token(ctx)
+}
+
+// commandTokenSource runs a shell command printing an access token, e.g.
+// "az account get-access-token --query accessToken -o tsv".
+type commandTokenSource string
+
+func (c commandTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	token, err := credentials.FromCommand(ctx, string(c))
+	if err != nil {
+		return nil, err
+	}
+
+	return newToken(strings.TrimSpace(token)), nil
+}
+
+// newToken returns an access token, expiring when its JWT claims say so.
+// Opaque tokens never expire, they are replaced when rejected.
+func newToken(accessToken string) *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		Expiry:      jwtExpiry(accessToken),
+	}
+}
+
+// jwtExpiry returns the exp claim of a JWT, or the zero time.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(claims.Exp), 0)
+}
@@ -0,0 +1,163 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientCredentialsTokenSource(t *testing.T) {
+	var requests []string
+
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+
+		id, secret, _ := r.BasicAuth()
+		requests = append(requests, fmt.Sprintf("%s:%s %s %s %s", id, secret, r.Form.Get("grant_type"), r.Form.Get("scope"), r.Form.Get("audience")))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"tok%d","token_type":"bearer","expires_in":3600}`, len(requests))
+	}))
+	defer idp.Close()
+
+	p := &OAuth2Parameters{
+		TokenUrl: idp.URL,
+		ClientId: "tbunny",
+		Scopes:   []string{"rabbitmq.read:*/*", "rabbitmq.tag:monitoring"},
+		Audience: "rabbitmq",
+	}
+
+	// The client secret defaults to the password of the cluster.
+	source, err := p.tokenSource("s3cret", &tls.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		token, err := source.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The source must not cache tokens, so that rejected ones are replaced.
+		if want := fmt.Sprintf("tok%d", i); token.AccessToken != want {
+			t.Errorf("token = %s, want %s", token.AccessToken, want)
+		}
+
+		if time.Until(token.Expiry) < 59*time.Minute {
+			t.Errorf("expiry = %s, want in an hour", token.Expiry)
+		}
+	}
+
+	want := "tbunny:s3cret client_credentials rabbitmq.read:*/* rabbitmq.tag:monitoring rabbitmq"
+	if len(requests) != 2 || requests[0] != want {
+		t.Errorf("token requests = %q, want %q twice", requests, want)
+	}
+}
+
+func TestClientCredentialsTokenSourceTLS(t *testing.T) {
+	idp := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token":"tok","token_type":"bearer"}`)
+	}))
+	defer idp.Close()
+
+	// The endpoint is only trusted by the CA of the cluster, whose server
+	// name doesn't apply to it.
+	pool := x509.NewCertPool()
+	pool.AddCert(idp.Certificate())
+
+	tlsConfig := &tls.Config{RootCAs: pool, ServerName: "rabbitmq.example.com"}
+
+	source, err := (&OAuth2Parameters{TokenUrl: idp.URL, ClientId: "tbunny"}).tokenSource("s3cret", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err := source.Token(); err != nil {
+		t.Errorf("Token() failed: %s", err)
+	} else if token.AccessToken != "tok" {
+		t.Errorf("token = %s, want tok", token.AccessToken)
+	}
+
+	if tlsConfig.ServerName != "rabbitmq.example.com" {
+		t.Errorf("server name of the cluster changed to %q", tlsConfig.ServerName)
+	}
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	source, err := (&OAuth2Parameters{Token: "opaque"}).tokenSource("", &tls.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := source.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "opaque" || !token.Expiry.IsZero() {
+		t.Errorf("token = %s expiring %s, want opaque never expiring", token.AccessToken, token.Expiry)
+	}
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	jwt := testJWT(`{"exp":1900000000}`)
+
+	source, err := (&OAuth2Parameters{TokenCommand: "echo " + jwt}).tokenSource("", &tls.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := source.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != jwt || !token.Expiry.Equal(time.Unix(1900000000, 0)) {
+		t.Errorf("token = %s expiring %s, want the JWT expiring at its exp claim", token.AccessToken, token.Expiry)
+	}
+}
+
+func TestTokenSourceWithoutToken(t *testing.T) {
+	if _, err := (&OAuth2Parameters{ClientId: "tbunny"}).tokenSource("", &tls.Config{}); err == nil {
+		t.Error("tokenSource() succeeded without a token, command or URL")
+	}
+}
+
+func TestJWTExpiry(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  time.Time
+	}{
+		{"exp claim", testJWT(`{"sub":"tbunny","exp":1700000000}`), time.Unix(1700000000, 0)},
+		{"padded payload", "header." + base64.URLEncoding.EncodeToString([]byte(`{"exp":1700000001 }`)) + ".signature", time.Unix(1700000001, 0)},
+		{"no exp claim", testJWT(`{"sub":"tbunny"}`), time.Time{}},
+		{"invalid payload", "header.!!!.signature", time.Time{}},
+		{"not JSON", "header." + base64.RawURLEncoding.EncodeToString([]byte("nope")) + ".signature", time.Time{}},
+		{"opaque token", "2YotnFZFEjr1zCsicMWpAA", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jwtExpiry(tt.token); !got.Equal(tt.want) {
+				t.Errorf("jwtExpiry() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func testJWT(claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+
+	return header + "." + payload + ".signature"
+}
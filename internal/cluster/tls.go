@@ -0,0 +1,170 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"tbunny/internal/utils"
+)
+
+// TLSParameters configure TLS for connections over https:// and amqps://.
+type TLSParameters struct {
+	// CAFile is a PEM bundle of the certificate authorities trusted in
+	// addition to the system ones, e.g. an internal CA.
+	CAFile string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	// CertFile and KeyFile are the PEM client certificate and key, for
+	// brokers requiring mutual TLS.
+	CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+	// ServerName overrides the host name the server certificate is verified
+	// against and sent with SNI.
+	ServerName string `yaml:"serverName,omitempty" json:"serverName,omitempty"`
+	// MinVersion is the minimum TLS version: 1.0, 1.1, 1.2 (default) or 1.3.
+	MinVersion string `yaml:"minVersion,omitempty" json:"minVersion,omitempty"`
+	// InsecureSkipVerify disables the verification of the server certificate.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty" json:"insecureSkipVerify,omitempty"`
+}
+
+// TLSVersions lists the supported minimum TLS versions.
+var TLSVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsConfig returns the TLS configuration defined by the parameters, or the
+// default configuration when they are nil. It never returns a nil
+// configuration without an error.
+func (p *TLSParameters) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if p == nil {
+		return cfg, nil
+	}
+
+	cfg.ServerName = p.ServerName
+	cfg.InsecureSkipVerify = p.InsecureSkipVerify
+
+	if p.MinVersion != "" {
+		version, ok := tlsVersions[p.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported minimum TLS version %s", p.MinVersion)
+		}
+
+		cfg.MinVersion = version
+	}
+
+	if p.CAFile != "" {
+		pool, err := loadCertPool(p.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if p.CertFile != "" || p.KeyFile != "" {
+		if p.CertFile == "" || p.KeyFile == "" {
+			return nil, errors.New("both a client certificate and a key are required")
+		}
+
+		cert, err := loadKeyPair(p.CertFile, p.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	path, err := utils.ExpandPath(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no PEM certificate found in %s", caFile)
+	}
+
+	return pool, nil
+}
+
+func loadKeyPair(certFile, keyFile string) (tls.Certificate, error) {
+	certPath, err := utils.ExpandPath(certFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	keyPath, err := utils.ExpandPath(keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	return cert, nil
+}
+
+// tlsState records the state of the latest TLS connection established with
+// a configuration, to display the negotiated certificate.
+type tlsState struct {
+	state atomic.Pointer[tls.ConnectionState]
+}
+
+// watch makes cfg record its connections in s.
+func (s *tlsState) watch(cfg *tls.Config) {
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		s.state.Store(&cs)
+		return nil
+	}
+}
+
+// describe describes the negotiated version and the server certificate, or
+// returns an empty string without TLS connection.
+func (s *tlsState) describe() string {
+	if s == nil {
+		return ""
+	}
+
+	cs := s.state.Load()
+	if cs == nil {
+		return ""
+	}
+
+	version := tls.VersionName(cs.Version)
+
+	if len(cs.PeerCertificates) == 0 {
+		return version
+	}
+
+	cert := cs.PeerCertificates[0]
+
+	subject := cert.Subject.CommonName
+	if subject == "" && len(cert.DNSNames) > 0 {
+		subject = cert.DNSNames[0]
+	}
+
+	return fmt.Sprintf("%s %s, until %s", version, subject, cert.NotAfter.Format("2006-01-02"))
+}
@@ -0,0 +1,19 @@
+package config
+
+// AlertRule colors the rows of resources matching a condition, so that
+// unhealthy resources stand out in their views.
+type AlertRule struct {
+	// Name identifies the rule in notifications; a cluster rule with the
+	// same name as a rule of config.yaml replaces it.
+	Name string `yaml:"name" json:"name"`
+	// Resource is the type of resources the rule applies to: queues, nodes,
+	// connections or vhosts.
+	Resource string `yaml:"resource" json:"resource"`
+	// When is the condition on the fields of the resources as returned by
+	// the management API, e.g. consumers == 0 and messages > 0.
+	When string `yaml:"when" json:"when"`
+	// Color is the color of the rows of matching resources.
+	Color string `yaml:"color" json:"color"`
+	// Disabled turns off a rule of config.yaml for a cluster.
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+}
@@ -14,6 +14,10 @@ import (
 type Config struct {
 	UI                UI            `yaml:"ui" json:"ui"`
 	ConnectionTimeout time.Duration `yaml:"connectionTimeout" json:"connectionTimeout"`
+	Decoders          Decoders      `yaml:"decoders" json:"decoders"`
+	Alerts            []AlertRule   `yaml:"alerts" json:"alerts"`
+	Credentials       Credentials   `yaml:"credentials" json:"credentials"`
+	Publish           Publish       `yaml:"publish" json:"publish"`
 }
 
 type UI struct {
@@ -21,6 +25,23 @@ type UI struct {
 	SplashDuration time.Duration `yaml:"splashDuration" json:"splashDuration"`
 }
 
+// Credentials configures where the passwords of clusters are kept.
+type Credentials struct {
+	// Store is the store passwords are moved to from cluster files: keyring
+	// or encrypted. Passwords stay in cluster files when it is empty.
+	Store string `yaml:"store" json:"store"`
+}
+
+// Publish configures the publishing of messages.
+type Publish struct {
+	// HistorySize is the number of published messages remembered per cluster.
+	HistorySize int `yaml:"historySize" json:"historySize"`
+}
+
+// DefaultHistorySize is the default number of published messages remembered
+// per cluster.
+const DefaultHistorySize = 50
+
 type Listener interface {
 	ConfigChanged(*Config)
 }
@@ -108,6 +129,8 @@ func newConfig() *Config {
 			SplashDuration: 1 * time.Second,
 		},
 		ConnectionTimeout: 10 * time.Second,
+		Publish: Publish{
+			HistorySize: DefaultHistorySize,
+		},
 	}
 }
-
@@ -0,0 +1,23 @@
+package config
+
+// Decoders configures payload decoders which need schemas.
+type Decoders struct {
+	Protobuf ProtobufDecoder `yaml:"protobuf" json:"protobuf"`
+	Avro     AvroDecoder     `yaml:"avro" json:"avro"`
+}
+
+type ProtobufDecoder struct {
+	// DescriptorSets are files produced by protoc --descriptor_set_out
+	// (preferably with --include_imports).
+	DescriptorSets []string `yaml:"descriptorSets" json:"descriptorSets"`
+	// MessageType is the fully qualified message name used when messages
+	// don't name theirs.
+	MessageType string `yaml:"messageType" json:"messageType"`
+}
+
+type AvroDecoder struct {
+	// Schemas maps schema names to schema files (.avsc).
+	Schemas map[string]string `yaml:"schemas" json:"schemas"`
+	// Schema is the name of the schema used when messages don't name theirs.
+	Schema string `yaml:"schema" json:"schema"`
+}
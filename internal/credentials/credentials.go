@@ -0,0 +1,85 @@
+// Package credentials keeps the passwords of clusters out of their
+// configuration files: in the OS keyring, in a file encrypted with a master
+// passphrase, or obtained from environment variables and external commands.
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Names of the stores of passwords.
+const (
+	KeyringStore   = "keyring"
+	EncryptedStore = "encrypted"
+)
+
+// Stores lists the names of the stores of passwords.
+var Stores = []string{KeyringStore, EncryptedStore}
+
+// ErrNotFound is returned when a store has no password for a cluster.
+var ErrNotFound = errors.New("password not found")
+
+// Store keeps the passwords of clusters, by cluster name.
+type Store interface {
+	// Get returns the password of a cluster, or ErrNotFound.
+	Get(cluster string) (string, error)
+	// Set stores the password of a cluster.
+	Set(cluster, password string) error
+	// Delete removes the password of a cluster, if any.
+	Delete(cluster string) error
+}
+
+// Open returns the store with the given name.
+func Open(name string) (Store, error) {
+	switch name {
+	case KeyringStore:
+		return keyringStore{}, nil
+	case EncryptedStore:
+		return encrypted, nil
+	default:
+		return nil, fmt.Errorf("unknown password store %q, expected one of %s", name, strings.Join(Stores, ", "))
+	}
+}
+
+// FromEnv returns the password held by an environment variable.
+func FromEnv(name string) (string, error) {
+	password, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return password, nil
+}
+
+// FromCommand returns the password printed by a shell command, e.g.
+// "pass show rmq/prod", without the trailing line break.
+func FromCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("password command failed: %w: %s", err, msg)
+		}
+
+		return "", fmt.Errorf("password command failed: %w", err)
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
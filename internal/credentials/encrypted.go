@@ -0,0 +1,221 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// encryptedFileName is the name of the encrypted file in the configuration directory.
+	encryptedFileName = "credentials.enc"
+
+	// keyIterations is the number of PBKDF2-HMAC-SHA256 iterations deriving
+	// the key from the master passphrase.
+	keyIterations = 600_000
+	keyLength     = 32
+	saltLength    = 16
+)
+
+// ErrLocked is returned by the encrypted store before Unlock is called.
+var ErrLocked = errors.New("encrypted password store is locked")
+
+// ErrWrongPassphrase is returned by Unlock when the passphrase does not
+// decrypt the file.
+var ErrWrongPassphrase = errors.New("wrong master passphrase")
+
+// encryptedFile is the content of the encrypted file: passwords by cluster
+// name, encrypted with AES-256-GCM.
+type encryptedFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// encryptedStore keeps passwords in a file encrypted with a key derived from
+// a master passphrase.
+type encryptedStore struct {
+	fileName  string
+	salt      []byte
+	key       []byte
+	passwords map[string]string
+	mx        sync.Mutex
+}
+
+var encrypted = &encryptedStore{}
+
+// Init sets the directory of the encrypted file.
+func Init(configDir string) {
+	encrypted.mx.Lock()
+	defer encrypted.mx.Unlock()
+
+	encrypted.fileName = filepath.Join(configDir, encryptedFileName)
+}
+
+// EncryptedFileExists reports whether passwords were saved to the encrypted file.
+func EncryptedFileExists() bool {
+	encrypted.mx.Lock()
+	defer encrypted.mx.Unlock()
+
+	_, err := os.Stat(encrypted.fileName)
+
+	return err == nil
+}
+
+// Unlock decrypts the encrypted file with the master passphrase, or
+// prepares a new file encrypted with it if there is none yet.
+func Unlock(passphrase string) error {
+	return encrypted.unlock(passphrase)
+}
+
+func (s *encryptedStore) unlock(passphrase string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	content, err := os.ReadFile(s.fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		s.salt = make([]byte, saltLength)
+		if _, err = rand.Read(s.salt); err != nil {
+			return err
+		}
+
+		s.key, err = deriveKey(passphrase, s.salt)
+		s.passwords = map[string]string{}
+
+		return err
+	} else if err != nil {
+		return fmt.Errorf("failed to read encrypted password store: %w", err)
+	}
+
+	var f encryptedFile
+	if err = json.Unmarshal(content, &f); err != nil {
+		return fmt.Errorf("failed to parse encrypted password store: %w", err)
+	}
+
+	key, err := deriveKey(passphrase, f.Salt)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+
+	var passwords map[string]string
+	if err = json.Unmarshal(plaintext, &passwords); err != nil {
+		return fmt.Errorf("failed to parse encrypted password store: %w", err)
+	}
+
+	s.salt, s.key, s.passwords = f.Salt, key, passwords
+
+	return nil
+}
+
+func (s *encryptedStore) Get(cluster string) (string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.key == nil {
+		return "", ErrLocked
+	}
+
+	password, ok := s.passwords[cluster]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return password, nil
+}
+
+func (s *encryptedStore) Set(cluster, password string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.key == nil {
+		return ErrLocked
+	}
+
+	s.passwords[cluster] = password
+
+	return s.save()
+}
+
+func (s *encryptedStore) Delete(cluster string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.key == nil {
+		return ErrLocked
+	}
+
+	if _, ok := s.passwords[cluster]; !ok {
+		return nil
+	}
+
+	delete(s.passwords, cluster)
+
+	return s.save()
+}
+
+// save encrypts the passwords with a new nonce and writes them to the file.
+// It must be called with the lock held.
+func (s *encryptedStore) save() error {
+	plaintext, err := json.Marshal(s.passwords)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+
+	f := encryptedFile{Salt: s.salt, Nonce: make([]byte, gcm.NonceSize())}
+	if _, err = rand.Read(f.Nonce); err != nil {
+		return err
+	}
+
+	f.Data = gcm.Seal(nil, f.Nonce, plaintext, nil)
+
+	content, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(s.fileName), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for encrypted password store: %w", err)
+	}
+
+	if err = os.WriteFile(s.fileName, content, 0600); err != nil {
+		return fmt.Errorf("failed to save encrypted password store: %w", err)
+	}
+
+	return nil
+}
+
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, keyIterations, keyLength)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
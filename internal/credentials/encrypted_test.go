@@ -0,0 +1,75 @@
+package credentials
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func newTestEncryptedStore(t *testing.T) *encryptedStore {
+	return &encryptedStore{fileName: filepath.Join(t.TempDir(), encryptedFileName)}
+}
+
+func TestEncryptedStoreRoundTrip(t *testing.T) {
+	s := newTestEncryptedStore(t)
+
+	if err := s.unlock("master"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Set("prod", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store reads the passwords back from the file.
+	reopened := &encryptedStore{fileName: s.fileName}
+	if err := reopened.unlock("master"); err != nil {
+		t.Fatal(err)
+	}
+
+	if password, err := reopened.Get("prod"); err != nil || password != "s3cret" {
+		t.Errorf("Get(prod) = %q, %v, want s3cret", password, err)
+	}
+
+	if _, err := reopened.Get("dev"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(dev) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestEncryptedStoreWrongPassphrase(t *testing.T) {
+	s := newTestEncryptedStore(t)
+
+	if err := s.unlock("master"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Set("prod", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := &encryptedStore{fileName: s.fileName}
+	if err := reopened.unlock("wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("unlock() error = %v, want ErrWrongPassphrase", err)
+	}
+
+	// The store stays locked after a wrong passphrase.
+	if _, err := reopened.Get("prod"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Get(prod) error = %v, want ErrLocked", err)
+	}
+}
+
+func TestEncryptedStoreLocked(t *testing.T) {
+	s := newTestEncryptedStore(t)
+
+	if _, err := s.Get("prod"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Get() error = %v, want ErrLocked", err)
+	}
+
+	if err := s.Set("prod", "s3cret"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Set() error = %v, want ErrLocked", err)
+	}
+
+	if err := s.Delete("prod"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Delete() error = %v, want ErrLocked", err)
+	}
+}
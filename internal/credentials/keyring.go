@@ -0,0 +1,43 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service passwords are stored under in the OS
+// keyring: the Secret Service on Linux, the Keychain on macOS and the
+// Credential Manager on Windows.
+const keyringService = "tbunny"
+
+type keyringStore struct{}
+
+func (keyringStore) Get(cluster string) (string, error) {
+	password, err := keyring.Get(keyringService, cluster)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read password from keyring: %w", err)
+	}
+
+	return password, nil
+}
+
+func (keyringStore) Set(cluster, password string) error {
+	if err := keyring.Set(keyringService, cluster, password); err != nil {
+		return fmt.Errorf("failed to save password to keyring: %w", err)
+	}
+
+	return nil
+}
+
+func (keyringStore) Delete(cluster string) error {
+	err := keyring.Delete(keyringService, cluster)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("failed to delete password from keyring: %w", err)
+	}
+
+	return nil
+}
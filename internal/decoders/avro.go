@@ -0,0 +1,105 @@
+package decoders
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"tbunny/internal/config"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+var (
+	avroMagic   = []byte("Obj\x01")
+	avroSchemas = newFileCache(func(content []byte) (avro.Schema, error) {
+		return avro.ParseBytes(content)
+	})
+)
+
+func isAvroContainer(data []byte) bool {
+	return bytes.HasPrefix(data, avroMagic)
+}
+
+// decodeAvro decodes Avro object container files, which embed their
+// schema, and single Avro datums with a configured schema.
+func decodeAvro(data []byte, hints Hints) ([]byte, Format, string, error) {
+	if isAvroContainer(data) {
+		return decodeAvroContainer(data)
+	}
+
+	name, schema, err := findAvroSchema(avroConfig(), hints)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var v any
+	if err = avro.Unmarshal(schema, data, &v); err != nil {
+		return nil, 0, "", err
+	}
+
+	return marshalJSON(v, Avro+" "+name)
+}
+
+func decodeAvroContainer(data []byte) ([]byte, Format, string, error) {
+	d, err := ocf.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var records []any
+
+	for d.HasNext() {
+		var v any
+		if err = d.Decode(&v); err != nil {
+			return nil, 0, "", err
+		}
+
+		records = append(records, v)
+	}
+
+	if err = d.Error(); err != nil {
+		return nil, 0, "", err
+	}
+
+	return marshalJSON(records, Avro+" container")
+}
+
+// findAvroSchema finds the schema named by the content type (schema
+// parameter), by the type property or by the configuration.
+func findAvroSchema(cfg config.AvroDecoder, hints Hints) (string, avro.Schema, error) {
+	if len(cfg.Schemas) == 0 {
+		return "", nil, errors.New("no schema configured (decoders.avro.schemas)")
+	}
+
+	for _, name := range []string{schemaName(hints, "schema"), cfg.Schema} {
+		if path, ok := cfg.Schemas[name]; ok && name != "" {
+			schema, err := avroSchemas.get(path)
+			return name, schema, err
+		}
+	}
+
+	// A single schema needs no name.
+	if len(cfg.Schemas) == 1 {
+		for name, path := range cfg.Schemas {
+			schema, err := avroSchemas.get(path)
+			return name, schema, err
+		}
+	}
+
+	name := cmp.Or(schemaName(hints, "schema"), cfg.Schema)
+	if name == "" {
+		return "", nil, errors.New("schema unknown: set it in the content type (schema parameter), the type property or decoders.avro.schema")
+	}
+
+	return "", nil, fmt.Errorf("schema %s not configured", name)
+}
+
+func avroConfig() config.AvroDecoder {
+	if c := config.Current(); c != nil {
+		return c.Decoders.Avro
+	}
+
+	return config.AvroDecoder{}
+}
@@ -0,0 +1,237 @@
+// Package decoders turns message payloads into something readable: they are
+// decompressed and unwrapped according to their content encoding, then
+// decoded according to their content type.
+package decoders
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Format tells how decoded data should be displayed.
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+	FormatHexDump
+)
+
+// Names of decoders which can be selected manually.
+const (
+	Auto     = "auto"
+	Text     = "text"
+	JSON     = "json"
+	MsgPack  = "msgpack"
+	CBOR     = "cbor"
+	Protobuf = "protobuf"
+	Avro     = "avro"
+	Hex      = "hex"
+)
+
+// Names lists decoders in the order they are cycled through.
+var Names = []string{Auto, Text, JSON, MsgPack, CBOR, Protobuf, Avro, Hex}
+
+// Hints are message properties used to select decoders.
+type Hints struct {
+	ContentType     string
+	ContentEncoding string
+	// Type is the AMQP type property, often the name of the message schema.
+	Type string
+}
+
+// Payload is a decoded payload.
+type Payload struct {
+	Data   []byte
+	Format Format
+	// Steps describes the decoders applied to the raw payload, e.g. gzip, protobuf.
+	Steps []string
+	// Err is set when the content decoder failed; Data then holds a fallback.
+	Err error
+}
+
+// Description returns the applied decoders, e.g. "gzip ⏵ protobuf".
+func (p *Payload) Description() string {
+	return strings.Join(p.Steps, " ⏵ ")
+}
+
+type contentDecoder func(data []byte, hints Hints) (decoded []byte, format Format, step string, err error)
+
+var contentDecoders = map[string]contentDecoder{
+	Text:     decodeText,
+	JSON:     decodeJSON,
+	MsgPack:  decodeMsgPack,
+	CBOR:     decodeCBOR,
+	Protobuf: decodeProtobuf,
+	Avro:     decodeAvro,
+	Hex:      decodeHex,
+}
+
+// Decode decodes a payload with the named decoder, or with decoders selected
+// from the hints when name is Auto. Content encodings are always removed first.
+func Decode(data []byte, hints Hints, name string) *Payload {
+	p := &Payload{}
+
+	data, steps, err := decodeContentEncoding(data, hints)
+	p.Steps = steps
+
+	if err != nil {
+		p.Err = err
+		p.Data, p.Format = []byte(hex.Dump(data)), FormatHexDump
+		p.Steps = append(p.Steps, Hex)
+		return p
+	}
+
+	if name == Auto || name == "" {
+		name = detectContentDecoder(data, hints)
+	}
+
+	decode, ok := contentDecoders[name]
+	if !ok {
+		decode = decodeText
+	}
+
+	decoded, format, step, err := decode(data, hints)
+	if err != nil {
+		p.Err = fmt.Errorf("%s: %w", name, err)
+		decoded, format, step, _ = fallback(data, hints)
+	}
+
+	p.Data, p.Format = decoded, format
+	p.Steps = append(p.Steps, step)
+
+	return p
+}
+
+// decodeContentEncoding removes compression and base64 wrapping.
+func decodeContentEncoding(data []byte, hints Hints) ([]byte, []string, error) {
+	var steps []string
+
+	// Content encodings may be stacked, e.g. "gzip, base64", in the order
+	// they were applied.
+	encodings := strings.Split(strings.ToLower(hints.ContentEncoding), ",")
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.TrimSpace(encodings[i])
+
+		decode, ok := contentEncodings[encoding]
+		if !ok {
+			continue
+		}
+
+		decoded, err := decode(data)
+		if err != nil {
+			return data, steps, fmt.Errorf("%s: %w", encoding, err)
+		}
+
+		data = decoded
+		steps = append(steps, encoding)
+	}
+
+	// Compressed payloads are common even without a content encoding.
+	if len(steps) == 0 && isGzip(data) {
+		if decoded, err := gunzip(data); err == nil {
+			data = decoded
+			steps = append(steps, "gzip")
+		}
+	}
+
+	return data, steps, nil
+}
+
+func detectContentDecoder(data []byte, hints Hints) string {
+	mediaType, _ := parseContentType(hints.ContentType)
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return JSON
+	case strings.Contains(mediaType, "msgpack"):
+		return MsgPack
+	case strings.Contains(mediaType, "cbor"):
+		return CBOR
+	case strings.Contains(mediaType, "protobuf") || strings.Contains(mediaType, "proto"):
+		return Protobuf
+	case strings.Contains(mediaType, "avro"):
+		return Avro
+	case isAvroContainer(data):
+		return Avro
+	}
+
+	if !isText(data) {
+		return Hex
+	}
+
+	if json.Valid(data) {
+		return JSON
+	}
+
+	return Text
+}
+
+// fallback displays data which could not be decoded as text, or as a hex
+// dump if it is binary.
+func fallback(data []byte, hints Hints) ([]byte, Format, string, error) {
+	if isText(data) {
+		return decodeText(data, hints)
+	}
+
+	return decodeHex(data, hints)
+}
+
+// isText tells whether data is UTF-8 text without control characters.
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+
+	return !bytes.ContainsFunc(data, func(r rune) bool {
+		return unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t'
+	})
+}
+
+func decodeText(data []byte, _ Hints) ([]byte, Format, string, error) {
+	return data, FormatText, Text, nil
+}
+
+func decodeJSON(data []byte, _ Hints) ([]byte, Format, string, error) {
+	if !json.Valid(data) {
+		return nil, 0, "", fmt.Errorf("invalid JSON")
+	}
+
+	return data, FormatJSON, JSON, nil
+}
+
+func decodeHex(data []byte, _ Hints) ([]byte, Format, string, error) {
+	return []byte(hex.Dump(data)), FormatHexDump, Hex, nil
+}
+
+// parseContentType returns the media type and the parameters of a content
+// type, e.g. application/x-protobuf; proto=orders.v1.OrderCreated.
+func parseContentType(contentType string) (string, map[string]string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType)), nil
+	}
+
+	return mediaType, params
+}
+
+// schemaName returns the schema or message type named by the first content
+// type parameter found, or by the type property.
+func schemaName(hints Hints, params ...string) string {
+	_, values := parseContentType(hints.ContentType)
+
+	for _, p := range params {
+		if v := values[p]; v != "" {
+			return v
+		}
+	}
+
+	return hints.Type
+}
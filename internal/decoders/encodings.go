@@ -0,0 +1,59 @@
+package decoders
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"io"
+	"strings"
+)
+
+var contentEncodings = map[string]func([]byte) ([]byte, error){
+	"gzip":    gunzip,
+	"x-gzip":  gunzip,
+	"deflate": inflate,
+	"base64":  unbase64,
+}
+
+func isGzip(data []byte) bool {
+	return len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// inflate decompresses deflate data, which in HTTP terms is zlib-wrapped
+// but is often sent raw.
+func inflate(data []byte) ([]byte, error) {
+	if r, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		defer r.Close()
+
+		if decoded, err := io.ReadAll(r); err == nil {
+			return decoded, nil
+		}
+	}
+
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+func unbase64(data []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(data))
+
+	if decoded, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return decoded, nil
+	}
+
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,59 @@
+package decoders
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"tbunny/internal/utils"
+)
+
+// fileCache keeps files parsed until they are modified.
+type fileCache[T any] struct {
+	mx      sync.Mutex
+	parse   func([]byte) (T, error)
+	entries map[string]fileCacheEntry[T]
+}
+
+type fileCacheEntry[T any] struct {
+	modTime int64
+	value   T
+}
+
+func newFileCache[T any](parse func([]byte) (T, error)) *fileCache[T] {
+	return &fileCache[T]{parse: parse, entries: make(map[string]fileCacheEntry[T])}
+}
+
+func (c *fileCache[T]) get(path string) (T, error) {
+	var zero T
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return zero, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return zero, err
+	}
+
+	if e, ok := c.entries[path]; ok && e.modTime == info.ModTime().UnixNano() {
+		return e.value, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return zero, err
+	}
+
+	value, err := c.parse(content)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", path, err)
+	}
+
+	c.entries[path] = fileCacheEntry[T]{info.ModTime().UnixNano(), value}
+
+	return value, nil
+}
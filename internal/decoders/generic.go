@@ -0,0 +1,68 @@
+package decoders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func decodeMsgPack(data []byte, _ Hints) ([]byte, Format, string, error) {
+	d := msgpack.NewDecoder(bytes.NewReader(data))
+	// Maps may have keys of any type.
+	d.SetMapDecoder(func(d *msgpack.Decoder) (any, error) {
+		return d.DecodeUntypedMap()
+	})
+
+	v, err := d.DecodeInterface()
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return marshalJSON(v, MsgPack)
+}
+
+func decodeCBOR(data []byte, _ Hints) ([]byte, Format, string, error) {
+	var v any
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return nil, 0, "", err
+	}
+
+	return marshalJSON(v, CBOR)
+}
+
+func marshalJSON(v any, step string) ([]byte, Format, string, error) {
+	data, err := json.Marshal(jsonCompatible(v))
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return data, FormatJSON, step, nil
+}
+
+// jsonCompatible converts maps with non-string keys, which MessagePack and
+// CBOR allow, to maps JSON can represent.
+func jsonCompatible(v any) any {
+	switch typed := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(typed))
+		for k, v := range typed {
+			m[fmt.Sprint(k)] = jsonCompatible(v)
+		}
+		return m
+	case map[string]any:
+		for k, v := range typed {
+			typed[k] = jsonCompatible(v)
+		}
+		return typed
+	case []any:
+		for i, v := range typed {
+			typed[i] = jsonCompatible(v)
+		}
+		return typed
+	}
+
+	return v
+}
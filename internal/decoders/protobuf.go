@@ -0,0 +1,102 @@
+package decoders
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"tbunny/internal/config"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var descriptorSets = newFileCache(loadDescriptorSet)
+
+func decodeProtobuf(data []byte, hints Hints) ([]byte, Format, string, error) {
+	cfg := protobufConfig()
+	if len(cfg.DescriptorSets) == 0 {
+		return nil, 0, "", errors.New("no descriptor set configured (decoders.protobuf.descriptorSets)")
+	}
+
+	md, err := findMessageType(cfg, hints)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	msg := dynamicpb.NewMessage(md.desc)
+	if err = proto.Unmarshal(data, msg); err != nil {
+		return nil, 0, "", err
+	}
+
+	// The resolver allows printing google.protobuf.Any fields.
+	o := protojson.MarshalOptions{Resolver: md.types}
+
+	decoded, err := o.Marshal(msg)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return decoded, FormatJSON, Protobuf + " " + string(md.desc.FullName()), nil
+}
+
+type messageType struct {
+	desc  protoreflect.MessageDescriptor
+	types *dynamicpb.Types
+}
+
+// findMessageType finds the message type named by the content type (proto or
+// messageType parameter), by the type property or by the configuration in
+// the descriptor sets.
+func findMessageType(cfg config.ProtobufDecoder, hints Hints) (*messageType, error) {
+	names := []string{schemaName(hints, "proto", "messagetype"), cfg.MessageType}
+
+	for _, path := range cfg.DescriptorSets {
+		files, err := descriptorSets.get(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+
+			d, err := files.FindDescriptorByName(protoreflect.FullName(name))
+			if err != nil {
+				continue
+			}
+
+			if desc, ok := d.(protoreflect.MessageDescriptor); ok {
+				return &messageType{desc, dynamicpb.NewTypes(files)}, nil
+			}
+		}
+	}
+
+	if names[0] == "" && names[1] == "" {
+		return nil, errors.New("message type unknown: set it in the content type (proto parameter), the type property or decoders.protobuf.messageType")
+	}
+
+	return nil, fmt.Errorf("message type %s not found in descriptor sets", cmp.Or(names...))
+}
+
+func loadDescriptorSet(content []byte) (*protoregistry.Files, error) {
+	var fds descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(content, &fds); err != nil {
+		return nil, err
+	}
+
+	return protodesc.NewFiles(&fds)
+}
+
+func protobufConfig() config.ProtobufDecoder {
+	if c := config.Current(); c != nil {
+		return c.Decoders.Protobuf
+	}
+
+	return config.ProtobufDecoder{}
+}
@@ -0,0 +1,389 @@
+// Package jsonpath evaluates simple JSONPath-like expressions on decoded JSON
+// documents, e.g. $.order.status == "FAILED" or $.items[*].sku =~ "^ABC".
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operator compares the values selected by a path with a literal.
+type Operator string
+
+const (
+	// OpExists matches when the path selects a value other than null.
+	OpExists       Operator = ""
+	OpEqual        Operator = "=="
+	OpNotEqual     Operator = "!="
+	OpMatch        Operator = "=~"
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+)
+
+// operators are ordered so that longer operators are found first.
+var operators = []Operator{OpEqual, OpNotEqual, OpMatch, OpGreaterEqual, OpLessEqual, OpGreater, OpLess}
+
+// wildcard is the path segment selecting all members of an object or array.
+const wildcard = "*"
+
+// Expression is a parsed expression: a path, optionally compared with a literal.
+type Expression struct {
+	path     []any
+	op       Operator
+	literal  any
+	re       *regexp.Regexp
+	original string
+}
+
+// IsExpression reports whether s looks like an expression rather than text.
+func IsExpression(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), "$")
+}
+
+// Parse parses an expression made of a path starting with $, with .name,
+// ['name'], [index] and [*] segments, optionally followed by an operator
+// (==, !=, =~, >, >=, <, <=) and a JSON literal; =~ takes a regular
+// expression as a string.
+func Parse(s string) (*Expression, error) {
+	e := Expression{original: strings.TrimSpace(s)}
+
+	pathText, literalText := e.original, ""
+
+	if i, op := findOperator(e.original); i >= 0 {
+		e.op = op
+		pathText = strings.TrimSpace(e.original[:i])
+		literalText = strings.TrimSpace(e.original[i+len(op):])
+	}
+
+	var err error
+
+	if e.path, err = parsePath(pathText); err != nil {
+		return nil, err
+	}
+
+	if e.op == OpExists {
+		return &e, nil
+	}
+
+	if literalText == "" {
+		return nil, fmt.Errorf("missing value after %s", e.op)
+	}
+
+	if e.literal, err = parseLiteral(literalText); err != nil {
+		return nil, err
+	}
+
+	if e.op == OpMatch {
+		pattern, ok := e.literal.(string)
+		if !ok {
+			return nil, errors.New("=~ requires a regular expression string")
+		}
+
+		if e.re, err = regexp.Compile(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+
+// String returns the expression as it was parsed.
+func (e *Expression) String() string {
+	return e.original
+}
+
+// Operator returns the operator of the expression.
+func (e *Expression) Operator() Operator {
+	return e.op
+}
+
+// Literal returns the literal the selected values are compared with.
+func (e *Expression) Literal() any {
+	return e.literal
+}
+
+// MatchJSON reports whether the JSON document matches the expression.
+func (e *Expression) MatchJSON(data []byte) bool {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+
+	return e.Match(doc)
+}
+
+// Match reports whether any value selected by the path of a decoded JSON
+// document satisfies the comparison.
+func (e *Expression) Match(doc any) bool {
+	for _, v := range selectValues(doc, e.path) {
+		if e.compare(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *Expression) compare(v any) bool {
+	switch e.op {
+	case OpExists:
+		return v != nil
+	case OpEqual:
+		return equal(v, e.literal)
+	case OpNotEqual:
+		return !equal(v, e.literal)
+	case OpMatch:
+		return e.re.MatchString(text(v))
+	}
+
+	c, ok := order(v, e.literal)
+	if !ok {
+		return false
+	}
+
+	switch e.op {
+	case OpGreater:
+		return c > 0
+	case OpGreaterEqual:
+		return c >= 0
+	case OpLess:
+		return c < 0
+	case OpLessEqual:
+		return c <= 0
+	}
+
+	return false
+}
+
+func equal(v, literal any) bool {
+	switch l := literal.(type) {
+	case float64:
+		n, ok := v.(float64)
+		return ok && n == l
+	case nil:
+		return v == nil
+	case map[string]any, []any:
+		// Objects and arrays are not comparable with ==.
+		return reflect.DeepEqual(v, literal)
+	default:
+		return v == literal
+	}
+}
+
+// order compares numbers or strings, other values cannot be ordered.
+func order(v, literal any) (int, bool) {
+	switch l := literal.(type) {
+	case float64:
+		if n, ok := v.(float64); ok {
+			switch {
+			case n < l:
+				return -1, true
+			case n > l:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	case string:
+		if s, ok := v.(string); ok {
+			return strings.Compare(s, l), true
+		}
+	}
+
+	return 0, false
+}
+
+func text(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case nil:
+		return "null"
+	case map[string]any, []any:
+		data, _ := json.Marshal(t)
+		return string(data)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func selectValues(doc any, path []any) []any {
+	values := []any{doc}
+
+	for _, segment := range path {
+		var next []any
+
+		for _, v := range values {
+			switch typed := v.(type) {
+			case map[string]any:
+				if segment == wildcard {
+					for _, member := range typed {
+						next = append(next, member)
+					}
+				} else if name, ok := segment.(string); ok {
+					if member, ok := typed[name]; ok {
+						next = append(next, member)
+					}
+				}
+			case []any:
+				if segment == wildcard {
+					next = append(next, typed...)
+				} else if i, ok := segment.(int); ok {
+					if i < 0 {
+						i += len(typed)
+					}
+					if i >= 0 && i < len(typed) {
+						next = append(next, typed[i])
+					}
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+// findOperator returns the position of the first operator outside of the
+// bracketed segments of the path.
+func findOperator(s string) (int, Operator) {
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"':
+			quote = c
+			continue
+		case c == '[':
+			depth++
+			continue
+		case c == ']':
+			depth--
+			continue
+		}
+
+		if depth > 0 {
+			continue
+		}
+
+		for _, op := range operators {
+			if strings.HasPrefix(s[i:], string(op)) {
+				return i, op
+			}
+		}
+	}
+
+	return -1, OpExists
+}
+
+func parsePath(s string) ([]any, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, errors.New("path must start with $")
+	}
+
+	var path []any
+	rest := s[1:]
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			name := strings.TrimSpace(rest[:end])
+			if name == "" {
+				return nil, fmt.Errorf("missing name in path %s", s)
+			}
+
+			path = append(path, name)
+			rest = rest[end:]
+		case '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("missing ] in path %s", s)
+			}
+
+			segment, err := parseBracketSegment(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, err
+			}
+
+			path = append(path, segment)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in path %s", rest[0], s)
+		}
+	}
+
+	return path, nil
+}
+
+func closingBracket(s string) int {
+	var quote byte
+
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+func parseBracketSegment(s string) (any, error) {
+	if s == wildcard {
+		return wildcard, nil
+	}
+
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path segment [%s]", s)
+	}
+
+	return i, nil
+}
+
+// parseLiteral parses a JSON literal; strings may also be single-quoted.
+func parseLiteral(s string) (any, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("invalid value %s", s)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,161 @@
+package jsonpath
+
+import (
+	"testing"
+)
+
+const document = `{
+	"order": {"id": 42, "status": "FAILED", "paid": false, "note": null},
+	"items": [{"sku": "ABC-1", "qty": 2}, {"sku": "XYZ-9", "qty": 10}],
+	"tags": [],
+	"meta": {},
+	"dotted.key": "yes"
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr    string
+		op      Operator
+		literal any
+		wantErr bool
+	}{
+		{expr: "$.order.status", op: OpExists},
+		{expr: `$.order.status == "FAILED"`, op: OpEqual, literal: "FAILED"},
+		{expr: "$.order.status != 'OK'", op: OpNotEqual, literal: "OK"},
+		{expr: "$.order.id >= 10", op: OpGreaterEqual, literal: 10.0},
+		{expr: "$.order.id <= 10", op: OpLessEqual, literal: 10.0},
+		{expr: "$.order.id > 10", op: OpGreater, literal: 10.0},
+		{expr: "$.order.id < 10", op: OpLess, literal: 10.0},
+		{expr: `$.items[*].sku =~ "^ABC"`, op: OpMatch, literal: "^ABC"},
+		{expr: `$['dotted.key'] == "yes"`, op: OpEqual, literal: "yes"},
+		{expr: `$["a==b"]`, op: OpExists},
+		{expr: "$.tags == []", op: OpEqual, literal: []any{}},
+		{expr: "order.status", wantErr: true},
+		{expr: "$.order.", wantErr: true},
+		{expr: "$.items[0", wantErr: true},
+		{expr: "$.items[x]", wantErr: true},
+		{expr: "$.order.status ==", wantErr: true},
+		{expr: "$.order.status == FAILED", wantErr: true},
+		{expr: "$.order.id =~ 1", wantErr: true},
+		{expr: `$.order.status =~ "("`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want an error", tt.expr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %s", tt.expr, err)
+			}
+
+			if e.Operator() != tt.op {
+				t.Errorf("operator = %q, want %q", e.Operator(), tt.op)
+			}
+
+			if !equal(e.Literal(), tt.literal) {
+				t.Errorf("literal = %#v, want %#v", e.Literal(), tt.literal)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// Existence.
+		{"$.order.status", true},
+		{"$.order.note", false},
+		{"$.order.missing", false},
+		{"$.missing.deeper", false},
+		{"$.items[5]", false},
+		{"$.items[-1].sku", true},
+		{"$['dotted.key']", true},
+
+		// Comparisons.
+		{`$.order.status == "FAILED"`, true},
+		{`$.order.status == 'FAILED'`, true},
+		{`$.order.status != "FAILED"`, false},
+		{"$.order.id == 42", true},
+		{`$.order.id == "42"`, false},
+		{"$.order.paid == false", true},
+		{"$.order.note == null", true},
+		{"$.order.id > 41", true},
+		{"$.order.id >= 42", true},
+		{"$.order.id < 42", false},
+		{"$.order.id <= 42", true},
+		{`$.order.status > "A"`, true},
+		{`$.order.status > 1`, false},
+		{"$.items[*].qty > 5", true},
+		{"$.items[*].qty > 50", false},
+		{"$.items[1].qty == 10", true},
+
+		// Objects and arrays.
+		{"$.tags == []", true},
+		{"$.meta == {}", true},
+		{"$.items == []", false},
+		{"$.order == {}", false},
+		{`$.items[0] == {"sku": "ABC-1", "qty": 2}`, true},
+		{"$.tags != []", false},
+		{"$.order.status == []", false},
+
+		// Missing paths never match, even with !=.
+		{`$.order.missing == "x"`, false},
+		{`$.order.missing != "x"`, false},
+		{"$.order.missing > 1", false},
+
+		// Regular expressions.
+		{`$.items[*].sku =~ "^XYZ"`, true},
+		{`$.items[*].sku =~ "^QQ"`, false},
+		{`$.order.id =~ "^4\\d$"`, true},
+		{`$.order.note =~ "null"`, true},
+		{`$.meta =~ "^\\{\\}$"`, true},
+		{`$.order.status =~ "(?i)failed"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %s", tt.expr, err)
+			}
+
+			if got := e.MatchJSON([]byte(document)); got != tt.want {
+				t.Errorf("MatchJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchInvalidJSON(t *testing.T) {
+	e, err := Parse("$.a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.MatchJSON([]byte("not json")) {
+		t.Error("MatchJSON() matched an invalid document")
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	tests := map[string]bool{
+		"$.a":        true,
+		"  $.a == 1": true,
+		"order":      false,
+		"":           false,
+	}
+
+	for s, want := range tests {
+		if got := IsExpression(s); got != want {
+			t.Errorf("IsExpression(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
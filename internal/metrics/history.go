@@ -0,0 +1,193 @@
+// Package metrics keeps a rolling in-memory history of resource metrics, so
+// that trends can be displayed next to current values.
+package metrics
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+// MaxAge is how long samples are kept, the longest window that can be displayed.
+const MaxAge = time.Hour
+
+// Sample is the value of a metric at a point in time.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// History holds samples of the metrics of one resource.
+type History struct {
+	series map[string][]Sample
+	mx     sync.Mutex
+}
+
+var (
+	histories   = map[string]*History{}
+	historiesMx sync.Mutex
+)
+
+// For returns the history of a resource identified by the key parts, e.g. a
+// cluster, a virtual host and a queue name. Histories are kept as long as the
+// application runs, so that they are not lost when a view is closed.
+func For(key ...string) *History {
+	k := strings.Join(key, "\x00")
+
+	historiesMx.Lock()
+	defer historiesMx.Unlock()
+
+	h, ok := histories[k]
+	if !ok {
+		h = &History{series: map[string][]Sample{}}
+		histories[k] = h
+	}
+
+	return h
+}
+
+// Add adds samples of a metric. Samples already known are ignored, and
+// samples older than MaxAge before the latest one are dropped.
+func (h *History) Add(metric string, samples ...Sample) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	series := h.series[metric]
+
+	for _, s := range samples {
+		i, found := slices.BinarySearchFunc(series, s.Time, func(e Sample, t time.Time) int { return e.Time.Compare(t) })
+		if !found {
+			series = slices.Insert(series, i, s)
+		}
+	}
+
+	if len(series) > 0 {
+		oldest := series[len(series)-1].Time.Add(-MaxAge)
+		i, _ := slices.BinarySearchFunc(series, oldest, func(e Sample, t time.Time) int { return e.Time.Compare(t) })
+		series = series[i:]
+	}
+
+	h.series[metric] = series
+}
+
+// Values returns n values of a metric over the window ending at its latest
+// sample. Each value is the latest sample of its slot of the window, or of a
+// previous slot when it has none; slots before the first sample are NaN.
+func (h *History) Values(metric string, window time.Duration, n int) []float64 {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+
+	series := h.series[metric]
+	if len(series) == 0 || n == 0 {
+		return values
+	}
+
+	end := series[len(series)-1].Time
+	start := end.Add(-window)
+	slot := window / time.Duration(n)
+
+	last := math.NaN()
+	j := 0
+
+	// Samples before the window give the value of the first slots.
+	for ; j < len(series) && !series[j].Time.After(start); j++ {
+		last = series[j].Value
+	}
+
+	for i := range values {
+		slotEnd := start.Add(slot * time.Duration(i+1))
+		if i == n-1 {
+			slotEnd = end
+		}
+
+		for ; j < len(series) && !series[j].Time.After(slotEnd); j++ {
+			last = series[j].Value
+		}
+
+		values[i] = last
+	}
+
+	return values
+}
+
+// Gauge returns the samples of a value reported by the management API, e.g.
+// a queue length or the memory used by a node. Without samples, e.g. when
+// statistics are disabled, the current value is returned as sampled now.
+func Gauge(details *rabbithole.RateDetails, current float64, now time.Time) []Sample {
+	if details == nil || len(details.Samples) == 0 {
+		return []Sample{{Time: now, Value: current}}
+	}
+
+	return toSamples(details.Samples, func(s rabbithole.RateDetailSample, _ int) (float64, bool) {
+		return float64(s.Sample), true
+	})
+}
+
+// Rate returns rates per second computed from the samples of a counter
+// reported by the management API, e.g. published messages. Without samples,
+// the current rate is returned as sampled now.
+func Rate(details *rabbithole.RateDetails, now time.Time) []Sample {
+	if details == nil {
+		return []Sample{{Time: now, Value: 0}}
+	}
+
+	if len(details.Samples) < 2 {
+		return []Sample{{Time: now, Value: float64(details.Rate)}}
+	}
+
+	samples := details.Samples
+
+	// Samples are ordered from the most recent, each rate is computed from the previous sample.
+	return toSamples(samples[:len(samples)-1], func(s rabbithole.RateDetailSample, i int) (float64, bool) {
+		previous := samples[i+1]
+		elapsed := float64(s.Timestamp-previous.Timestamp) / 1000
+
+		// A counter going down was reset, e.g. when the queue was recreated.
+		if elapsed <= 0 || s.Sample < previous.Sample {
+			return 0, false
+		}
+
+		return float64(s.Sample-previous.Sample) / elapsed, true
+	})
+}
+
+func toSamples(samples []rabbithole.RateDetailSample, valueFn func(s rabbithole.RateDetailSample, i int) (float64, bool)) []Sample {
+	result := make([]Sample, 0, len(samples))
+
+	for i, s := range samples {
+		if v, ok := valueFn(s, i); ok {
+			result = append(result, Sample{Time: time.UnixMilli(s.Timestamp), Value: v})
+		}
+	}
+
+	return result
+}
+
+// Windows lists the windows of history which can be displayed.
+var Windows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour}
+
+// DefaultWindow is the window of history displayed first.
+const DefaultWindow = 5 * time.Minute
+
+// SampleInterval returns the interval of samples to request for a window:
+// the management API keeps 5 second samples for a minute only by default,
+// then 1 minute samples for an hour.
+func SampleInterval(window time.Duration) time.Duration {
+	return max(5*time.Second, window/60)
+}
+
+// NextWindow returns the window following window in Windows, cycling.
+func NextWindow(window time.Duration) time.Duration {
+	i := slices.Index(Windows, window)
+
+	return Windows[(i+1)%len(Windows)]
+}
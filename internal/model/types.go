@@ -46,6 +46,13 @@ type View interface {
 	RemoveActionsListener(listener ViewActionsListener)
 }
 
+// Closer is implemented by views holding resources (e.g. connections) that
+// must be released when the view is closed, not just hidden.
+type Closer interface {
+	// Close releases the view resources.
+	Close()
+}
+
 // Filterer provides methods for filtering views.
 type Filterer interface {
 	// Filter applies a filter to the view.
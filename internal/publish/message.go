@@ -0,0 +1,107 @@
+// Package publish stores publish templates and the history of published
+// messages.
+package publish
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+// Message is a message as entered in the publish dialog.
+type Message struct {
+	Vhost      string `yaml:"vhost,omitempty"`
+	Exchange   string `yaml:"exchange,omitempty"`
+	RoutingKey string `yaml:"routingKey,omitempty"`
+	// Headers are kept apart from the properties, as they are edited apart.
+	Headers map[string]any `yaml:"headers,omitempty"`
+	// Properties use the management API names, e.g. content_type.
+	Properties      map[string]any `yaml:"properties,omitempty"`
+	Payload         string         `yaml:"payload"`
+	PayloadEncoding string         `yaml:"payloadEncoding,omitempty"`
+	// Mandatory asks the broker to return the message if it is not routed.
+	Mandatory bool `yaml:"mandatory,omitempty"`
+}
+
+// PublishOptions returns options to publish the message with the management API.
+func (m *Message) PublishOptions() rabbithole.PublishOptions {
+	props := maps.Clone(m.Properties)
+	if props == nil {
+		props = map[string]any{}
+	}
+
+	if len(m.Headers) > 0 {
+		props["headers"] = m.Headers
+	}
+
+	return rabbithole.PublishOptions{
+		RoutingKey:      m.RoutingKey,
+		Properties:      props,
+		Payload:         m.Payload,
+		PayloadEncoding: m.PayloadEncoding,
+	}
+}
+
+var counter atomic.Int64
+
+// Placeholders lists the placeholders replaced by Expand.
+var Placeholders = []string{"{{uuid}}", "{{timestamp}}", "{{unix}}", "{{counter}}"}
+
+// Expand returns a copy of the message with placeholders replaced in the
+// routing key, the string payload and string headers and properties:
+// {{uuid}} by a random UUID, {{timestamp}} by the current time (RFC 3339),
+// {{unix}} by the current Unix time and {{counter}} by a number incremented
+// for each expanded message.
+func (m *Message) Expand() *Message {
+	now := time.Now()
+	n := counter.Add(1)
+
+	replace := func(s string) string {
+		if !strings.Contains(s, "{{") {
+			return s
+		}
+
+		// Each {{uuid}} gets its own UUID, e.g. for message and correlation IDs.
+		for strings.Contains(s, "{{uuid}}") {
+			s = strings.Replace(s, "{{uuid}}", uuid.NewString(), 1)
+		}
+
+		return strings.NewReplacer(
+			"{{timestamp}}", now.UTC().Format(time.RFC3339),
+			"{{unix}}", strconv.FormatInt(now.Unix(), 10),
+			"{{counter}}", strconv.FormatInt(n, 10),
+		).Replace(s)
+	}
+
+	replaceValues := func(values map[string]any) map[string]any {
+		if values == nil {
+			return nil
+		}
+
+		expanded := make(map[string]any, len(values))
+		for k, v := range values {
+			if s, ok := v.(string); ok {
+				v = replace(s)
+			}
+			expanded[k] = v
+		}
+
+		return expanded
+	}
+
+	e := *m
+	e.RoutingKey = replace(m.RoutingKey)
+	e.Headers = replaceValues(m.Headers)
+	e.Properties = replaceValues(m.Properties)
+
+	if m.PayloadEncoding != "base64" {
+		e.Payload = replace(m.Payload)
+	}
+
+	return &e
+}
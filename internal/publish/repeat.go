@@ -0,0 +1,53 @@
+package publish
+
+import "time"
+
+// Repeat defines how many copies of a message are published and how fast,
+// e.g. to fill a queue.
+type Repeat struct {
+	// Count is the number of copies, 0 to publish until Duration elapsed.
+	Count int
+	// Rate is the target rate in messages per second, 0 for as fast as possible.
+	Rate float64
+	// Duration limits the publishing time, 0 for no limit.
+	Duration time.Duration
+}
+
+// IsRepeated reports whether more than one message is published.
+func (r Repeat) IsRepeated() bool {
+	return r.Count > 1 || r.Duration > 0
+}
+
+// IsDone reports whether n messages published in elapsed time complete the repeat.
+func (r Repeat) IsDone(n int, elapsed time.Duration) bool {
+	if r.Count > 0 && n >= r.Count {
+		return true
+	}
+
+	return r.Duration > 0 && elapsed >= r.Duration
+}
+
+// Progress returns the completed part of the repeat, from 0 to 1.
+func (r Repeat) Progress(n int, elapsed time.Duration) float64 {
+	var progress float64
+
+	if r.Count > 0 {
+		progress = float64(n) / float64(r.Count)
+	}
+
+	if r.Duration > 0 {
+		progress = max(progress, float64(elapsed)/float64(r.Duration))
+	}
+
+	return min(progress, 1)
+}
+
+// Delay returns when the n-th message, starting from 0, is due after the
+// start of publishing to keep the target rate.
+func (r Repeat) Delay(n int) time.Duration {
+	if r.Rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(n) / r.Rate * float64(time.Second))
+}
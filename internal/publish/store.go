@@ -0,0 +1,189 @@
+package publish
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sync"
+	"tbunny/internal/config"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a named message saved from the publish dialog.
+type Template struct {
+	Name    string `yaml:"name"`
+	Message `yaml:",inline"`
+}
+
+// HistoryEntry is a published message.
+type HistoryEntry struct {
+	Time    time.Time `yaml:"time"`
+	Routed  bool      `yaml:"routed"`
+	Message `yaml:",inline"`
+}
+
+type templatesFile struct {
+	Templates []*Template `yaml:"templates"`
+}
+
+type historyFile struct {
+	Messages []*HistoryEntry `yaml:"messages"`
+}
+
+var (
+	templatesFileName string
+	historyDir        string
+	mx                sync.Mutex
+)
+
+func Init(configDir string) {
+	templatesFileName = path.Join(configDir, "templates.yaml")
+	historyDir = path.Join(configDir, "history")
+}
+
+// Templates returns saved templates sorted by name.
+func Templates() ([]*Template, error) {
+	mx.Lock()
+	defer mx.Unlock()
+
+	var f templatesFile
+	if err := load(templatesFileName, &f); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(f.Templates, func(a, b *Template) int { return cmp.Compare(a.Name, b.Name) })
+
+	return f.Templates, nil
+}
+
+// SaveTemplate saves a template, replacing the template with the same name.
+func SaveTemplate(t *Template) error {
+	if t.Name == "" {
+		return errors.New("template name is required")
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	var f templatesFile
+	if err := load(templatesFileName, &f); err != nil {
+		return err
+	}
+
+	f.Templates = slices.DeleteFunc(f.Templates, func(o *Template) bool { return o.Name == t.Name })
+	f.Templates = append(f.Templates, t)
+
+	return save(templatesFileName, &f)
+}
+
+// DeleteTemplate deletes the template with the given name.
+func DeleteTemplate(name string) error {
+	mx.Lock()
+	defer mx.Unlock()
+
+	var f templatesFile
+	if err := load(templatesFileName, &f); err != nil {
+		return err
+	}
+
+	f.Templates = slices.DeleteFunc(f.Templates, func(o *Template) bool { return o.Name == name })
+
+	return save(templatesFileName, &f)
+}
+
+// History returns the messages published to a cluster, most recent first.
+func History(clusterName string) ([]*HistoryEntry, error) {
+	mx.Lock()
+	defer mx.Unlock()
+
+	var f historyFile
+	err := load(historyFileName(clusterName), &f)
+
+	return f.Messages, err
+}
+
+// AddToHistory records a message published to a cluster.
+func AddToHistory(clusterName string, e *HistoryEntry) error {
+	mx.Lock()
+	defer mx.Unlock()
+
+	fileName := historyFileName(clusterName)
+
+	var f historyFile
+	if err := load(fileName, &f); err != nil {
+		return err
+	}
+
+	f.Messages = slices.Insert(f.Messages, 0, e)
+	if size := historySize(); len(f.Messages) > size {
+		f.Messages = f.Messages[:size]
+	}
+
+	return save(fileName, &f)
+}
+
+// DeleteFromHistory removes a message from the history of a cluster.
+func DeleteFromHistory(clusterName string, t time.Time) error {
+	mx.Lock()
+	defer mx.Unlock()
+
+	fileName := historyFileName(clusterName)
+
+	var f historyFile
+	if err := load(fileName, &f); err != nil {
+		return err
+	}
+
+	f.Messages = slices.DeleteFunc(f.Messages, func(e *HistoryEntry) bool { return e.Time.Equal(t) })
+
+	return save(fileName, &f)
+}
+
+// historySize returns the number of published messages remembered per cluster.
+func historySize() int {
+	if c := config.Current(); c != nil && c.Publish.HistorySize > 0 {
+		return c.Publish.HistorySize
+	}
+
+	return config.DefaultHistorySize
+}
+
+func historyFileName(clusterName string) string {
+	return path.Join(historyDir, clusterName+".yaml")
+}
+
+func load(fileName string, v any) error {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err = yaml.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", fileName, err)
+	}
+
+	return nil
+}
+
+func save(fileName string, v any) error {
+	content, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(path.Dir(fileName), 0755); err != nil {
+		return err
+	}
+
+	// Messages may contain sensitive data.
+	return os.WriteFile(fileName, content, 0600)
+}
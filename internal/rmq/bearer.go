@@ -0,0 +1,138 @@
+package rmq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// tokenRefreshMargin is how long before its expiry a token is replaced.
+const tokenRefreshMargin = 30 * time.Second
+
+// ErrTokenRejected is returned when the management API rejects the bearer token.
+var ErrTokenRejected = errors.New("OAuth 2 token rejected by the management API")
+
+// TokenRejectedError is returned when the management API rejects the bearer
+// token, with the reason given by the API, if any. It matches ErrTokenRejected.
+type TokenRejectedError struct {
+	Reason string
+}
+
+func (e *TokenRejectedError) Error() string {
+	if e.Reason == "" {
+		return ErrTokenRejected.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrTokenRejected, e.Reason)
+}
+
+func (e *TokenRejectedError) Is(target error) bool {
+	return target == ErrTokenRejected
+}
+
+// BearerTransport authenticates the requests to the management API with an
+// OAuth 2 bearer token instead of basic authentication, for brokers using the
+// rabbitmq_auth_backend_oauth2 plugin. The token is obtained from its source
+// when there is none, when it is about to expire, or after it was rejected.
+type BearerTransport struct {
+	source oauth2.TokenSource
+	base   http.RoundTripper
+	token  *oauth2.Token
+	mx     sync.Mutex
+}
+
+// NewBearerTransport returns a transport adding the tokens of source to the
+// requests sent through base, or http.DefaultTransport if base is nil.
+func NewBearerTransport(source oauth2.TokenSource, base http.RoundTripper) *BearerTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &BearerTransport{
+		source: source,
+		base:   base,
+	}
+}
+
+// Token returns the current token, obtaining a new one if needed.
+func (t *BearerTransport) Token() (*oauth2.Token, error) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.token != nil && (t.token.Expiry.IsZero() || time.Until(t.token.Expiry) > tokenRefreshMargin) {
+		return t.token, nil
+	}
+
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OAuth 2 token: %w", err)
+	}
+
+	t.token = token
+
+	return token, nil
+}
+
+// invalidate drops the token, so that the next request obtains a new one.
+func (t *BearerTransport) invalidate(token *oauth2.Token) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.token == token {
+		t.token = nil
+	}
+}
+
+func (t *BearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	// The request must not be modified, and replaces the basic
+	// authentication set by the client.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	t.invalidate(token)
+
+	reason := rejectionReason(resp.Body)
+	_ = resp.Body.Close()
+
+	return nil, &TokenRejectedError{Reason: reason}
+}
+
+// rejectionReason returns the reason of a 401 response of the management
+// API, e.g. "token expired".
+func rejectionReason(body io.Reader) string {
+	var rme struct {
+		Error  string `json:"error"`
+		Reason string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(body, 4096)).Decode(&rme); err != nil {
+		return ""
+	}
+
+	if rme.Reason != "" {
+		return strings.TrimSpace(rme.Reason)
+	}
+
+	return rme.Error
+}
@@ -0,0 +1,132 @@
+package rmq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// countingTokenSource returns tok1, tok2... expiring after lifetime.
+type countingTokenSource struct {
+	issued   atomic.Int32
+	lifetime time.Duration
+}
+
+func (s *countingTokenSource) Token() (*oauth2.Token, error) {
+	n := s.issued.Add(1)
+
+	return &oauth2.Token{
+		AccessToken: fmt.Sprintf("tok%d", n),
+		Expiry:      time.Now().Add(s.lifetime),
+	}, nil
+}
+
+func TestBearerTransportReusesToken(t *testing.T) {
+	source := &countingTokenSource{lifetime: time.Hour}
+	transport := NewBearerTransport(source, nil)
+
+	for range 3 {
+		token, err := transport.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if token.AccessToken != "tok1" {
+			t.Fatalf("token = %s, want tok1 reused", token.AccessToken)
+		}
+	}
+}
+
+func TestBearerTransportRefreshesBeforeExpiry(t *testing.T) {
+	// Tokens expire within the refresh margin, so each one is replaced.
+	source := &countingTokenSource{lifetime: tokenRefreshMargin / 2}
+	transport := NewBearerTransport(source, nil)
+
+	for i := 1; i <= 3; i++ {
+		token, err := transport.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := fmt.Sprintf("tok%d", i); token.AccessToken != want {
+			t.Fatalf("token = %s, want %s", token.AccessToken, want)
+		}
+	}
+}
+
+func TestBearerTransportRejectedToken(t *testing.T) {
+	var reject atomic.Bool
+	var authorizations []string
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+		if reject.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"error":"not_authorized","reason":"token expired"}`)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, `[{"name":"/"}]`)
+	}))
+	defer api.Close()
+
+	source := &countingTokenSource{lifetime: time.Hour}
+
+	client, err := NewTLSClient(api.URL, "guest", "guest", NewBearerTransport(source, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.ListVhosts(); err != nil {
+		t.Fatalf("ListVhosts() failed: %s", err)
+	}
+
+	reject.Store(true)
+
+	_, err = client.ListVhosts()
+	if !errors.Is(err, ErrTokenRejected) {
+		t.Fatalf("ListVhosts() error = %v, want ErrTokenRejected", err)
+	}
+
+	var rejected *TokenRejectedError
+	if !errors.As(err, &rejected) || rejected.Reason != "token expired" {
+		t.Errorf("rejection = %v, want the reason of the API", rejected)
+	}
+
+	reject.Store(false)
+
+	if _, err = client.ListVhosts(); err != nil {
+		t.Fatalf("ListVhosts() failed: %s", err)
+	}
+
+	want := []string{"Bearer tok1", "Bearer tok1", "Bearer tok2"}
+	if fmt.Sprint(authorizations) != fmt.Sprint(want) {
+		t.Errorf("authorizations = %v, want %v with a new token after the rejection", authorizations, want)
+	}
+}
+
+func TestBearerTransportTokenError(t *testing.T) {
+	source := oauth2.TokenSource(failingTokenSource{})
+
+	client, err := NewTLSClient("http://127.0.0.1:1", "", "", NewBearerTransport(source, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = client.ListVhosts(); err == nil || errors.Is(err, ErrTokenRejected) {
+		t.Errorf("ListVhosts() error = %v, want the token source error", err)
+	}
+}
+
+type failingTokenSource struct{}
+
+func (failingTokenSource) Token() (*oauth2.Token, error) {
+	return nil, errors.New("token endpoint unavailable")
+}
@@ -0,0 +1,77 @@
+package rmq
+
+import (
+	"net/url"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+// ChannelInfo extends rabbit-hole channel information with the fields that
+// it doesn't decode: state, message rates and consumers.
+type ChannelInfo struct {
+	rabbithole.ChannelInfo
+
+	// Channel state: running, flow, idle, etc.
+	State string `json:"state,omitempty"`
+	// Prefetch limit shared by all consumers of the channel
+	GlobalPrefetchCount int `json:"global_prefetch_count,omitempty"`
+
+	MessageStats *rabbithole.MessageStats `json:"message_stats,omitempty"`
+
+	// Consumers of the channel, only returned for a single channel
+	ConsumerDetails []ChannelConsumerDetail `json:"consumer_details,omitempty"`
+}
+
+// ChannelConsumerDetail describes a consumer of a channel.
+type ChannelConsumerDetail struct {
+	ConsumerTag   string                    `json:"consumer_tag"`
+	Queue         rabbithole.BriefQueueInfo `json:"queue"`
+	AckRequired   bool                      `json:"ack_required"`
+	Exclusive     bool                      `json:"exclusive"`
+	PrefetchCount int                       `json:"prefetch_count"`
+	Active        bool                      `json:"active"`
+	ActiveStatus  string                    `json:"activity_status,omitempty"`
+	Arguments     map[string]any            `json:"arguments,omitempty"`
+}
+
+// ListChannels returns all channels in the cluster.
+func (c *Client) ListChannels() (rec []ChannelInfo, err error) {
+	return listChannels(c, "channels")
+}
+
+// ListChannelsIn returns channels in the given virtual host.
+func (c *Client) ListChannelsIn(vhost string) (rec []ChannelInfo, err error) {
+	return listChannels(c, "vhosts/"+url.PathEscape(vhost)+"/channels")
+}
+
+// ListConnectionChannels returns channels of the given connection.
+func (c *Client) ListConnectionChannels(connection string) (rec []ChannelInfo, err error) {
+	return listChannels(c, "connections/"+url.PathEscape(connection)+"/channels")
+}
+
+// GetChannel returns information about a channel, including its consumers.
+func (c *Client) GetChannel(name string) (rec *ChannelInfo, err error) {
+	req, err := newGETRequest(c, "channels/"+url.PathEscape(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = executeAndParseRequest(c, req, &rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+func listChannels(c *Client, path string) (rec []ChannelInfo, err error) {
+	req, err := newGETRequest(c, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = executeAndParseRequest(c, req, &rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
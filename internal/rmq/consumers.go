@@ -0,0 +1,40 @@
+package rmq
+
+import (
+	"net/url"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+// ConsumerInfo extends rabbit-hole consumer information with the activity flags.
+type ConsumerInfo struct {
+	rabbithole.ConsumerInfo
+
+	// Is the consumer active (single active consumer or exclusive)?
+	Active bool `json:"active"`
+	// Consumer activity status: up, single_active, waiting, suspected_down
+	ActivityStatus string `json:"activity_status,omitempty"`
+}
+
+// ListConsumers returns all consumers in the cluster.
+func (c *Client) ListConsumers() (rec []ConsumerInfo, err error) {
+	return listConsumers(c, "consumers")
+}
+
+// ListConsumersIn returns consumers in the given virtual host.
+func (c *Client) ListConsumersIn(vhost string) (rec []ConsumerInfo, err error) {
+	return listConsumers(c, "consumers/"+url.PathEscape(vhost))
+}
+
+func listConsumers(c *Client, path string) (rec []ConsumerInfo, err error) {
+	req, err := newGETRequest(c, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = executeAndParseRequest(c, req, &rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,233 @@
+package rmq
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// deathHeaders are added by the broker when a message is dead-lettered.
+var deathHeaders = []string{
+	"x-death",
+	"x-first-death-exchange", "x-first-death-queue", "x-first-death-reason",
+	"x-last-death-exchange", "x-last-death-queue", "x-last-death-reason",
+}
+
+// Death is an entry of the x-death header: where and why a message was dead-lettered.
+type Death struct {
+	Queue       string
+	Reason      string
+	Exchange    string
+	RoutingKeys []string
+	Count       int64
+}
+
+// LastDeath returns the most recent x-death entry of the message.
+func (m *FetchedMessage) LastDeath() (Death, bool) {
+	deaths, ok := m.Properties.Headers["x-death"].([]any)
+	if !ok || len(deaths) == 0 {
+		return Death{}, false
+	}
+
+	// Messages fetched through the management API have plain maps, messages
+	// received over AMQP have tables.
+	var entry map[string]any
+	switch typed := deaths[0].(type) {
+	case map[string]any:
+		entry = typed
+	case amqp.Table:
+		entry = typed
+	default:
+		return Death{}, false
+	}
+
+	d := Death{
+		Queue:    stringValue(entry["queue"]),
+		Reason:   stringValue(entry["reason"]),
+		Exchange: stringValue(entry["exchange"]),
+	}
+
+	if keys, ok := entry["routing-keys"].([]any); ok {
+		for _, k := range keys {
+			d.RoutingKeys = append(d.RoutingKeys, stringValue(k))
+		}
+	}
+
+	switch count := entry["count"].(type) {
+	case float64:
+		d.Count = int64(count)
+	case int64:
+		d.Count = count
+	}
+
+	return d, true
+}
+
+// DecodedPayload returns the payload bytes, decoding base64 if needed.
+func (m *FetchedMessage) DecodedPayload() ([]byte, error) {
+	if m.PayloadEncoding == PayloadEncodingBase64 {
+		return base64.StdEncoding.DecodeString(m.Payload)
+	}
+
+	return []byte(m.Payload), nil
+}
+
+// fingerprint identifies a message fetched through the management API and
+// the same message received over AMQP.
+func (m *FetchedMessage) fingerprint() (string, error) {
+	payload, err := m.DecodedPayload()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, s := range []string{m.Exchange, m.RoutingKey, m.Properties.MessageId, m.Properties.CorrelationId} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	h.Write(payload)
+
+	return string(h.Sum(nil)), nil
+}
+
+// RequeueOptions control how dead-lettered messages are requeued.
+type RequeueOptions struct {
+	// Window is the number of messages at the head of the queue searched for the messages.
+	Window int
+	// StripDeaths removes x-death and related headers from requeued messages.
+	StripDeaths bool
+	// ProgressFn is called after each requeued message.
+	ProgressFn func(requeued int)
+}
+
+// RequeueDeadLetters takes the given messages from the head of a dead-letter
+// queue and publishes them to the exchange and routing key they were
+// originally published to. A message is acknowledged (removed from the
+// queue) only after the broker confirmed it was routed; other messages
+// are returned to the queue. It returns the requeued messages.
+func RequeueDeadLetters(conn *amqp.Connection, queue string, messages []*FetchedMessage, opts RequeueOptions) ([]*FetchedMessage, error) {
+	pending := make(map[string][]*FetchedMessage, len(messages))
+	for _, m := range messages {
+		fp, err := m.fingerprint()
+		if err != nil {
+			return nil, err
+		}
+		pending[fp] = append(pending[fp], m)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err = ch.Confirm(false); err != nil {
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+
+	// Messages which are not requeued are held until the end, so that they
+	// are not fetched again, and then returned to the queue in one go (a
+	// multiple nack only affects messages which are not acknowledged yet).
+	var lastHeld uint64
+	defer func() {
+		if lastHeld > 0 {
+			_ = ch.Nack(lastHeld, true, true)
+		}
+	}()
+
+	var requeued []*FetchedMessage
+
+	for i := 0; i < opts.Window && len(requeued) < len(messages); i++ {
+		d, ok, err := ch.Get(queue, false)
+		if err != nil {
+			return requeued, fmt.Errorf("failed to get message: %w", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		fm := newFetchedMessage(d)
+		fp, _ := fm.fingerprint()
+
+		candidates := pending[fp]
+		death, dead := fm.LastDeath()
+
+		if len(candidates) == 0 || !dead || len(death.RoutingKeys) == 0 {
+			lastHeld = d.DeliveryTag
+			continue
+		}
+
+		if err = republish(ch, returns, d, death, opts.StripDeaths); err != nil {
+			lastHeld = d.DeliveryTag
+			return requeued, err
+		}
+
+		if err = d.Ack(false); err != nil {
+			return requeued, fmt.Errorf("failed to acknowledge message: %w", err)
+		}
+
+		pending[fp] = candidates[1:]
+		requeued = append(requeued, candidates[0])
+
+		if opts.ProgressFn != nil {
+			opts.ProgressFn(len(requeued))
+		}
+	}
+
+	return requeued, nil
+}
+
+func republish(ch *amqp.Channel, returns <-chan amqp.Return, d amqp.Delivery, death Death, stripDeaths bool) error {
+	headers := d.Headers
+	if stripDeaths {
+		headers = amqp.Table{}
+		for k, v := range d.Headers {
+			if !isDeathHeader(k) {
+				headers[k] = v
+			}
+		}
+	}
+
+	p := amqp.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    d.DeliveryMode,
+		Priority:        d.Priority,
+		CorrelationId:   d.CorrelationId,
+		ReplyTo:         d.ReplyTo,
+		Expiration:      d.Expiration,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Type:            d.Type,
+		UserId:          d.UserId,
+		AppId:           d.AppId,
+		Body:            d.Body,
+	}
+
+	return publishMandatory(ch, returns, death.Exchange, death.RoutingKeys[0], p)
+}
+
+func isDeathHeader(name string) bool {
+	for _, h := range deathHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	return ""
+}
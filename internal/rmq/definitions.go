@@ -0,0 +1,132 @@
+package rmq
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"tbunny/internal/utils"
+)
+
+// DefinitionsSummary is a lightweight view of a definitions file: just enough
+// to tell the user what is going to be imported. Definitions themselves are
+// passed to the broker as is, so that no field gets lost on the way.
+type DefinitionsSummary struct {
+	RabbitVersion string                 `json:"rabbit_version,omitempty"`
+	Users         []NamedDefinition      `json:"users,omitempty"`
+	Vhosts        []NamedDefinition      `json:"vhosts,omitempty"`
+	Permissions   []PermissionDefinition `json:"permissions,omitempty"`
+	Policies      []NamedDefinition      `json:"policies,omitempty"`
+	Parameters    []NamedDefinition      `json:"parameters,omitempty"`
+	Queues        []NamedDefinition      `json:"queues,omitempty"`
+	Exchanges     []NamedDefinition      `json:"exchanges,omitempty"`
+	Bindings      []BindingDefinition    `json:"bindings,omitempty"`
+}
+
+// NamedDefinition is any definition that has a name and, optionally, a virtual host.
+type NamedDefinition struct {
+	Name      string `json:"name"`
+	Vhost     string `json:"vhost,omitempty"`
+	Component string `json:"component,omitempty"`
+}
+
+// BindingDefinition is a binding definition.
+type BindingDefinition struct {
+	Vhost           string `json:"vhost,omitempty"`
+	Source          string `json:"source"`
+	Destination     string `json:"destination"`
+	DestinationType string `json:"destination_type"`
+	RoutingKey      string `json:"routing_key"`
+}
+
+// PermissionDefinition is a user permissions definition.
+type PermissionDefinition struct {
+	User  string `json:"user"`
+	Vhost string `json:"vhost"`
+}
+
+// ParseDefinitionsSummary parses a definitions file into a summary.
+func ParseDefinitionsSummary(data []byte) (*DefinitionsSummary, error) {
+	var s DefinitionsSummary
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// ForVhost returns the summary of the definitions imported into a single
+// virtual host: users, virtual hosts and permissions are not imported, and
+// the other definitions are imported into that virtual host.
+func (s *DefinitionsSummary) ForVhost(vhost string) *DefinitionsSummary {
+	inVhost := func(d NamedDefinition) NamedDefinition {
+		d.Vhost = vhost
+		return d
+	}
+
+	scoped := DefinitionsSummary{
+		RabbitVersion: s.RabbitVersion,
+		Policies:      utils.Map(s.Policies, inVhost),
+		Parameters:    utils.Map(s.Parameters, inVhost),
+		Queues:        utils.Map(s.Queues, inVhost),
+		Exchanges:     utils.Map(s.Exchanges, inVhost),
+		Bindings: utils.Map(s.Bindings, func(d BindingDefinition) BindingDefinition {
+			d.Vhost = vhost
+			return d
+		}),
+	}
+
+	return &scoped
+}
+
+// ExportDefinitions returns definitions of the whole cluster as raw JSON.
+func (c *Client) ExportDefinitions() ([]byte, error) {
+	return c.exportDefinitions("definitions")
+}
+
+// ExportVhostDefinitions returns definitions of a single virtual host as raw JSON.
+func (c *Client) ExportVhostDefinitions(vhost string) ([]byte, error) {
+	return c.exportDefinitions("definitions/" + url.PathEscape(vhost))
+}
+
+// ImportDefinitions uploads definitions of the whole cluster.
+func (c *Client) ImportDefinitions(data []byte) error {
+	return c.importDefinitions("definitions", data)
+}
+
+// ImportVhostDefinitions uploads definitions into a single virtual host.
+func (c *Client) ImportVhostDefinitions(vhost string, data []byte) error {
+	return c.importDefinitions("definitions/"+url.PathEscape(vhost), data)
+}
+
+func (c *Client) exportDefinitions(path string) ([]byte, error) {
+	req, err := newGETRequest(c, path)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := executeRequest(c, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+
+	return io.ReadAll(res.Body)
+}
+
+func (c *Client) importDefinitions(path string, data []byte) error {
+	req, err := newRequestWithBody(c, http.MethodPost, path, data)
+	if err != nil {
+		return err
+	}
+
+	res, err := executeRequest(c, req)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
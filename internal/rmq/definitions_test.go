@@ -0,0 +1,37 @@
+package rmq
+
+import "testing"
+
+func TestDefinitionsSummaryForVhost(t *testing.T) {
+	summary, err := ParseDefinitionsSummary([]byte(`{
+		"rabbit_version": "4.1.0",
+		"users": [{"name": "admin"}],
+		"vhosts": [{"name": "/"}],
+		"permissions": [{"user": "admin", "vhost": "/"}],
+		"policies": [{"name": "ha", "vhost": "/"}],
+		"queues": [{"name": "orders", "vhost": "/"}],
+		"exchanges": [{"name": "events", "vhost": "/"}],
+		"bindings": [{"vhost": "/", "source": "events", "destination": "orders", "destination_type": "queue"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scoped := summary.ForVhost("staging")
+
+	if len(scoped.Users) != 0 || len(scoped.Vhosts) != 0 || len(scoped.Permissions) != 0 {
+		t.Errorf("users, vhosts, permissions = %v, %v, %v, want none", scoped.Users, scoped.Vhosts, scoped.Permissions)
+	}
+
+	if scoped.RabbitVersion != "4.1.0" || len(scoped.Policies) != 1 || len(scoped.Queues) != 1 || len(scoped.Exchanges) != 1 || len(scoped.Bindings) != 1 {
+		t.Fatalf("summary = %+v, want the policies, queues, exchanges and bindings", scoped)
+	}
+
+	if scoped.Queues[0].Vhost != "staging" || scoped.Bindings[0].Vhost != "staging" {
+		t.Errorf("vhosts = %s, %s, want staging", scoped.Queues[0].Vhost, scoped.Bindings[0].Vhost)
+	}
+
+	if summary.Queues[0].Vhost != "/" {
+		t.Errorf("original summary changed: queue vhost = %s", summary.Queues[0].Vhost)
+	}
+}
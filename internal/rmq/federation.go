@@ -0,0 +1,97 @@
+package rmq
+
+import (
+	"net/url"
+)
+
+// FederationUpstreamSetComponent is the name of the runtime parameter component
+// used by federation upstream sets.
+const FederationUpstreamSetComponent = "federation-upstream-set"
+
+// FederationLink is a status of a federation link as reported by `/api/federation-links`.
+// rabbit-hole returns links as untyped maps, so we decode them on our own.
+type FederationLink struct {
+	// Link identifier
+	Id string `json:"id"`
+	// Node the link is running on
+	Node string `json:"node"`
+	// Virtual host the link belongs to
+	Vhost string `json:"vhost"`
+	// Name of the upstream
+	Upstream string `json:"upstream"`
+	// Type of the federated resource: exchange or queue
+	Type string `json:"type"`
+	// Link status: starting, running, error or shutdown
+	Status string `json:"status"`
+	// Upstream URI with credentials stripped
+	Uri string `json:"uri,omitempty"`
+	// Error message if the link has failed
+	Error string `json:"error,omitempty"`
+	// Time the link status last changed
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// Local federated exchange
+	Exchange string `json:"exchange,omitempty"`
+	// Upstream exchange
+	UpstreamExchange string `json:"upstream_exchange,omitempty"`
+	// Local federated queue
+	Queue string `json:"queue,omitempty"`
+	// Upstream queue
+	UpstreamQueue string `json:"upstream_queue,omitempty"`
+
+	// Name of the local connection used by the link
+	LocalConnection string `json:"local_connection,omitempty"`
+}
+
+// FederationUpstreamSet is a named list of federation upstreams.
+type FederationUpstreamSet struct {
+	Name      string                        `json:"name"`
+	Vhost     string                        `json:"vhost"`
+	Upstreams []FederationUpstreamSetMember `json:"value"`
+}
+
+// FederationUpstreamSetMember is an upstream reference in a set. Members may
+// override any upstream definition key, we only need the upstream name.
+type FederationUpstreamSetMember struct {
+	Upstream string `json:"upstream"`
+}
+
+// ListFederationLinkStatuses returns statuses of all federation links in the cluster.
+func (c *Client) ListFederationLinkStatuses() (rec []FederationLink, err error) {
+	return listFederationResource[FederationLink](c, "federation-links")
+}
+
+// ListFederationLinkStatusesIn returns statuses of federation links in the given virtual host.
+func (c *Client) ListFederationLinkStatusesIn(vhost string) (rec []FederationLink, err error) {
+	return listFederationResource[FederationLink](c, "federation-links/"+url.PathEscape(vhost))
+}
+
+// ListFederationUpstreamSets returns all federation upstream sets in the cluster.
+func (c *Client) ListFederationUpstreamSets() (rec []FederationUpstreamSet, err error) {
+	return listFederationResource[FederationUpstreamSet](c, "parameters/"+FederationUpstreamSetComponent)
+}
+
+// ListFederationUpstreamSetsIn returns federation upstream sets in the given virtual host.
+func (c *Client) ListFederationUpstreamSetsIn(vhost string) (rec []FederationUpstreamSet, err error) {
+	return listFederationResource[FederationUpstreamSet](c, "parameters/"+FederationUpstreamSetComponent+"/"+url.PathEscape(vhost))
+}
+
+// DeleteFederationUpstreamSet removes a federation upstream set.
+func (c *Client) DeleteFederationUpstreamSet(vhost, name string) error {
+	_, err := c.DeleteRuntimeParameter(FederationUpstreamSetComponent, vhost, name)
+
+	return err
+}
+
+func listFederationResource[T any](c *Client, path string) (rec []T, err error) {
+	req, err := newGETRequest(c, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = executeAndParseRequest(c, req, &rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,73 @@
+package rmq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+// WriteMessages writes messages as JSON Lines, one message with its
+// properties, routing information and encoded payload per line.
+func WriteMessages(w io.Writer, messages []*FetchedMessage) error {
+	e := json.NewEncoder(w)
+
+	for _, m := range messages {
+		if err := e.Encode(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadMessages reads messages written by WriteMessages.
+func ReadMessages(r io.Reader) ([]*FetchedMessage, error) {
+	var messages []*FetchedMessage
+
+	d := json.NewDecoder(r)
+
+	for {
+		var m FetchedMessage
+
+		err := d.Decode(&m)
+		if errors.Is(err, io.EOF) {
+			return messages, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid message %d: %w", len(messages)+1, err)
+		}
+
+		messages = append(messages, &m)
+	}
+}
+
+// PublishOptions returns options to publish the message again, with its
+// original routing key unless routingKey is set.
+func (m *FetchedMessage) PublishOptions(routingKey string) (rabbithole.PublishOptions, error) {
+	if routingKey == "" {
+		routingKey = m.RoutingKey
+	}
+
+	// Property names of fetched messages are the ones the management API
+	// expects when publishing.
+	content, err := json.Marshal(m.Properties)
+	if err != nil {
+		return rabbithole.PublishOptions{}, err
+	}
+
+	var props map[string]any
+	if err = json.Unmarshal(content, &props); err != nil {
+		return rabbithole.PublishOptions{}, err
+	}
+
+	return rabbithole.PublishOptions{
+		RoutingKey:      routingKey,
+		Properties:      props,
+		Payload:         m.Payload,
+		PayloadEncoding: string(m.PayloadEncoding),
+	}, nil
+}
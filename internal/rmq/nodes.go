@@ -0,0 +1,32 @@
+package rmq
+
+import (
+	"net/url"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+// NodeInfo adds the socket statistics, which rabbit-hole does not decode, to
+// the information about a node.
+type NodeInfo struct {
+	rabbithole.NodeInfo
+
+	SocketsUsed        int                    `json:"sockets_used"`
+	SocketsUsedDetails rabbithole.RateDetails `json:"sockets_used_details"`
+	SocketsTotal       int                    `json:"sockets_total"`
+}
+
+// GetNodeWithSamples returns information about a node, with samples of its
+// statistics (memory, file descriptors, sockets, disk) over the range.
+func (c *Client) GetNodeWithSamples(name string, r SampleRange) (rec *NodeInfo, err error) {
+	req, err := newGETRequest(c, "nodes/"+url.PathEscape(name)+"?"+r.query("node_stats"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = executeAndParseRequest(c, req, &rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,283 @@
+package rmq
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const publishTimeout = 30 * time.Second
+
+// UnroutableError is returned when a mandatory message is not routed to any queue.
+type UnroutableError struct {
+	Exchange   string
+	RoutingKey string
+	Reason     string
+}
+
+func (e *UnroutableError) Error() string {
+	return fmt.Sprintf("message not routed by exchange %q with routing key %s: %s", e.Exchange, e.RoutingKey, e.Reason)
+}
+
+// PublishMandatory publishes a message over AMQP with the mandatory flag and
+// waits for the broker confirmation. It returns an *UnroutableError when the
+// broker returned the message because no queue was bound to receive it.
+func PublishMandatory(conn *amqp.Connection, exchange string, opts rabbithole.PublishOptions) error {
+	p, err := NewPublisher(conn, exchange, 1)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	if err = p.Publish(opts, true); err != nil {
+		return err
+	}
+
+	if err = p.Wait(); err != nil {
+		return err
+	}
+
+	if r := p.lastReturn; r != nil {
+		return &UnroutableError{Exchange: r.Exchange, RoutingKey: r.RoutingKey, Reason: r.ReplyText}
+	}
+
+	return nil
+}
+
+// Publisher publishes messages to an exchange over a single AMQP channel with
+// publisher confirms, e.g. to publish many copies of a message. Up to window
+// messages are published before waiting for their confirmation.
+type Publisher struct {
+	ch         *amqp.Channel
+	returns    <-chan amqp.Return
+	exchange   string
+	window     int
+	pending    []*amqp.DeferredConfirmation
+	confirmed  int
+	returned   int
+	lastReturn *amqp.Return
+}
+
+// NewPublisher opens a channel on the connection to publish messages to the
+// exchange, with at most window unconfirmed messages.
+func NewPublisher(conn *amqp.Connection, exchange string, window int) (*Publisher, error) {
+	window = max(window, 1)
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err = ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	// Messages are returned before their confirmation, so there are at most
+	// window returns to read, which must not block the connection.
+	p := Publisher{
+		ch:       ch,
+		returns:  ch.NotifyReturn(make(chan amqp.Return, window)),
+		exchange: exchange,
+		window:   window,
+	}
+
+	return &p, nil
+}
+
+// Publish publishes a message without waiting for its confirmation, unless
+// the window is full: it then waits for the confirmation of the oldest
+// unconfirmed message. Mandatory messages returned by the broker are counted
+// by Returned.
+func (p *Publisher) Publish(opts rabbithole.PublishOptions, mandatory bool) error {
+	msg, err := newPublishing(opts)
+	if err != nil {
+		return err
+	}
+
+	if len(p.pending) >= p.window {
+		if err = p.waitOldest(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	confirmation, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, p.exchange, opts.RoutingKey, mandatory, false, msg)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	p.pending = append(p.pending, confirmation)
+
+	return nil
+}
+
+// Wait waits for the confirmation of all published messages.
+func (p *Publisher) Wait() error {
+	for len(p.pending) > 0 {
+		if err := p.waitOldest(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Confirmed returns the number of messages confirmed by the broker.
+func (p *Publisher) Confirmed() int {
+	return p.confirmed
+}
+
+// Returned returns the number of confirmed mandatory messages that were
+// returned by the broker because no queue was bound to receive them.
+func (p *Publisher) Returned() int {
+	return p.returned
+}
+
+// Close closes the channel of the publisher.
+func (p *Publisher) Close() error {
+	return p.ch.Close()
+}
+
+func (p *Publisher) waitOldest() error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	confirmation := p.pending[0]
+	p.pending = p.pending[1:]
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm message: %w", err)
+	}
+
+	if !acked {
+		return errors.New("message was not confirmed by the broker")
+	}
+
+	p.confirmed++
+
+	// The broker sends basic.return before the confirmation, returns of
+	// unconfirmed messages may also be read already.
+	for {
+		select {
+		case r := <-p.returns:
+			p.returned++
+			p.lastReturn = &r
+		default:
+			return nil
+		}
+	}
+}
+
+// publishMandatory publishes a message with the mandatory flag on a channel
+// in confirm mode and waits for its confirmation, e.g. to republish a message
+// on the channel it was fetched from.
+func publishMandatory(ch *amqp.Channel, returns <-chan amqp.Return, exchange, routingKey string, p amqp.Publishing) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	// Mandatory publishing returns unroutable messages instead of dropping them.
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, true, false, p)
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm message: %w", err)
+	}
+
+	if !acked {
+		return errors.New("message was not confirmed by the broker")
+	}
+
+	// The broker sends basic.return before the confirmation.
+	select {
+	case r := <-returns:
+		return &UnroutableError{Exchange: r.Exchange, RoutingKey: r.RoutingKey, Reason: r.ReplyText}
+	default:
+	}
+
+	return nil
+}
+
+// newPublishing converts management API publish options to an AMQP message.
+func newPublishing(opts rabbithole.PublishOptions) (amqp.Publishing, error) {
+	content, err := json.Marshal(opts.Properties)
+	if err != nil {
+		return amqp.Publishing{}, err
+	}
+
+	var props FetchedMessageProperties
+	if err = json.Unmarshal(content, &props); err != nil {
+		return amqp.Publishing{}, fmt.Errorf("invalid properties: %w", err)
+	}
+
+	body := []byte(opts.Payload)
+	if opts.PayloadEncoding == string(PayloadEncodingBase64) {
+		if body, err = base64.StdEncoding.DecodeString(opts.Payload); err != nil {
+			return amqp.Publishing{}, fmt.Errorf("invalid base64 payload: %w", err)
+		}
+	}
+
+	p := amqp.Publishing{
+		Headers:         props.Headers,
+		ContentType:     props.ContentType,
+		ContentEncoding: props.ContentEncoding,
+		DeliveryMode:    uint8(props.DeliveryMode),
+		Priority:        uint8(props.Priority),
+		CorrelationId:   props.CorrelationId,
+		ReplyTo:         props.ReplyTo,
+		Expiration:      props.Expiration,
+		MessageId:       props.MessageId,
+		Type:            props.Type,
+		UserId:          props.UserId,
+		AppId:           props.AppId,
+		Body:            body,
+	}
+
+	// Headers are taken as they are, JSON would turn integers into floats.
+	if headers, ok := tableValue(opts.Properties["headers"]).(amqp.Table); ok {
+		p.Headers = headers
+	}
+
+	if props.Timestamp != 0 {
+		p.Timestamp = time.Unix(props.Timestamp, 0)
+	}
+
+	return p, nil
+}
+
+// tableValue converts nested maps of a header value to tables, which AMQP
+// requires for field tables, also inside arrays.
+func tableValue(v any) any {
+	switch v := v.(type) {
+	case amqp.Table:
+		return tableValue(map[string]any(v))
+	case map[string]any:
+		table := make(amqp.Table, len(v))
+		for k, value := range v {
+			table[k] = tableValue(value)
+		}
+
+		return table
+	case []any:
+		values := make([]any, len(v))
+		for i, value := range v {
+			values[i] = tableValue(value)
+		}
+
+		return values
+	}
+
+	return v
+}
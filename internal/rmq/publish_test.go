@@ -0,0 +1,54 @@
+package rmq
+
+import (
+	"testing"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestNewPublishingNestedHeaders(t *testing.T) {
+	opts := rabbithole.PublishOptions{
+		Properties: rabbithole.Properties{
+			"delivery_mode": 2,
+			"headers": map[string]any{
+				"retries": 3,
+				"trace":   map[string]any{"id": "abc", "span": map[string]any{"depth": 1}},
+				"hops":    []any{map[string]any{"node": "rabbit@a"}, "rabbit@b"},
+				"origin":  amqp.Table{"region": "eu"},
+			},
+		},
+		Payload: "hello",
+	}
+
+	p, err := newPublishing(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = p.Headers.Validate(); err != nil {
+		t.Fatalf("headers are not a valid AMQP table: %s", err)
+	}
+
+	if p.Headers["retries"] != 3 {
+		t.Errorf("retries = %#v, want the integer 3", p.Headers["retries"])
+	}
+
+	trace, ok := p.Headers["trace"].(amqp.Table)
+	if !ok {
+		t.Fatalf("trace = %T, want amqp.Table", p.Headers["trace"])
+	}
+
+	if _, ok = trace["span"].(amqp.Table); !ok {
+		t.Errorf("trace.span = %T, want amqp.Table", trace["span"])
+	}
+
+	hops := p.Headers["hops"].([]any)
+	if _, ok = hops[0].(amqp.Table); !ok {
+		t.Errorf("hops[0] = %T, want amqp.Table", hops[0])
+	}
+
+	if p.DeliveryMode != 2 || string(p.Body) != "hello" {
+		t.Errorf("delivery mode = %d, body = %q, want 2 and hello", p.DeliveryMode, p.Body)
+	}
+}
@@ -93,7 +93,17 @@ type OwnerPidDetailsWrapper struct {
 
 // GetQueue returns information about a queue.
 func (c *Client) GetQueue(vhost, queue string) (rec *DetailedQueueInfo, err error) {
-	req, err := newGETRequest(c, "queues/"+url.PathEscape(vhost)+"/"+url.PathEscape(queue))
+	return c.getQueue("queues/" + url.PathEscape(vhost) + "/" + url.PathEscape(queue))
+}
+
+// GetQueueWithSamples returns information about a queue, with samples of its
+// lengths and message rates over the range.
+func (c *Client) GetQueueWithSamples(vhost, queue string, r SampleRange) (rec *DetailedQueueInfo, err error) {
+	return c.getQueue("queues/" + url.PathEscape(vhost) + "/" + url.PathEscape(queue) + "?" + r.query("lengths", "msg_rates"))
+}
+
+func (c *Client) getQueue(path string) (rec *DetailedQueueInfo, err error) {
+	req, err := newGETRequest(c, path)
 	if err != nil {
 		return nil, err
 	}
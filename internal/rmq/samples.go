@@ -0,0 +1,27 @@
+package rmq
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SampleRange asks the management API for samples of the last Age, one
+// every Incr, in the details of statistics (e.g. messages_ready_details).
+type SampleRange struct {
+	Age  time.Duration
+	Incr time.Duration
+}
+
+// query returns the query string requesting samples of the given kinds of
+// statistics, e.g. lengths, msg_rates or node_stats.
+func (r SampleRange) query(kinds ...string) string {
+	values := url.Values{}
+
+	for _, kind := range kinds {
+		values.Set(kind+"_age", strconv.Itoa(int(r.Age.Seconds())))
+		values.Set(kind+"_incr", strconv.Itoa(int(r.Incr.Seconds())))
+	}
+
+	return values.Encode()
+}
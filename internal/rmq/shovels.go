@@ -0,0 +1,82 @@
+package rmq
+
+import (
+	"net/url"
+)
+
+// ShovelStatus is a runtime status of a shovel as reported by `/api/shovels`.
+// rabbit-hole only exposes the name and the state, so we need our own type
+// to get endpoints, counters and the termination reason.
+type ShovelStatus struct {
+	// Shovel name
+	Name string `json:"name"`
+	// Virtual host this shovel belongs to
+	Vhost string `json:"vhost"`
+	// Type of the shovel: dynamic or static
+	Type string `json:"type"`
+	// Node the shovel is running on
+	Node string `json:"node,omitempty"`
+	// State of the shovel: starting, running or terminated
+	State string `json:"state"`
+	// Reason the shovel was terminated, if it was
+	Reason string `json:"reason,omitempty"`
+	// Flow control status of the shovel
+	BlockedStatus string `json:"blocked_status,omitempty"`
+	// Time the shovel state last changed
+	Timestamp string `json:"timestamp"`
+
+	SourceURI         string `json:"src_uri,omitempty"`
+	SourceProtocol    string `json:"src_protocol,omitempty"`
+	SourceQueue       string `json:"src_queue,omitempty"`
+	SourceExchange    string `json:"src_exchange,omitempty"`
+	SourceExchangeKey string `json:"src_exchange_key,omitempty"`
+
+	DestinationURI         string `json:"dest_uri,omitempty"`
+	DestinationProtocol    string `json:"dest_protocol,omitempty"`
+	DestinationQueue       string `json:"dest_queue,omitempty"`
+	DestinationExchange    string `json:"dest_exchange,omitempty"`
+	DestinationExchangeKey string `json:"dest_exchange_key,omitempty"`
+
+	// Number of messages forwarded so far
+	Forwarded int64 `json:"forwarded,omitempty"`
+	// Number of messages left to transfer before the shovel is deleted
+	Remaining int64 `json:"remaining,omitempty"`
+}
+
+// ListShovelStatuses returns runtime statuses of all shovels in the cluster.
+func (c *Client) ListShovelStatuses() (rec []ShovelStatus, err error) {
+	return c.listShovelStatuses("shovels")
+}
+
+// ListShovelStatusesIn returns runtime statuses of shovels in the given virtual host.
+func (c *Client) ListShovelStatusesIn(vhost string) (rec []ShovelStatus, err error) {
+	return c.listShovelStatuses("shovels/" + url.PathEscape(vhost))
+}
+
+// RestartShovel restarts a dynamic shovel.
+func (c *Client) RestartShovel(vhost, shovel string) error {
+	req, err := newRequestWithBody(c, "DELETE", "shovels/vhost/"+url.PathEscape(vhost)+"/"+url.PathEscape(shovel)+"/restart", nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := executeRequest(c, req)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (c *Client) listShovelStatuses(path string) (rec []ShovelStatus, err error) {
+	req, err := newGETRequest(c, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = executeAndParseRequest(c, req, &rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
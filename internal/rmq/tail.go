@@ -0,0 +1,181 @@
+package rmq
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// tailBufferSize is the number of received messages buffered before the
+	// consumer blocks.
+	tailBufferSize = 256
+
+	// streamPrefetchCount is the prefetch required by stream consumers.
+	streamPrefetchCount = 100
+)
+
+// TailBinding binds the queue of a tail to an exchange.
+type TailBinding struct {
+	Exchange   string
+	RoutingKey string
+	Arguments  map[string]any
+}
+
+// Tail receives copies of messages over AMQP 0-9-1 without taking them away
+// from other consumers: either through an exclusive auto-delete queue bound
+// to exchanges, or by reading a stream.
+type Tail struct {
+	conn     *amqp.Connection
+	messages chan *FetchedMessage
+	err      error
+	once     sync.Once
+}
+
+// TailExchanges declares an exclusive, auto-delete queue with the given
+// bindings and consumes it. The tail takes ownership of the connection.
+func TailExchanges(conn *amqp.Connection, bindings []TailBinding) (*Tail, error) {
+	if len(bindings) == 0 {
+		_ = conn.Close()
+		return nil, errors.New("nothing to bind to")
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	q, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to declare tail queue: %w", err)
+	}
+
+	for _, b := range bindings {
+		if err = ch.QueueBind(q.Name, b.RoutingKey, b.Exchange, false, b.Arguments); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to bind tail queue to exchange %s: %w", b.Exchange, err)
+		}
+	}
+
+	deliveries, err := ch.Consume(q.Name, "", true, true, false, false, nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to consume tail queue: %w", err)
+	}
+
+	return startTail(conn, ch, deliveries, nil), nil
+}
+
+// TailStream consumes a stream queue starting at the given offset ("first",
+// "last", "next", a numeric offset...). Reading a stream does not remove
+// messages from it. The tail takes ownership of the connection.
+func TailStream(conn *amqp.Connection, queue string, offset any) (*Tail, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err = ch.Qos(streamPrefetchCount, 0, false); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	deliveries, err := ch.Consume(queue, "", false, false, false, false, amqp.Table{"x-stream-offset": offset})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to consume stream %s: %w", queue, err)
+	}
+
+	return startTail(conn, ch, deliveries, func(d amqp.Delivery) { _ = d.Ack(false) }), nil
+}
+
+func startTail(conn *amqp.Connection, ch *amqp.Channel, deliveries <-chan amqp.Delivery, ackFn func(amqp.Delivery)) *Tail {
+	t := &Tail{
+		conn:     conn,
+		messages: make(chan *FetchedMessage, tailBufferSize),
+	}
+
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	go func() {
+		defer close(t.messages)
+
+		for d := range deliveries {
+			if ackFn != nil {
+				ackFn(d)
+			}
+
+			t.messages <- newFetchedMessage(d)
+		}
+
+		if err, ok := <-closed; ok && err != nil {
+			t.err = err
+		}
+	}()
+
+	return t
+}
+
+// Messages returns the channel of received messages. It is closed when the
+// tail is closed or the broker closes the channel or the connection.
+func (t *Tail) Messages() <-chan *FetchedMessage {
+	return t.messages
+}
+
+// Err returns the reason the channel or connection was closed by the broker,
+// once Messages is closed.
+func (t *Tail) Err() error {
+	return t.err
+}
+
+// Close closes the connection; the tail queue is deleted by the broker.
+func (t *Tail) Close() {
+	t.once.Do(func() {
+		_ = t.conn.Close()
+	})
+}
+
+func newFetchedMessage(d amqp.Delivery) *FetchedMessage {
+	m := &FetchedMessage{
+		PayloadBytes: len(d.Body),
+		Redelivered:  d.Redelivered,
+		Exchange:     d.Exchange,
+		RoutingKey:   d.RoutingKey,
+		Properties: FetchedMessageProperties{
+			AppId:           d.AppId,
+			ContentEncoding: d.ContentEncoding,
+			ContentType:     d.ContentType,
+			CorrelationId:   d.CorrelationId,
+			DeliveryMode:    MessageDeliveryMode(d.DeliveryMode),
+			Expiration:      d.Expiration,
+			Headers:         d.Headers,
+			MessageId:       d.MessageId,
+			Priority:        int(d.Priority),
+			ReplyTo:         d.ReplyTo,
+			Type:            d.Type,
+			UserId:          d.UserId,
+		},
+	}
+
+	if !d.Timestamp.IsZero() {
+		m.Properties.Timestamp = d.Timestamp.Unix()
+	}
+
+	// Same as the "auto" encoding of the management API.
+	if utf8.Valid(d.Body) {
+		m.Payload = string(d.Body)
+		m.PayloadEncoding = PayloadEncodingString
+	} else {
+		m.Payload = base64.StdEncoding.EncodeToString(d.Body)
+		m.PayloadEncoding = PayloadEncodingBase64
+	}
+
+	return m
+}
@@ -0,0 +1,39 @@
+package dialog
+
+import (
+	"strings"
+	"tbunny/internal/model"
+	"tbunny/internal/ui"
+
+	"github.com/rivo/tview"
+)
+
+// ShowFileDialog asks for a file path.
+func ShowFileDialog(mm model.ModalManager, title, buttonLabel, path, placeholder string, okFn func(path string)) {
+	f := ui.NewModalForm()
+
+	f.AddInputField("File:", path, 60, nil, nil)
+	f.AddButtons([]string{"Cancel", buttonLabel})
+
+	pathField := f.GetFormItem(0).(*tview.InputField)
+	pathField.SetPlaceholder(placeholder)
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		path := strings.TrimSpace(pathField.GetText())
+		if path == "" {
+			return
+		}
+
+		okFn(path)
+	})
+
+	f.SetTitle(title)
+
+	modal := ui.NewModalDialog(f, 80, 7)
+	mm.ShowModal(modal)
+}
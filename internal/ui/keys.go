@@ -9,12 +9,14 @@ func init() {
 func initKeys() {
 	tcell.KeyNames[KeyHelp] = "?"
 	tcell.KeyNames[KeySlash] = "/"
+	tcell.KeyNames[KeyColon] = ":"
 	tcell.KeyNames[KeySpace] = "space"
 
 	initNumbKeys()
 	initStdKeys()
 	initShiftKeys()
 	initShiftNumKeys()
+	initAltKeys()
 }
 
 // Defines numeric keys for container actions.
@@ -112,6 +114,36 @@ const (
 	KeyShiftZ
 )
 
+// Define Alt keys, see AsKey for the encoding.
+const (
+	KeyAltA tcell.Key = (iota + 97) * tcell.Key(tcell.ModAlt)
+	KeyAltB
+	KeyAltC
+	KeyAltD
+	KeyAltE
+	KeyAltF
+	KeyAltG
+	KeyAltH
+	KeyAltI
+	KeyAltJ
+	KeyAltK
+	KeyAltL
+	KeyAltM
+	KeyAltN
+	KeyAltO
+	KeyAltP
+	KeyAltQ
+	KeyAltR
+	KeyAltS
+	KeyAltT
+	KeyAltU
+	KeyAltV
+	KeyAltW
+	KeyAltX
+	KeyAltY
+	KeyAltZ
+)
+
 // NumKeys tracks number keys.
 var NumKeys = map[int]tcell.Key{
 	0: Key0,
@@ -210,6 +242,12 @@ func initShiftKeys() {
 	tcell.KeyNames[KeyShiftZ] = "Shift-Z"
 }
 
+func initAltKeys() {
+	for k := KeyAltA; k <= KeyAltZ; k += tcell.Key(tcell.ModAlt) {
+		tcell.KeyNames[k] = "Alt-" + string(rune('A'+(k-KeyAltA)/tcell.Key(tcell.ModAlt)))
+	}
+}
+
 // AsKey converts rune to the keyboard key.
 func AsKey(evt *tcell.EventKey) tcell.Key {
 	if evt.Key() != tcell.KeyRune {
@@ -23,9 +23,13 @@ type TableRow interface {
 type Table[R TableRow] struct {
 	*tview.Table
 
-	columns []TableColumn
-	rows    []R
-	skin    *skins.Skin
+	columns       []TableColumn
+	rows          []R
+	skin          *skins.Skin
+	sortColumn    string
+	sortAscending bool
+	marked        map[string]struct{}
+	colors        map[string]skins.Color
 }
 
 func NewTable[R TableRow]() *Table[R] {
@@ -56,6 +60,39 @@ func (t *Table[R]) SetColumns(columns []TableColumn) {
 	t.rebuildTable()
 }
 
+// SetSortColumn marks the column the rows are sorted by in the header. The
+// rows themselves are expected to be sorted by the caller.
+func (t *Table[R]) SetSortColumn(name string, ascending bool) {
+	t.sortColumn = name
+	t.sortAscending = ascending
+
+	if t.GetRowCount() > 0 {
+		t.createHeaderRow()
+	}
+}
+
+// SetMarked highlights rows with the given IDs as marked.
+func (t *Table[R]) SetMarked(ids map[string]struct{}) {
+	t.marked = ids
+
+	t.updateStyles()
+}
+
+// SetRowColors colors the rows with the given IDs, e.g. resources matching
+// alert rules. Marked rows keep the mark color.
+func (t *Table[R]) SetRowColors(colors map[string]skins.Color) {
+	t.colors = colors
+
+	t.updateStyles()
+}
+
+// IsMarked returns true if the row with the given ID is marked.
+func (t *Table[R]) IsMarked(id string) bool {
+	_, ok := t.marked[id]
+
+	return ok
+}
+
 func (t *Table[R]) Rows() []R {
 	return t.rows
 }
@@ -85,6 +122,20 @@ func (t *Table[R]) GetSelectedRow() (row R, ok bool) {
 	return t.rows[rowIdx-1], true
 }
 
+// SelectRowByID moves the selection to the row with the given ID. It returns
+// false if there is no such row.
+func (t *Table[R]) SelectRowByID(id string) bool {
+	for i, row := range t.rows {
+		if row.GetTableRowID() == id {
+			t.SetSelectable(true, false)
+			t.Select(i+1, 0)
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t *Table[R]) ApplySkin(skin *skins.Skin) {
 	t.skin = skin
 
@@ -151,6 +202,7 @@ func (t *Table[R]) updateDataRows(oldRows []R) {
 				if cell != nil {
 					cell.SetText(content)
 					cell.SetReference(newRow)
+					cell.SetStyle(t.getDataRowCellStyle(newRow))
 				}
 			}
 			oldIdx++
@@ -243,11 +295,26 @@ func (t *Table[R]) createHeaderRow() {
 }
 
 func (t *Table[R]) createHeaderRowCell(column TableColumn) *tview.TableCell {
-	return t.createCell(column, column.Title, t.getHeaderRowCellStyle(), false, &column)
+	title := column.Title
+
+	if column.Name == t.sortColumn {
+		indicator := "↓"
+		if t.sortAscending {
+			indicator = "↑"
+		}
+
+		if t.skin != nil {
+			indicator = "[" + t.skin.Views.Table.Header.SorterColor.String() + "]" + indicator + "[-]"
+		}
+
+		title += indicator
+	}
+
+	return t.createCell(column, title, t.getHeaderRowCellStyle(), false, &column)
 }
 
 func (t *Table[R]) createDataRowCell(row TableRow, column TableColumn, content string) *tview.TableCell {
-	return t.createCell(column, content, t.getDataRowCellStyle(), true, row)
+	return t.createCell(column, content, t.getDataRowCellStyle(row), true, row)
 }
 
 func (t *Table[R]) createCell(column TableColumn, content string, style tcell.Style, selectable bool, ref any) *tview.TableCell {
@@ -277,9 +344,9 @@ func (t *Table[R]) updateStyles() {
 		}
 	}
 
-	style = t.getDataRowCellStyle()
+	for i := 1; i < rowCount && i <= len(t.rows); i++ {
+		style = t.getDataRowCellStyle(t.rows[i-1])
 
-	for i := 1; i < rowCount; i++ {
 		for j := range t.GetColumnCount() {
 			t.GetCell(i, j).SetStyle(style)
 		}
@@ -296,12 +363,21 @@ func (t *Table[R]) getHeaderRowCellStyle() tcell.Style {
 		Background(t.skin.Views.Table.Header.BgColor.Color())
 }
 
-func (t *Table[R]) getDataRowCellStyle() tcell.Style {
+func (t *Table[R]) getDataRowCellStyle(row TableRow) tcell.Style {
 	if t.skin == nil {
 		return tcell.StyleDefault
 	}
 
+	id := row.GetTableRowID()
+
+	fgColor := t.skin.Views.Table.FgColor
+	if t.IsMarked(id) {
+		fgColor = t.skin.Views.Table.MarkColor
+	} else if color, ok := t.colors[id]; ok {
+		fgColor = color
+	}
+
 	return tcell.StyleDefault.
-		Foreground(t.skin.Views.Table.FgColor.Color()).
+		Foreground(fgColor.Color()).
 		Background(t.skin.Views.Table.BgColor.Color())
 }
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+// SortableTableRow is implemented by rows whose formatted column values do
+// not compare naturally, e.g. sizes or rates formatted with units.
+type SortableTableRow interface {
+	// GetTableColumnSortValue returns the raw numeric value of a column, or
+	// false if the formatted value should be compared instead.
+	GetTableColumnSortValue(columnName string) (float64, bool)
+}
+
+// CompareTableRows compares two rows by the value of a column. Numeric
+// values are compared as numbers, empty values go first and everything else
+// is compared as case-insensitive text.
+func CompareTableRows[R TableRow](a, b R, columnName string) int {
+	if va, ok := sortValue(a, columnName); ok {
+		if vb, ok := sortValue(b, columnName); ok {
+			return cmp.Compare(va, vb)
+		}
+	}
+
+	return compareTableValues(a.GetTableColumnValue(columnName), b.GetTableColumnValue(columnName))
+}
+
+func sortValue(row TableRow, columnName string) (float64, bool) {
+	if s, ok := row.(SortableTableRow); ok {
+		return s.GetTableColumnSortValue(columnName)
+	}
+
+	return 0, false
+}
+
+func compareTableValues(a, b string) int {
+	if a == "" || b == "" {
+		return cmp.Compare(len(a), len(b))
+	}
+
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(fa, fb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+
+	return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// RedactURIs joins the URIs with a comma, replacing passwords with "xxxxx".
+func RedactURIs(uris []string) string {
+	redacted := make([]string, 0, len(uris))
+
+	for _, uri := range uris {
+		redacted = append(redacted, redactURI(uri))
+	}
+
+	return strings.Join(redacted, ", ")
+}
+
+// RestoreRedactedURIs splits a comma separated list of URIs edited from the
+// output of RedactURIs. URIs left as redacted are replaced with the original
+// ones, with their passwords; new or changed URIs are kept as typed.
+func RestoreRedactedURIs(text string, originals []string) []string {
+	byRedacted := make(map[string]string, len(originals))
+	for _, uri := range originals {
+		if _, ok := byRedacted[redactURI(uri)]; !ok {
+			byRedacted[redactURI(uri)] = uri
+		}
+	}
+
+	var uris []string
+
+	for _, uri := range strings.Split(text, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri == "" {
+			continue
+		}
+
+		if original, ok := byRedacted[uri]; ok {
+			uri = original
+		}
+
+		uris = append(uris, uri)
+	}
+
+	return uris
+}
+
+func redactURI(uri string) string {
+	if u, err := url.Parse(uri); err == nil {
+		return u.Redacted()
+	}
+
+	return uri
+}
@@ -0,0 +1,84 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"tbunny/internal/alerts"
+	"tbunny/internal/skins"
+)
+
+// alertKey identifies the resources of a kind in a cluster.
+type alertKey struct {
+	cluster  string
+	resource string
+}
+
+// firingAlerts keeps the names of the rules matched by resources, by row ID,
+// outside the views, so that rules don't fire again when a view is reopened.
+// Views list the resources of the active virtual host only, so an update
+// only changes the rules of the resources it evaluated.
+var (
+	firingAlerts   = make(map[alertKey]map[string]string)
+	firingAlertsMx sync.Mutex
+)
+
+// alertState evaluates the rules on the resources of a view, to color their
+// rows and notify rules which newly fire.
+type alertState[R Resource] struct {
+	key    alertKey
+	rules  []*alerts.Rule
+	colors map[string]skins.Color
+}
+
+// update evaluates the rules on resources and returns a notification of the
+// rules which newly fire, if any.
+func (s *alertState[R]) update(kind string, resources []R) string {
+	if len(s.rules) == 0 {
+		return ""
+	}
+
+	firingAlertsMx.Lock()
+	defer firingAlertsMx.Unlock()
+
+	firing := firingAlerts[s.key]
+	if firing == nil {
+		firing = make(map[string]string)
+		firingAlerts[s.key] = firing
+	}
+
+	colors := make(map[string]skins.Color)
+
+	var fired []*alerts.Rule
+	newlyFiring := make(map[*alerts.Rule][]R)
+
+	for _, r := range resources {
+		id := r.GetTableRowID()
+		previous := firing[id]
+
+		rule := alerts.Evaluate(s.rules, r)
+		if rule == nil {
+			delete(firing, id)
+			continue
+		}
+
+		firing[id] = rule.Name()
+		colors[id] = rule.Color()
+
+		if previous != rule.Name() {
+			if _, ok := newlyFiring[rule]; !ok {
+				fired = append(fired, rule)
+			}
+			newlyFiring[rule] = append(newlyFiring[rule], r)
+		}
+	}
+
+	s.colors = colors
+
+	notifications := make([]string, 0, len(fired))
+	for _, rule := range fired {
+		notifications = append(notifications, fmt.Sprintf("%s: %s", rule.Name(), DescribeResources(kind, newlyFiring[rule])))
+	}
+
+	return strings.Join(notifications, "; ")
+}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"errors"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -8,14 +9,21 @@ import (
 	"tbunny/internal/cluster"
 	"tbunny/internal/config"
 	"tbunny/internal/model"
+	"tbunny/internal/rmq"
 	"tbunny/internal/skins"
 	"tbunny/internal/sl"
 	"tbunny/internal/ui"
+	"tbunny/internal/view/channels"
 	"tbunny/internal/view/clusters"
 	"tbunny/internal/view/connections"
+	"tbunny/internal/view/consumers"
 	"tbunny/internal/view/exchanges"
+	"tbunny/internal/view/federation"
+	"tbunny/internal/view/history"
 	"tbunny/internal/view/nodes"
+	"tbunny/internal/view/policies"
 	"tbunny/internal/view/queues"
+	"tbunny/internal/view/shovels"
 	"tbunny/internal/view/users"
 	"tbunny/internal/view/vhosts"
 	"time"
@@ -42,12 +50,14 @@ type App struct {
 	// Toggles
 	headerVisible bool
 	filterVisible bool
+	promptVisible bool
 	crumbsVisible bool
 	disableKeys   bool
 
 	// Views
 	header     *Header
 	filter     *Filter
+	prompt     *Prompt
 	content    *ViewStack
 	crumbs     *crumbs
 	statusLine *statusLine
@@ -61,16 +71,23 @@ type topLevelViewDescriptor struct {
 	description string
 	key         tcell.Key
 	factory     func() model.View
+	aliases     []string
 }
 
 var topLevelViews = map[string]topLevelViewDescriptor{
-	"queues":      {"Queues", ui.KeyShiftQ, queues.NewQueues},
-	"exchanges":   {"Exchanges", ui.KeyShiftE, exchanges.NewExchanges},
-	"vhosts":      {"Virtual hosts", ui.KeyShiftV, vhosts.NewVHosts},
-	"clusters":    {"Clusters", ui.KeyShiftL, clusters.NewClusters},
-	"connections": {"Connections", ui.KeyShiftC, connections.NewConnections},
-	"users":       {"Users", ui.KeyShiftU, users.NewView},
-	"nodes":       {"Nodes", ui.KeyShiftN, nodes.NewView},
+	"queues":      {"Queues", ui.KeyShiftQ, queues.NewQueues, []string{"q"}},
+	"exchanges":   {"Exchanges", ui.KeyShiftE, exchanges.NewExchanges, []string{"e", "x"}},
+	"vhosts":      {"Virtual hosts", ui.KeyShiftV, vhosts.NewVHosts, []string{"vh"}},
+	"clusters":    {"Clusters", ui.KeyShiftL, clusters.NewClusters, []string{"cl"}},
+	"connections": {"Connections", ui.KeyShiftC, connections.NewConnections, []string{"conn"}},
+	"channels":    {"Channels", ui.KeyShiftH, channels.NewChannels, []string{"ch"}},
+	"consumers":   {"Consumers", ui.KeyShiftO, consumers.NewConsumers, []string{"cons"}},
+	"users":       {"Users", ui.KeyShiftU, users.NewView, []string{"u"}},
+	"nodes":       {"Nodes", ui.KeyShiftN, nodes.NewView, []string{"n"}},
+	"policies":    {"Policies", ui.KeyShiftP, policies.NewPolicies, []string{"pol"}},
+	"shovels":     {"Shovels", ui.KeyShiftS, shovels.NewShovels, []string{"sh"}},
+	"federation":  {"Federation upstreams", ui.KeyShiftF, federation.NewUpstreams, []string{"fed"}},
+	"history":     {"Publish history", ui.KeyShiftY, history.NewHistory, []string{"hist"}},
 }
 
 func NewApp(version string) *App {
@@ -86,6 +103,7 @@ func NewApp(version string) *App {
 	a.main = tview.NewPages()
 	a.header = NewHeader(a)
 	a.filter = NewFilter(a)
+	a.prompt = NewPrompt(a)
 	a.crumbs = newCrumbs(a)
 	a.statusLine = newStatusLine(a, defaultStatusLineDelay)
 
@@ -157,7 +175,14 @@ func (a *App) ClusterChanged(cluster *cluster.Cluster) {
 	a.bindKeys()
 }
 
-func (a *App) ClusterConnectionLost(*cluster.Cluster) {
+func (a *App) ClusterConnectionLost(c *cluster.Cluster) {
+	// A rejected token does not heal by waiting, unlike a network failure.
+	var rejected *rmq.TokenRejectedError
+	if errors.As(c.LastError(), &rejected) {
+		a.statusLine.Errorf("Lost connection to cluster: %s", rejected)
+		return
+	}
+
 	a.statusLine.Error("Lost connection to cluster")
 }
 
@@ -230,14 +255,18 @@ func (a *App) layout() {
 	f.Clear()
 
 	if a.headerVisible {
-		f.AddItem(a.header, 7, 1, false)
+		f.AddItem(a.header, headerHeight, 1, false)
 	}
 
 	if a.filterVisible {
 		f.AddItem(a.filter, 3, 1, true)
 	}
 
-	f.AddItem(a.content.Primitive(), 0, 10, !a.filterVisible)
+	if a.promptVisible {
+		f.AddItem(a.prompt, 3, 1, true)
+	}
+
+	f.AddItem(a.content.Primitive(), 0, 10, !a.filterVisible && !a.promptVisible)
 
 	if a.crumbsVisible {
 		f.AddItem(a.crumbs, 1, 1, false)
@@ -247,6 +276,8 @@ func (a *App) layout() {
 
 	if a.filterVisible {
 		a.SetFocus(a.filter)
+	} else if a.promptVisible {
+		a.SetFocus(a.prompt)
 	} else {
 		a.SetFocus(a.content.Primitive())
 	}
@@ -266,6 +297,7 @@ func (a *App) bindKeys() {
 	m := ui.KeyMap{
 		tcell.KeyEscape: ui.NewKeyActionWithGroup("Back/Clear", a.clearOrBackCmd, false, 0),
 		ui.KeySlash:     ui.NewKeyActionWithGroup("Filter", a.filterCmd, false, 0),
+		ui.KeyColon:     ui.NewKeyActionWithGroup("Command", a.promptCmd, false, 0),
 
 		ui.KeyHelp:     ui.NewKeyActionWithGroup("Help", a.helpCmd, false, 1),
 		tcell.KeyCtrlC: ui.NewKeyActionWithGroup("Quit", a.quitCmd, false, 2),
@@ -331,6 +363,16 @@ func (a *App) filterCmd(*tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+func (a *App) promptCmd(*tcell.EventKey) *tcell.EventKey {
+	a.prompt.Open()
+	a.fetchPromptNames()
+
+	a.promptVisible = true
+	a.layout()
+
+	return nil
+}
+
 func (a *App) clearOrBackCmd(*tcell.EventKey) *tcell.EventKey {
 	filterer, ok := a.content.Top().(model.Filterer)
 	if ok && filterer.Clear() {
@@ -347,7 +389,7 @@ func (a *App) keyboard(event *tcell.EventKey) *tcell.EventKey {
 		return nil
 	}
 
-	if a.filterVisible {
+	if a.filterVisible || a.promptVisible {
 		return event
 	}
 
@@ -385,3 +427,8 @@ func (a *App) closeFilter() {
 	a.filterVisible = false
 	a.layout()
 }
+
+func (a *App) closePrompt() {
+	a.promptVisible = false
+	a.layout()
+}
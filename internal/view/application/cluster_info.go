@@ -71,6 +71,7 @@ func (c *ClusterInfo) update() {
 	row := c.setCell(0, info.Name)
 	row = c.setCell(row, info.ClusterName)
 	row = c.setCell(row, c.cluster.Username())
+	row = c.setCell(row, info.TLS)
 	row = c.setCell(row, info.RabbitMQVersion)
 	row = c.setCell(row, info.ManagementVersion)
 	row = c.setCell(row, info.ErlangVersion)
@@ -84,11 +85,12 @@ func (c *ClusterInfo) reset() {
 	row = c.setCell(row, NAValue)
 	row = c.setCell(row, NAValue)
 	row = c.setCell(row, NAValue)
+	row = c.setCell(row, NAValue)
 	c.setCell(row, c.app.Version)
 }
 
 func (c *ClusterInfo) layout() {
-	for row, section := range []string{"Cluster", "Name", "User", "Rabbit Rev", "Mgmt Rev", "Erlang Rev", "TBunny Rev"} {
+	for row, section := range []string{"Cluster", "Name", "User", "TLS", "Rabbit Rev", "Mgmt Rev", "Erlang Rev", "TBunny Rev"} {
 		c.SetCell(row, 0, c.sectionCell(section))
 		c.SetCell(row, 1, c.infoCell(NAValue))
 	}
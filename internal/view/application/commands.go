@@ -0,0 +1,319 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/sl"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/bindings"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+const (
+	queueNames    = "queues"
+	exchangeNames = "exchanges"
+)
+
+var errNotConnected = errors.New("not connected to a cluster")
+
+// promptCommand is a command that can be executed from the command prompt.
+type promptCommand struct {
+	name    string
+	aliases []string
+	// complete returns candidates for the next argument, given the arguments
+	// entered so far.
+	complete func(a *App, args []string) []string
+	run      func(a *App, args []string) error
+}
+
+// promptCommands returns all commands available in the prompt: one per top
+// level view, plus navigation commands.
+func promptCommands() []promptCommand {
+	var commands []promptCommand
+
+	for _, name := range slices.Sorted(maps.Keys(topLevelViews)) {
+		descriptor := topLevelViews[name]
+
+		commands = append(commands, promptCommand{
+			name:     name,
+			aliases:  descriptor.aliases,
+			complete: viewFilterCandidates(name),
+			run: func(a *App, args []string) error {
+				return a.openViewCommand(name, args)
+			},
+		})
+	}
+
+	return append(commands,
+		promptCommand{name: "vhost", aliases: []string{"ns"}, complete: vhostCandidates, run: (*App).vhostCommand},
+		promptCommand{name: "cluster", aliases: []string{"ctx"}, complete: clusterCandidates, run: (*App).clusterCommand},
+		promptCommand{name: "bindings", aliases: []string{"b"}, complete: bindingsCandidates, run: (*App).bindingsCommand},
+		promptCommand{name: "quit", aliases: []string{"q!"}, run: func(a *App, _ []string) error {
+			a.quitCmd(nil)
+			return nil
+		}},
+	)
+}
+
+func findPromptCommand(name string) (promptCommand, bool) {
+	for _, c := range promptCommands() {
+		if c.name == name || slices.Contains(c.aliases, name) {
+			return c, true
+		}
+	}
+
+	return promptCommand{}, false
+}
+
+// completeCommand returns complete command lines starting with text.
+func (a *App) completeCommand(text string) []string {
+	fields := strings.Fields(text)
+
+	partial := ""
+	if len(fields) > 0 && !strings.HasSuffix(text, " ") {
+		partial = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	var prefix string
+	var candidates []string
+
+	if len(fields) == 0 {
+		candidates = utils.Map(promptCommands(), func(c promptCommand) string { return c.name })
+	} else {
+		c, ok := findPromptCommand(fields[0])
+		if !ok || c.complete == nil {
+			return nil
+		}
+
+		prefix = strings.Join(fields, " ") + " "
+		candidates = c.complete(a, fields[1:])
+	}
+
+	return utils.FilterMap(
+		candidates,
+		func(s string) bool { return s != partial && strings.HasPrefix(s, partial) },
+		func(s string) string { return prefix + s },
+	)
+}
+
+func (a *App) executeCommand(text string) {
+	fields := strings.Fields(text)
+
+	c, ok := findPromptCommand(fields[0])
+	if !ok {
+		a.statusLine.Errorf("Unknown command: %s", fields[0])
+		return
+	}
+
+	if err := c.run(a, fields[1:]); err != nil {
+		a.statusLine.Errorf("%s: %s", c.name, err)
+	}
+}
+
+// fetchPromptNames loads names of queues and exchanges of the active virtual
+// host in the background, so that the prompt can complete them.
+func (a *App) fetchPromptNames() {
+	c := a.cluster
+	if c == nil || !c.IsAvailable() {
+		return
+	}
+
+	vhost := c.ActiveVirtualHost()
+
+	go func() {
+		var queues []rabbithole.QueueInfo
+		var err error
+
+		if vhost == "" {
+			queues, err = c.ListQueues()
+		} else {
+			queues, err = c.ListQueuesIn(vhost)
+		}
+
+		if err != nil {
+			slog.Error("Failed to fetch queues for completion", sl.Error, err, sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+			return
+		}
+
+		a.prompt.SetNames(queueNames, sortedNames(queues, func(q rabbithole.QueueInfo) string { return q.Name }))
+	}()
+
+	go func() {
+		var exchanges []rabbithole.ExchangeInfo
+		var err error
+
+		if vhost == "" {
+			exchanges, err = c.ListExchanges()
+		} else {
+			exchanges, err = c.ListExchangesIn(vhost)
+		}
+
+		if err != nil {
+			slog.Error("Failed to fetch exchanges for completion", sl.Error, err, sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+			return
+		}
+
+		a.prompt.SetNames(exchangeNames, sortedNames(exchanges, func(e rabbithole.ExchangeInfo) string { return e.Name }))
+	}()
+}
+
+func sortedNames[T any](items []T, nameFn func(T) string) []string {
+	names := utils.FilterMap(items, func(i T) bool { return nameFn(i) != "" }, nameFn)
+
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+func viewFilterCandidates(view string) func(a *App, args []string) []string {
+	return func(a *App, args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+
+		return a.prompt.Names(view)
+	}
+}
+
+func vhostCandidates(a *App, args []string) []string {
+	if len(args) > 0 || a.cluster == nil {
+		return nil
+	}
+
+	return utils.Map(a.cluster.VirtualHosts(), func(v rabbithole.VhostInfo) string { return v.Name })
+}
+
+func clusterCandidates(_ *App, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(cluster.Clusters()))
+}
+
+func bindingsCandidates(a *App, args []string) []string {
+	switch len(args) {
+	case 0:
+		return []string{string(bindings.ExchangeSubject), string(bindings.QueueSubject)}
+	case 1:
+		switch bindings.SubjectType(args[0]) {
+		case bindings.ExchangeSubject:
+			return a.prompt.Names(exchangeNames)
+		case bindings.QueueSubject:
+			return a.prompt.Names(queueNames)
+		}
+	}
+
+	return nil
+}
+
+// openViewCommand opens a top level view and filters it with the optional argument.
+func (a *App) openViewCommand(name string, args []string) error {
+	if a.cluster == nil && name != "clusters" {
+		return errNotConnected
+	}
+
+	a.openToplevelView(name)
+
+	if len(args) > 0 {
+		if filterer, ok := a.content.Top().(model.Filterer); ok {
+			filterer.Filter(commandFilter(strings.Join(args, " ")))
+		}
+	}
+
+	return nil
+}
+
+// commandFilter returns the filter of a view command: with "*" or "?"
+// wildcards, e.g. orders-*, it matches whole values.
+func commandFilter(arg string) string {
+	if strings.ContainsAny(arg, "*?") && !strings.HasPrefix(arg, view.WildcardFilterPrefix) {
+		return view.WildcardFilterPrefix + arg
+	}
+
+	return arg
+}
+
+// vhostCommand switches the active virtual host; without arguments (or with
+// "*") it switches to all virtual hosts.
+func (a *App) vhostCommand(args []string) error {
+	if a.cluster == nil {
+		return errNotConnected
+	}
+
+	vhost := ""
+	if len(args) > 0 && args[0] != "*" {
+		vhost = args[0]
+	}
+
+	if vhost != "" && !slices.ContainsFunc(a.cluster.VirtualHosts(), func(v rabbithole.VhostInfo) bool { return v.Name == vhost }) {
+		return fmt.Errorf("virtual host %s not found", vhost)
+	}
+
+	a.cluster.SetActiveVirtualHost(vhost)
+
+	return nil
+}
+
+func (a *App) clusterCommand(args []string) error {
+	if len(args) != 1 {
+		return errors.New("cluster name expected")
+	}
+
+	name := args[0]
+	if _, ok := cluster.Clusters()[name]; !ok {
+		return fmt.Errorf("cluster %s not found", name)
+	}
+
+	a.statusLine.Infof("Connecting to cluster %s...", name)
+	a.DisableKeys()
+
+	go func() {
+		_, err := cluster.Connect(name)
+		a.EnableKeys()
+
+		if err != nil {
+			a.statusLine.Errorf("Failed to connect to cluster %s: %s", name, err.Error())
+			return
+		}
+
+		a.QueueUpdateDraw(func() {
+			a.OpenClusterDefaultView()
+		})
+	}()
+
+	return nil
+}
+
+func (a *App) bindingsCommand(args []string) error {
+	if a.cluster == nil {
+		return errNotConnected
+	}
+
+	if len(args) != 2 {
+		return errors.New("usage: bindings exchange|queue <name>")
+	}
+
+	subjectType := bindings.SubjectType(args[0])
+	if subjectType != bindings.ExchangeSubject && subjectType != bindings.QueueSubject {
+		return fmt.Errorf("unknown subject type %s, expected exchange or queue", args[0])
+	}
+
+	vhost := a.cluster.ActiveVirtualHost()
+	if vhost == "" {
+		vhost = "/"
+	}
+
+	a.AddView(bindings.NewBindings(subjectType, args[1], vhost))
+
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 
 const (
 	clusterInfoWidth = 50
+	// headerHeight is the number of rows of the cluster information.
+	headerHeight = 8
 	//clusterInfoPad   = 15
 )
 
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maxPromptHistory is the number of commands the prompt remembers.
+const maxPromptHistory = 100
+
+// Prompt is a k9s-style command line opened with ":". It suggests the
+// completion of the command being typed and remembers executed commands.
+type Prompt struct {
+	*tview.TextView
+
+	app  *App
+	text string
+
+	suggestions     []string
+	suggestionIndex int
+
+	history      []string
+	historyIndex int
+
+	names map[string][]string
+	mx    sync.RWMutex
+}
+
+func NewPrompt(app *App) *Prompt {
+	p := &Prompt{
+		TextView: tview.NewTextView(),
+		app:      app,
+	}
+
+	p.SetBorder(true)
+	p.SetBorderPadding(0, 0, 1, 1)
+	p.SetDynamicColors(true)
+	p.SetInputCapture(p.keyboard)
+
+	return p
+}
+
+func (p *Prompt) Open() {
+	p.mx.Lock()
+	p.names = make(map[string][]string)
+	p.mx.Unlock()
+
+	p.historyIndex = len(p.history)
+	p.SetText("")
+}
+
+func (p *Prompt) SetText(text string) {
+	p.text = text
+	p.suggestions = p.app.completeCommand(text)
+	p.suggestionIndex = 0
+
+	p.render()
+}
+
+// SetNames stores resource names of the given kind to be used for completion.
+func (p *Prompt) SetNames(kind string, names []string) {
+	p.mx.Lock()
+	p.names[kind] = names
+	p.mx.Unlock()
+
+	p.app.QueueUpdateDraw(func() {
+		p.SetText(p.text)
+	})
+}
+
+// Names returns resource names of the given kind fetched so far.
+func (p *Prompt) Names(kind string) []string {
+	p.mx.RLock()
+	defer p.mx.RUnlock()
+
+	return p.names[kind]
+}
+
+func (p *Prompt) render() {
+	var b strings.Builder
+
+	b.WriteString("[::b]:[::-]")
+	b.WriteString(tview.Escape(p.text))
+
+	if s := p.suggestion(); s != "" {
+		b.WriteString("[::d]")
+		b.WriteString(tview.Escape(strings.TrimPrefix(s, p.text)))
+		b.WriteString("[::-]")
+	}
+
+	p.TextView.SetText(b.String())
+}
+
+func (p *Prompt) suggestion() string {
+	if len(p.suggestions) == 0 {
+		return ""
+	}
+
+	return p.suggestions[p.suggestionIndex]
+}
+
+func (p *Prompt) keyboard(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyBackspace2, tcell.KeyBackspace:
+		p.delete()
+	case tcell.KeyRune:
+		p.SetText(p.text + string(event.Rune()))
+	case tcell.KeyTab, tcell.KeyRight:
+		p.accept()
+	case tcell.KeyCtrlN:
+		p.cycle(1)
+	case tcell.KeyCtrlP:
+		p.cycle(-1)
+	case tcell.KeyUp:
+		p.recall(-1)
+	case tcell.KeyDown:
+		p.recall(1)
+	case tcell.KeyEnter:
+		p.execute()
+	case tcell.KeyEscape:
+		p.app.closePrompt()
+	default:
+	}
+
+	return nil
+}
+
+func (p *Prompt) delete() {
+	if len(p.text) == 0 {
+		return
+	}
+
+	_, size := utf8.DecodeLastRuneInString(p.text)
+
+	p.SetText(p.text[:len(p.text)-size])
+}
+
+func (p *Prompt) accept() {
+	if s := p.suggestion(); s != "" {
+		p.SetText(s + " ")
+	}
+}
+
+func (p *Prompt) cycle(delta int) {
+	if len(p.suggestions) == 0 {
+		return
+	}
+
+	p.suggestionIndex = (p.suggestionIndex + delta + len(p.suggestions)) % len(p.suggestions)
+
+	p.render()
+}
+
+func (p *Prompt) recall(delta int) {
+	index := p.historyIndex + delta
+	if index < 0 || index > len(p.history) {
+		return
+	}
+
+	p.historyIndex = index
+
+	if index == len(p.history) {
+		p.SetText("")
+	} else {
+		p.SetText(p.history[index])
+	}
+}
+
+func (p *Prompt) execute() {
+	text := strings.TrimSpace(p.text)
+
+	p.app.closePrompt()
+
+	if text == "" {
+		return
+	}
+
+	p.remember(text)
+	p.app.executeCommand(text)
+}
+
+func (p *Prompt) remember(text string) {
+	if len(p.history) > 0 && p.history[len(p.history)-1] == text {
+		return
+	}
+
+	p.history = append(p.history, text)
+
+	if len(p.history) > maxPromptHistory {
+		p.history = p.history[len(p.history)-maxPromptHistory:]
+	}
+}
@@ -114,6 +114,10 @@ func (vs *ViewStack) Pop() (model.View, bool) {
 	v.Stop()
 	vs.delete(v)
 
+	if c, ok := v.(model.Closer); ok {
+		c.Close()
+	}
+
 	if top != nil {
 		vs.show(top)
 		top.Start()
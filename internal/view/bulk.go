@@ -0,0 +1,115 @@
+package view
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"tbunny/internal/model"
+	"tbunny/internal/sl"
+	"tbunny/internal/utils"
+)
+
+const (
+	// maxBulkWorkers limits the number of requests a bulk action runs concurrently.
+	maxBulkWorkers = 8
+
+	// maxDescribedResources is the number of names listed when describing several resources.
+	maxDescribedResources = 10
+)
+
+// BulkAction names an action performed on several resources at once, as
+// used in status line messages.
+type BulkAction struct {
+	// Name is the infinitive, e.g. "delete".
+	Name string
+	// Progress is the present participle, e.g. "Deleting".
+	Progress string
+	// Done is the past tense, e.g. "Deleted".
+	Done string
+}
+
+var (
+	DeleteAction = BulkAction{"delete", "Deleting", "Deleted"}
+	PurgeAction  = BulkAction{"purge", "Purging", "Purged"}
+)
+
+// DescribeResources returns the display name of a single resource, or the
+// number of resources with a (possibly truncated) list of their names.
+func DescribeResources[R Resource](kind string, resources []R) string {
+	if len(resources) == 1 {
+		return resources[0].GetDisplayName()
+	}
+
+	shown := resources[:min(len(resources), maxDescribedResources)]
+	names := strings.Join(utils.Map(shown, func(r R) string { return r.GetName() }), ", ")
+
+	if len(resources) > len(shown) {
+		names += fmt.Sprintf(" and %d more", len(resources)-len(shown))
+	}
+
+	return fmt.Sprintf("%d %s (%s)", len(resources), kind, names)
+}
+
+// RunBulkAction runs fn for every resource in the background, a few
+// resources at a time, reports the result for each of them and a summary
+// in the status line, and calls doneFn when all resources are processed.
+func RunBulkAction[R Resource](app model.App, kind string, resources []R, action BulkAction, fn func(R) error, doneFn func()) {
+	if len(resources) == 0 {
+		return
+	}
+
+	app.StatusLine().Infof("%s %s...", action.Progress, DescribeResources(kind, resources))
+
+	type result struct {
+		resource R
+		err      error
+	}
+
+	go func() {
+		results := make(chan result)
+		workers := make(chan struct{}, maxBulkWorkers)
+
+		for _, r := range resources {
+			go func() {
+				workers <- struct{}{}
+				err := fn(r)
+				<-workers
+
+				results <- result{r, err}
+			}()
+		}
+
+		var failed []string
+
+		for i := range resources {
+			res := <-results
+
+			counter := ""
+			if len(resources) > 1 {
+				counter = fmt.Sprintf(" (%d/%d)", i+1, len(resources))
+			}
+
+			if res.err != nil {
+				slog.Error("Bulk action failed", sl.Error, res.err, sl.Resource, res.resource.GetName())
+
+				failed = append(failed, res.resource.GetName())
+				app.StatusLine().Errorf("Failed to %s %s%s: %s", action.Name, res.resource.GetDisplayName(), counter, res.err)
+			} else if len(failed) == 0 {
+				// Successes are not reported after a failure, so that the error stays visible.
+				app.StatusLine().Infof("%s %s%s", action.Done, res.resource.GetDisplayName(), counter)
+			}
+		}
+
+		if len(resources) > 1 {
+			if len(failed) == 0 {
+				app.StatusLine().Infof("%s %d %s", action.Done, len(resources), kind)
+			} else {
+				app.StatusLine().Errorf("%s %d of %d %s, failed: %s", action.Done, len(resources)-len(failed), len(resources), kind, strings.Join(failed, ", "))
+			}
+		}
+
+		if doneFn != nil {
+			doneFn()
+		}
+	}()
+}
@@ -0,0 +1,201 @@
+package channels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/skins"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const ChannelDetailsTitleFmt = " [fg:bg:b]%s[fg:bg:-]([hilite:bg:b]%s[fg:bg:-]) "
+
+type ChannelDetails struct {
+	*view.ClusterAwareRefreshableView[*tview.TextView]
+
+	name    string
+	channel *rmq.ChannelInfo
+	skin    *skins.Skin
+}
+
+func NewChannelDetails(name string) *ChannelDetails {
+	textView := tview.NewTextView()
+	textView.SetBorderPadding(1, 0, 1, 1)
+	textView.SetBorder(true)
+	textView.SetDynamicColors(true)
+	textView.SetScrollable(true)
+	textView.SetWordWrap(false)
+
+	strategy := view.NewLiveUpdateStrategy()
+
+	v := &ChannelDetails{
+		ClusterAwareRefreshableView: view.NewClusterAwareRefreshableView("Channel Details", textView, strategy),
+		name:                        name,
+	}
+
+	v.SetUpdateFn(v.performUpdate)
+	v.AddBindingKeysFn(v.bindKeys)
+
+	return v
+}
+
+func (v *ChannelDetails) bindKeys(km ui.KeyMap) {
+	km.Add(ui.KeyO, ui.NewKeyAction("Show consumers", v.showConsumersCmd))
+}
+
+func (v *ChannelDetails) showConsumersCmd(*tcell.EventKey) *tcell.EventKey {
+	var vhost string
+	if v.channel != nil {
+		vhost = v.channel.Vhost
+	}
+
+	v.App().AddView(NewChannelConsumersFn(v.name, vhost))
+
+	return nil
+}
+
+func (v *ChannelDetails) Init(app model.App) error {
+	err := v.ClusterAwareRefreshableView.Init(app)
+	if err != nil {
+		return err
+	}
+
+	v.skin = skins.Current()
+	v.updateTitle()
+
+	return nil
+}
+
+func (v *ChannelDetails) performUpdate(view.UpdateKind) {
+	channel, err := v.Cluster().GetChannel(v.name)
+	if err != nil {
+		v.App().StatusLine().Errorf("Failed to fetch channel details: %v", err)
+		return
+	}
+
+	v.channel = channel
+
+	v.App().QueueUpdateDraw(func() {
+		v.updateContent()
+	})
+}
+
+func (v *ChannelDetails) updateTitle() {
+	title := view.SkinTitle(fmt.Sprintf(ChannelDetailsTitleFmt, v.Name(), v.name))
+
+	v.Ui().SetTitle(title)
+}
+
+func (v *ChannelDetails) updateContent() {
+	var b strings.Builder
+
+	v.formatDetails(&b)
+	v.formatConsumers(&b)
+
+	styledContent := view.SkinStatsContent(b.String(), &v.skin.Views.Stats)
+
+	v.Ui().SetText(styledContent)
+}
+
+type labelAndFormatter struct {
+	label string
+	fn    detailsFormatter
+}
+
+type detailsFormatter func(channel *rmq.ChannelInfo) string
+
+var detailsFormatters = []labelAndFormatter{
+	{label: "Connection:", fn: func(channel *rmq.ChannelInfo) string { return channel.ConnectionDetails.Name }},
+	{label: "Node:", fn: func(channel *rmq.ChannelInfo) string { return channel.Node }},
+	{label: "Virtual host:", fn: func(channel *rmq.ChannelInfo) string { return channel.Vhost }},
+	{label: "User name:", fn: func(channel *rmq.ChannelInfo) string { return channel.User }},
+	{label: "State:", fn: func(channel *rmq.ChannelInfo) string { return channel.State }},
+	{label: "Prefetch count:", fn: func(channel *rmq.ChannelInfo) string { return strconv.Itoa(channel.PrefetchCount) }},
+	{label: "Global prefetch count:", fn: func(channel *rmq.ChannelInfo) string { return strconv.Itoa(channel.GlobalPrefetchCount) }},
+	{label: "Publisher confirms:", fn: func(channel *rmq.ChannelInfo) string { return view.FormatBool(channel.UsesPublisherConfirms) }},
+	{label: "Transactional:", fn: func(channel *rmq.ChannelInfo) string { return view.FormatBool(channel.Transactional) }},
+	{label: "Flow blocked:", fn: func(channel *rmq.ChannelInfo) string { return view.FormatBool(channel.ClientFlowBlocked) }},
+	{label: "Unacknowledged:", fn: func(channel *rmq.ChannelInfo) string { return strconv.Itoa(channel.UnacknowledgedMessageCount) }},
+	{label: "Unconfirmed:", fn: func(channel *rmq.ChannelInfo) string { return strconv.Itoa(channel.UnconfirmedMessageCount) }},
+	{label: "Uncommitted messages:", fn: func(channel *rmq.ChannelInfo) string { return strconv.Itoa(channel.UncommittedMessageCount) }},
+	{label: "Uncommitted acks:", fn: func(channel *rmq.ChannelInfo) string { return strconv.Itoa(channel.UncommittedAckCount) }},
+	{label: "Publish rate:", fn: func(channel *rmq.ChannelInfo) string {
+		if channel.MessageStats == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f/s", channel.MessageStats.PublishDetails.Rate)
+	}},
+	{label: "Deliver rate:", fn: func(channel *rmq.ChannelInfo) string {
+		if channel.MessageStats == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f/s", channel.MessageStats.DeliverGetDetails.Rate)
+	}},
+	{label: "Ack rate:", fn: func(channel *rmq.ChannelInfo) string {
+		if channel.MessageStats == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f/s", channel.MessageStats.AckDetails.Rate)
+	}},
+}
+
+func (v *ChannelDetails) formatDetails(b *strings.Builder) {
+	maxLabelLength := 0
+
+	for _, lf := range detailsFormatters {
+		if len(lf.label) > maxLabelLength {
+			maxLabelLength = len(lf.label)
+		}
+	}
+
+	b.WriteString("[caption]Details[-]\n\n")
+	for _, lf := range detailsFormatters {
+		utils.Sbprintf(b, "[label]%s[-] [value]%s[-]\n", utils.PadRight(lf.label, maxLabelLength), lf.fn(v.channel))
+	}
+}
+
+func (v *ChannelDetails) formatConsumers(b *strings.Builder) {
+	utils.Sbprintf(b, "\n[caption]Consumers (%d)[-]\n\n", len(v.channel.ConsumerDetails))
+
+	if len(v.channel.ConsumerDetails) == 0 {
+		return
+	}
+
+	headers := []string{"Consumer tag", "Queue", "Ack required", "Exclusive", "Prefetch", "Active"}
+	rows := utils.Map(v.channel.ConsumerDetails, func(c rmq.ChannelConsumerDetail) []string {
+		return []string{
+			c.ConsumerTag,
+			c.Queue.Name,
+			view.FormatBool(c.AckRequired),
+			view.FormatBool(c.Exclusive),
+			strconv.Itoa(c.PrefetchCount),
+			view.FormatBool(c.Active),
+		}
+	})
+
+	widths := utils.Map(headers, func(h string) int { return len(h) })
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+
+	for i, h := range headers {
+		utils.Sbprintf(b, "[label]%s[-]  ", utils.PadRight(h, widths[i]))
+	}
+	b.WriteString("\n")
+
+	for _, row := range rows {
+		for i, cell := range row {
+			utils.Sbprintf(b, "[value]%s[-]  ", cell+strings.Repeat(" ", widths[i]-len([]rune(cell))))
+		}
+		b.WriteString("\n")
+	}
+}
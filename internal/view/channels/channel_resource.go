@@ -0,0 +1,82 @@
+package channels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"tbunny/internal/rmq"
+)
+
+type ChannelResource struct {
+	rmq.ChannelInfo
+}
+
+func (r *ChannelResource) GetName() string {
+	return r.Name
+}
+
+func (r *ChannelResource) GetDisplayName() string {
+	return "channel " + r.Name
+}
+
+func (r *ChannelResource) GetTableRowID() string {
+	return r.Name
+}
+
+func (r *ChannelResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "name":
+		return r.Name
+	case "vhost":
+		return r.Vhost
+	case "username":
+		return r.User
+	case "node":
+		return r.Node
+	case "mode":
+		return r.getMode()
+	case "state":
+		return r.State
+	case "prefetch":
+		return strconv.Itoa(r.PrefetchCount)
+	case "unacked":
+		return strconv.Itoa(r.UnacknowledgedMessageCount)
+	case "unconfirmed":
+		return strconv.Itoa(r.UnconfirmedMessageCount)
+	case "consumers":
+		return strconv.Itoa(r.ConsumerCount)
+	case "msgRatePublish":
+		if r.MessageStats == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f", r.MessageStats.PublishDetails.Rate)
+	case "msgRateDelivered":
+		if r.MessageStats == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f", r.MessageStats.DeliverGetDetails.Rate)
+	case "msgRateAcked":
+		if r.MessageStats == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f", r.MessageStats.AckDetails.Rate)
+	default:
+		return ""
+	}
+}
+
+// getMode returns channel mode flags the same way the management UI does:
+// C for publisher confirms and T for transactional channels.
+func (r *ChannelResource) getMode() string {
+	var parts []string
+
+	if r.UsesPublisherConfirms {
+		parts = append(parts, "C")
+	}
+
+	if r.Transactional {
+		parts = append(parts, "T")
+	}
+
+	return strings.Join(parts, " ")
+}
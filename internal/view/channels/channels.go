@@ -0,0 +1,163 @@
+package channels
+
+import (
+	"fmt"
+	"log/slog"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/vhosts"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type Channels struct {
+	view.ClusterAwareResourceView[*ChannelResource]
+
+	connection string
+	wideMode   bool
+}
+
+// NewChannelConsumersFn creates a view with consumers of a single channel.
+// It is set by the consumers package.
+var NewChannelConsumersFn func(channel, vhost string) model.View
+
+// NewChannels creates a view with channels of all connections.
+func NewChannels() model.View {
+	v := &Channels{
+		ClusterAwareResourceView: vhosts.NewVHostExtender[*ChannelResource](
+			view.NewClusterAwareResourceTableView[*ChannelResource]("Channels", view.NewLiveUpdateStrategy()),
+		),
+	}
+
+	v.init()
+
+	return v
+}
+
+// NewConnectionChannels creates a view with channels of a single connection.
+func NewConnectionChannels(connection string) model.View {
+	v := &Channels{
+		ClusterAwareResourceView: view.NewClusterAwareResourceTableView[*ChannelResource]("Channels", view.NewLiveUpdateStrategy()),
+		connection:               connection,
+	}
+
+	v.SetPath(connection)
+	v.init()
+
+	return v
+}
+
+func (v *Channels) init() {
+	v.SetResourceProvider(v)
+	v.SetEnterAction("Show details", v.showDetails)
+	v.AddBindingKeysFn(v.bindKeys)
+}
+
+func (v *Channels) GetResources() ([]*ChannelResource, error) {
+	channels, err := v.getChannels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channels: %w", err)
+	}
+
+	rows := utils.Map(channels, func(c rmq.ChannelInfo) *ChannelResource {
+		return &ChannelResource{c}
+	})
+
+	return rows, nil
+}
+
+func (v *Channels) getChannels() (channels []rmq.ChannelInfo, err error) {
+	c := v.Cluster()
+
+	if v.connection != "" {
+		slog.Debug("Fetching connection channels", sl.Component, v.Name(), sl.Cluster, c.Name(), sl.Resource, v.connection)
+		channels, err = c.ListConnectionChannels(v.connection)
+	} else if vhost := c.ActiveVirtualHost(); vhost != "" {
+		slog.Debug("Fetching channels", sl.Component, v.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+		channels, err = c.ListChannelsIn(vhost)
+	} else {
+		slog.Debug("Fetching channels", sl.Component, v.Name(), sl.Cluster, c.Name())
+		channels, err = c.ListChannels()
+	}
+
+	if err != nil {
+		slog.Error("Failed to fetch channels", sl.Error, err, sl.Component, v.Name(), sl.Cluster, c.Name())
+	}
+
+	return channels, err
+}
+
+func (v *Channels) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "name", Title: "CHANNEL", Expansion: 2},
+	}
+
+	if v.wideMode {
+		c = append(c, ui.TableColumn{Name: "username", Title: "USER NAME"})
+	}
+
+	c = append(c, []ui.TableColumn{
+		{Name: "mode", Title: "MODE", Align: tview.AlignCenter},
+		{Name: "state", Title: "STATE"},
+		{Name: "prefetch", Title: "PREFETCH", Align: tview.AlignRight},
+		{Name: "unacked", Title: "UNACKED", Align: tview.AlignRight},
+		{Name: "unconfirmed", Title: "UNCONFIRMED", Align: tview.AlignRight},
+		{Name: "consumers", Title: "CONSUMERS", Align: tview.AlignRight},
+		{Name: "msgRatePublish", Title: "PUBLISH/S", Align: tview.AlignRight},
+		{Name: "msgRateDelivered", Title: "DELIVER/S", Align: tview.AlignRight},
+	}...)
+
+	if v.wideMode {
+		c = append(c, ui.TableColumn{Name: "msgRateAcked", Title: "ACK/S", Align: tview.AlignRight})
+	}
+
+	if v.connection == "" && v.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	if v.wideMode {
+		c = append(c, ui.TableColumn{Name: "node", Title: "NODE"})
+	}
+
+	return c
+}
+
+func (v *Channels) CanDeleteResources() bool {
+	return false
+}
+
+func (v *Channels) DeleteResource(_ *ChannelResource) error {
+	return nil
+}
+
+func (v *Channels) bindKeys(km ui.KeyMap) {
+	km.Add(ui.KeyO, ui.NewKeyAction("Show consumers", v.showConsumersCmd))
+	km.Add(tcell.KeyCtrlW, ui.NewKeyAction("Toggle wide mode", v.toggleWideModeCmd))
+}
+
+func (v *Channels) showConsumersCmd(*tcell.EventKey) *tcell.EventKey {
+	if channel, ok := v.GetSelectedResource(); ok {
+		v.App().AddView(NewChannelConsumersFn(channel.Name, channel.Vhost))
+	}
+
+	return nil
+}
+
+func (v *Channels) toggleWideModeCmd(*tcell.EventKey) *tcell.EventKey {
+	v.wideMode = !v.wideMode
+
+	v.RequestUpdate(view.FullUpdate)
+
+	return nil
+}
+
+func (v *Channels) showDetails(channel *ChannelResource) {
+	details := NewChannelDetails(channel.Name)
+
+	v.App().AddView(details)
+}
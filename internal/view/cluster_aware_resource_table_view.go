@@ -1,6 +1,7 @@
 package view
 
 import (
+	"tbunny/internal/alerts"
 	"tbunny/internal/cluster"
 	"tbunny/internal/model"
 )
@@ -31,6 +32,14 @@ func (v *ClusterAwareResourceTableView[R]) Init(app model.App) (err error) {
 
 	v.cluster = cluster.Current()
 
+	if ra, ok := v.resourceProvider.(ResourceAlerter); ok && v.cluster != nil {
+		v.alerts.key = alertKey{cluster: v.cluster.Name(), resource: ra.AlertResource()}
+		v.alerts.rules, err = alerts.Compile(v.cluster.AlertRules(), ra.AlertResource())
+		if err != nil {
+			app.StatusLine().Errorf("Invalid alert rules: %s", err)
+		}
+	}
+
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"tbunny/internal/ui"
 	"tbunny/internal/view"
 	"tbunny/internal/view/clusters/dialogs"
+	"tbunny/internal/view/definitions"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -73,6 +74,41 @@ func (c *Clusters) DeleteResource(r *ClusterResource) error {
 
 func (c *Clusters) bindKeys(km ui.KeyMap) {
 	km.Add(ui.KeyA, ui.NewKeyAction("Add cluster", c.addClusterCmd))
+	km.Add(ui.KeyX, ui.NewKeyAction("Export definitions", c.exportDefinitionsCmd))
+	km.Add(ui.KeyI, ui.NewKeyAction("Import definitions", c.importDefinitionsCmd))
+}
+
+func (c *Clusters) exportDefinitionsCmd(*tcell.EventKey) *tcell.EventKey {
+	if active, ok := c.getSelectedActiveCluster(); ok {
+		definitions.Export(c.App(), active, "")
+	}
+
+	return nil
+}
+
+func (c *Clusters) importDefinitionsCmd(*tcell.EventKey) *tcell.EventKey {
+	if active, ok := c.getSelectedActiveCluster(); ok {
+		definitions.Import(c.App(), active, "")
+	}
+
+	return nil
+}
+
+// getSelectedActiveCluster returns the selected cluster if it is the one we are connected to.
+// Definitions can only be transferred through an active connection.
+func (c *Clusters) getSelectedActiveCluster() (*cluster.Cluster, bool) {
+	row, ok := c.GetSelectedResource()
+	if !ok {
+		return nil, false
+	}
+
+	active := cluster.Current()
+	if !row.active || active == nil || !active.IsAvailable() {
+		c.App().StatusLine().Errorf("Connect to cluster %s first", row.name)
+		return nil, false
+	}
+
+	return active, true
 }
 
 func (c *Clusters) selectCluster(row *ClusterResource) {
@@ -14,14 +14,17 @@ type AddClusterFn func(name string, parameters cluster.ConnectionParameters)
 const (
 	directTypeOption = "Direct"
 	k8sTypeOption    = "Kubernetes"
-	usernameLabel    = "Username:"
-	passwordLabel    = "Password:"
+	// k8sDiscoveryTypeOption picks a RabbitMQ instance discovered in a context.
+	k8sDiscoveryTypeOption = "Kubernetes discovery"
+	usernameLabel          = "Username:"
+	passwordLabel          = "Password:"
 )
 
-func ShowAddClusterDialog(mm model.ModalManager, okFn AddClusterFn) {
+func ShowAddClusterDialog(app model.App, okFn AddClusterFn) {
 	f := ui.NewModalForm()
+	discovery := &k8sDiscovery{app: app}
 
-	f.AddInputField("Name:", "", 30, nil, nil)
+	f.AddInputField(clusterNameFieldLabel, "", 30, nil, nil)
 	f.AddDropDown("Type:", getAllowedTypes(), 0, nil)
 
 	f.AddButtons([]string{"Cancel", "Create"})
@@ -36,7 +39,8 @@ func ShowAddClusterDialog(mm model.ModalManager, okFn AddClusterFn) {
 
 	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 		if buttonIndex != 1 {
-			mm.DismissModal()
+			discovery.reset()
+			app.DismissModal()
 			return
 		}
 
@@ -84,6 +88,16 @@ func ShowAddClusterDialog(mm model.ModalManager, okFn AddClusterFn) {
 				Username: username,
 				Password: password,
 			}
+		case k8sDiscoveryTypeOption:
+			k8sParams, ok := collectKubernetesDiscoveryParameters(f, discovery)
+			if !ok {
+				return
+			}
+			params = cluster.ConnectionParameters{
+				K8s:      k8sParams,
+				Username: username,
+				Password: password,
+			}
 		}
 
 		okFn(name, params)
@@ -101,11 +115,15 @@ func ShowAddClusterDialog(mm model.ModalManager, okFn AddClusterFn) {
 			f.RemoveFormItem(2)
 		}
 
+		discovery.reset()
+
 		switch text {
 		case directTypeOption:
 			typeFieldsCount = createDirectConnectionFields(f)
 		case k8sTypeOption:
 			typeFieldsCount = createKubernetesConnectionFields(f)
+		case k8sDiscoveryTypeOption:
+			typeFieldsCount = createKubernetesDiscoveryFields(f, discovery)
 		}
 
 		createUsernameAndPasswordFields(f)
@@ -114,16 +132,16 @@ func ShowAddClusterDialog(mm model.ModalManager, okFn AddClusterFn) {
 		modal.Resize(formWidth, baseFormHeight+typeFieldsCount)
 	})
 
-	mm.ShowModal(modal)
+	app.ShowModal(modal)
 }
 
 func getAllowedTypes() []string {
-	t := make([]string, 0, 2)
+	t := make([]string, 0, 3)
 
 	t = append(t, directTypeOption)
 
 	if kubernetesIsAvailable() {
-		t = append(t, k8sTypeOption)
+		t = append(t, k8sTypeOption, k8sDiscoveryTypeOption)
 	}
 
 	return t
@@ -1,14 +1,28 @@
 package dialogs
 
 import (
+	"os"
+	"slices"
 	"strings"
 	"tbunny/internal/cluster"
 	"tbunny/internal/ui"
+	"tbunny/internal/utils"
 
 	"github.com/rivo/tview"
 )
 
-const directConnectionUriFieldLabel = "URI:"
+const (
+	directConnectionUriFieldLabel = "URI:"
+	tlsCAFileFieldLabel           = "CA file:"
+	tlsCertFileFieldLabel         = "Client cert:"
+	tlsKeyFileFieldLabel          = "Client key:"
+	tlsServerNameFieldLabel       = "Server name:"
+	tlsMinVersionFieldLabel       = "Min TLS version:"
+	tlsSkipVerifyFieldLabel       = "Skip TLS verify:"
+)
+
+// defaultTLSVersion is the minimum TLS version selected by default.
+const defaultTLSVersion = "1.2"
 
 func createDirectConnectionFields(f *ui.ModalForm) int {
 	uriField := tview.NewInputField().
@@ -18,7 +32,18 @@ func createDirectConnectionFields(f *ui.ModalForm) int {
 
 	f.AddFormItem(uriField)
 
-	return 1
+	// TLS fields, used for https:// URIs.
+	f.AddInputField(tlsCAFileFieldLabel, "", 30, nil, nil)
+	f.AddInputField(tlsCertFileFieldLabel, "", 30, nil, nil)
+	f.AddInputField(tlsKeyFileFieldLabel, "", 30, nil, nil)
+	f.AddInputField(tlsServerNameFieldLabel, "", 30, nil, nil)
+	f.AddDropDown(tlsMinVersionFieldLabel, cluster.TLSVersions, slices.Index(cluster.TLSVersions, defaultTLSVersion), nil)
+	f.AddCheckbox(tlsSkipVerifyFieldLabel, false, nil)
+
+	f.GetFormItemByLabel(tlsCAFileFieldLabel).(*tview.InputField).SetPlaceholder("~/certs/ca.pem")
+	f.GetFormItemByLabel(tlsServerNameFieldLabel).(*tview.InputField).SetPlaceholder("host of the URI")
+
+	return 7
 }
 
 func collectDirectConnectionParameters(f *ui.ModalForm) (*cluster.DirectConnectionParameters, bool) {
@@ -30,7 +55,72 @@ func collectDirectConnectionParameters(f *ui.ModalForm) (*cluster.DirectConnecti
 		return nil, false
 	}
 
+	tlsParams, ok := collectTLSParameters(f)
+	if !ok {
+		return nil, false
+	}
+
 	return &cluster.DirectConnectionParameters{
 		Uri: uri,
+		TLS: tlsParams,
 	}, true
 }
+
+// collectTLSParameters returns the TLS parameters of the form, or nil when
+// they are the default ones.
+func collectTLSParameters(f *ui.ModalForm) (*cluster.TLSParameters, bool) {
+	p := cluster.TLSParameters{
+		ServerName:         strings.TrimSpace(f.GetFormItemByLabel(tlsServerNameFieldLabel).(*tview.InputField).GetText()),
+		InsecureSkipVerify: f.GetFormItemByLabel(tlsSkipVerifyFieldLabel).(*tview.Checkbox).IsChecked(),
+	}
+
+	files := []struct {
+		label string
+		value *string
+	}{
+		{tlsCAFileFieldLabel, &p.CAFile},
+		{tlsCertFileFieldLabel, &p.CertFile},
+		{tlsKeyFileFieldLabel, &p.KeyFile},
+	}
+
+	for _, file := range files {
+		*file.value = strings.TrimSpace(f.GetFormItemByLabel(file.label).(*tview.InputField).GetText())
+
+		if *file.value != "" && !validateFile(*file.value) {
+			f.SetFocus(f.GetFormItemIndex(file.label))
+			return nil, false
+		}
+	}
+
+	// A client certificate goes with its key.
+	if (p.CertFile == "") != (p.KeyFile == "") {
+		label := tlsKeyFileFieldLabel
+		if p.CertFile == "" {
+			label = tlsCertFileFieldLabel
+		}
+
+		f.SetFocus(f.GetFormItemIndex(label))
+		return nil, false
+	}
+
+	if _, version := f.GetFormItemByLabel(tlsMinVersionFieldLabel).(*tview.DropDown).GetCurrentOption(); version != defaultTLSVersion {
+		p.MinVersion = version
+	}
+
+	if p == (cluster.TLSParameters{}) {
+		return nil, true
+	}
+
+	return &p, true
+}
+
+func validateFile(path string) bool {
+	expanded, err := utils.ExpandPath(path)
+	if err != nil {
+		return false
+	}
+
+	info, err := os.Stat(expanded)
+
+	return err == nil && !info.IsDir()
+}
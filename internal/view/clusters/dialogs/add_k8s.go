@@ -2,15 +2,15 @@ package dialogs
 
 import (
 	"maps"
-	"os"
-	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"tbunny/internal/cluster"
 	"tbunny/internal/ui"
 
 	"github.com/rivo/tview"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -18,8 +18,16 @@ const (
 	k8sConnectionContextFieldLabel   = "Context:"
 	k8sConnectionNamespaceFieldLabel = "Namespace:"
 	k8sInstanceNameFieldLabel        = "Instance name:"
+	k8sServiceFieldLabel             = "Service:"
+	k8sSelectorFieldLabel            = "Label selector:"
+	k8sPortFieldLabel                = "Port:"
+	k8sPodPolicyFieldLabel           = "Pod policy:"
+	k8sOrdinalFieldLabel             = "Pod ordinal:"
 )
 
+// k8sPorts are the management ports offered, without and with TLS.
+var k8sPorts = []string{"15672", "15671"}
+
 var (
 	k8sConfigLoader sync.Once
 	k8sConfig       *api.Config
@@ -27,9 +35,7 @@ var (
 
 func kubernetesIsAvailable() bool {
 	k8sConfigLoader.Do(func() {
-		kubeConfigFile := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-
-		k8sConfig, _ = clientcmd.LoadFromFile(kubeConfigFile)
+		k8sConfig, _ = cluster.LoadKubeConfig()
 	})
 
 	return k8sConfig != nil && len(k8sConfig.Contexts) > 0
@@ -56,7 +62,17 @@ func createKubernetesConnectionFields(f *ui.ModalForm) int {
 	f.AddFormItem(namespaceField)
 	f.AddFormItem(instanceNameField)
 
-	return 3
+	f.AddInputField(k8sServiceFieldLabel, "", 30, nil, nil)
+	f.AddInputField(k8sSelectorFieldLabel, "", 30, nil, nil)
+	f.AddDropDown(k8sPortFieldLabel, k8sPorts, 0, nil)
+	f.AddDropDown(k8sPodPolicyFieldLabel, cluster.PodPolicies, 0, nil)
+	f.AddInputField(k8sOrdinalFieldLabel, "", 30, tview.InputFieldInteger, nil)
+
+	f.GetFormItemByLabel(k8sServiceFieldLabel).(*tview.InputField).SetPlaceholder("forward to its pods instead")
+	f.GetFormItemByLabel(k8sSelectorFieldLabel).(*tview.InputField).SetPlaceholder("app.kubernetes.io/name=<instance>")
+	f.GetFormItemByLabel(k8sOrdinalFieldLabel).(*tview.InputField).SetPlaceholder("0, for the ordinal policy")
+
+	return 8
 }
 
 func collectKubernetesConnectionParameters(f *ui.ModalForm) (*cluster.K8sConnectionParameters, bool) {
@@ -78,9 +94,38 @@ func collectKubernetesConnectionParameters(f *ui.ModalForm) (*cluster.K8sConnect
 		return nil, false
 	}
 
-	return &cluster.K8sConnectionParameters{
+	params := &cluster.K8sConnectionParameters{
 		Context:   context,
 		Namespace: namespace,
 		Name:      instanceName,
-	}, true
+		Service:   strings.TrimSpace(f.GetFormItemByLabel(k8sServiceFieldLabel).(*tview.InputField).GetText()),
+		Selector:  strings.TrimSpace(f.GetFormItemByLabel(k8sSelectorFieldLabel).(*tview.InputField).GetText()),
+	}
+
+	if params.Selector != "" {
+		if _, err := labels.Parse(params.Selector); err != nil {
+			f.SetFocus(f.GetFormItemIndex(k8sSelectorFieldLabel))
+			return nil, false
+		}
+	}
+
+	if _, port := f.GetFormItemByLabel(k8sPortFieldLabel).(*tview.DropDown).GetCurrentOption(); port != k8sPorts[0] {
+		params.Port, _ = strconv.Atoi(port)
+	}
+
+	if _, policy := f.GetFormItemByLabel(k8sPodPolicyFieldLabel).(*tview.DropDown).GetCurrentOption(); policy != cluster.PodPolicyFirstReady {
+		params.PodPolicy = policy
+	}
+
+	if params.PodPolicy == cluster.PodPolicyOrdinal {
+		ordinal, err := strconv.Atoi(f.GetFormItemByLabel(k8sOrdinalFieldLabel).(*tview.InputField).GetText())
+		if err != nil || ordinal < 0 {
+			f.SetFocus(f.GetFormItemIndex(k8sOrdinalFieldLabel))
+			return nil, false
+		}
+
+		params.Ordinal = ordinal
+	}
+
+	return params, true
 }
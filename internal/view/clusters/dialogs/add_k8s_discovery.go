@@ -0,0 +1,140 @@
+package dialogs
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"tbunny/internal/cluster"
+	"tbunny/internal/config"
+	"tbunny/internal/model"
+	"tbunny/internal/ui"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	k8sDiscoveryInstanceFieldLabel = "Instance:"
+	clusterNameFieldLabel          = "Name:"
+)
+
+// k8sDiscovery holds the RabbitMQ instances discovered in the selected
+// context. It is only accessed from the UI goroutine.
+type k8sDiscovery struct {
+	app        model.App
+	context    string
+	instances  []cluster.DiscoveredInstance
+	filledName string
+}
+
+// reset ignores the results of a running discovery.
+func (d *k8sDiscovery) reset() {
+	d.context = ""
+	d.instances = nil
+}
+
+func createKubernetesDiscoveryFields(f *ui.ModalForm, d *k8sDiscovery) int {
+	availableContexts := slices.Sorted(maps.Keys(k8sConfig.Contexts))
+
+	contextField := tview.NewDropDown().
+		SetLabel(k8sConnectionContextFieldLabel).
+		SetFieldWidth(30)
+	instanceField := tview.NewDropDown().
+		SetLabel(k8sDiscoveryInstanceFieldLabel).
+		SetFieldWidth(30)
+
+	f.AddFormItem(contextField)
+	f.AddFormItem(instanceField)
+
+	contextField.SetOptions(availableContexts, func(k8sContext string, _ int) {
+		d.discover(f, instanceField, k8sContext)
+	})
+	contextField.SetCurrentOption(0)
+
+	return 2
+}
+
+// discover lists the RabbitMQ instances of the context in the background,
+// then offers them in the instance field.
+func (d *k8sDiscovery) discover(f *ui.ModalForm, instanceField *tview.DropDown, k8sContext string) {
+	d.context = k8sContext
+	d.instances = nil
+
+	instanceField.SetOptions([]string{"Discovering..."}, nil)
+	instanceField.SetCurrentOption(0)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), config.Current().ConnectionTimeout)
+		defer cancel()
+
+		instances, err := cluster.DiscoverK8sInstances(ctx, k8sContext)
+
+		d.app.QueueUpdateDraw(func() {
+			// Another context or connection type was selected in the meantime.
+			if d.context != k8sContext {
+				return
+			}
+
+			if err != nil {
+				d.app.StatusLine().Errorf("Failed to discover RabbitMQ clusters in %s: %s", k8sContext, err)
+			} else if len(instances) == 0 {
+				d.app.StatusLine().Warningf("No RabbitMQ clusters found in %s", k8sContext)
+			}
+
+			if len(instances) == 0 {
+				instanceField.SetOptions([]string{"None"}, nil)
+				instanceField.SetCurrentOption(0)
+				return
+			}
+
+			d.instances = instances
+
+			options := make([]string, len(instances))
+			for i := range instances {
+				options[i] = instances[i].String()
+			}
+
+			instanceField.SetOptions(options, func(_ string, index int) {
+				d.fill(f, index)
+			})
+			instanceField.SetCurrentOption(0)
+		})
+	}()
+}
+
+// fill pre-fills the cluster name and the credentials of the selected instance.
+func (d *k8sDiscovery) fill(f *ui.ModalForm, index int) {
+	if index < 0 || index >= len(d.instances) {
+		return
+	}
+
+	instance := d.instances[index]
+
+	// The name is replaced as long as it was not typed.
+	nameField := f.GetFormItemByLabel(clusterNameFieldLabel).(*tview.InputField)
+	if name := nameField.GetText(); name == "" || name == d.filledName {
+		d.filledName = instance.Parameters.Name
+		nameField.SetText(d.filledName)
+	}
+
+	if i := f.GetFormItemIndex(usernameLabel); i >= 0 {
+		f.GetFormItem(i).(*tview.InputField).SetText(instance.Username)
+	}
+
+	if i := f.GetFormItemIndex(passwordLabel); i >= 0 {
+		f.GetFormItem(i).(*tview.InputField).SetText(instance.Password)
+	}
+}
+
+func collectKubernetesDiscoveryParameters(f *ui.ModalForm, d *k8sDiscovery) (*cluster.K8sConnectionParameters, bool) {
+	instanceField := f.GetFormItemByLabel(k8sDiscoveryInstanceFieldLabel).(*tview.DropDown)
+
+	index, _ := instanceField.GetCurrentOption()
+	if index < 0 || index >= len(d.instances) {
+		f.SetFocus(f.GetFormItemIndex(k8sDiscoveryInstanceFieldLabel))
+		return nil, false
+	}
+
+	params := d.instances[index].Parameters
+
+	return &params, true
+}
@@ -6,10 +6,13 @@ import (
 	"strings"
 	"tbunny/internal/model"
 	"tbunny/internal/skins"
+	"tbunny/internal/ui"
+	"tbunny/internal/ui/dialog"
 	"tbunny/internal/utils"
 	"tbunny/internal/view"
 	"time"
 
+	"github.com/gdamore/tcell/v2"
 	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
 	"github.com/rivo/tview"
 )
@@ -40,6 +43,7 @@ func NewConnectionDetails(name string) *ConnectionDetails {
 	}
 
 	v.SetUpdateFn(v.performUpdate)
+	v.AddBindingKeysFn(v.bindKeys)
 
 	return v
 }
@@ -56,6 +60,40 @@ func (v *ConnectionDetails) Init(app model.App) error {
 	return nil
 }
 
+func (v *ConnectionDetails) bindKeys(km ui.KeyMap) {
+	if v.Cluster().IsAvailable() {
+		km.Add(tcell.KeyCtrlD, ui.NewKeyAction("Close connection", v.closeConnectionCmd))
+	}
+}
+
+func (v *ConnectionDetails) closeConnectionCmd(*tcell.EventKey) *tcell.EventKey {
+	msg := fmt.Sprintf("Close connection %s?", v.name)
+
+	modal := dialog.CreateConfirmDialog(
+		skins.Current(),
+		"Confirm Close",
+		msg,
+		func() {
+			v.App().StatusLine().Infof("Closing connection %s...", v.name)
+
+			_, err := v.Cluster().CloseConnection(v.name)
+			if err != nil {
+				v.App().StatusLine().Errorf("Failed to close connection: %s", err)
+				return
+			}
+
+			v.App().StatusLine().Infof("Closed connection %s", v.name)
+			v.App().CloseLastView()
+		},
+		func() {
+			v.App().DismissModal()
+		})
+
+	v.App().ShowModal(modal)
+
+	return nil
+}
+
 func (v *ConnectionDetails) performUpdate(view.UpdateKind) {
 	connection, err := v.Cluster().GetConnection(v.name)
 	if err != nil {
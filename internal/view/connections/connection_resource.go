@@ -64,3 +64,14 @@ func (r *ConnectionResource) GetTableColumnValue(columnName string) string {
 		return ""
 	}
 }
+
+func (r *ConnectionResource) GetTableColumnSortValue(columnName string) (float64, bool) {
+	switch columnName {
+	case "fromClient":
+		return float64(r.RecvOctDetails.Rate), true
+	case "toClient":
+		return float64(r.SendOctDetails.Rate), true
+	default:
+		return 0, false
+	}
+}
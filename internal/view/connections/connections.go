@@ -3,11 +3,13 @@ package connections
 import (
 	"fmt"
 	"log/slog"
+	"tbunny/internal/alerts"
 	"tbunny/internal/model"
 	"tbunny/internal/sl"
 	"tbunny/internal/ui"
 	"tbunny/internal/utils"
 	"tbunny/internal/view"
+	"tbunny/internal/view/channels"
 	"tbunny/internal/view/vhosts"
 
 	"github.com/gdamore/tcell/v2"
@@ -36,6 +38,11 @@ func NewConnections() model.View {
 	return v
 }
 
+// AlertResource returns the type of resources of alert rules applying to the view.
+func (v *Connections) AlertResource() string {
+	return alerts.Connections
+}
+
 func (v *Connections) GetResources() ([]*ConnectionResource, error) {
 	exchanges, err := v.getConnections()
 	if err != nil {
@@ -115,6 +122,7 @@ func (v *Connections) DeleteResource(resource *ConnectionResource) error {
 }
 
 func (v *Connections) bindKeys(km ui.KeyMap) {
+	km.Add(ui.KeyH, ui.NewKeyAction("Show channels", v.showChannelsCmd))
 	km.Add(tcell.KeyCtrlW, ui.NewKeyAction("Toggle wide mode", v.toggleWideModeCmd))
 }
 
@@ -126,6 +134,14 @@ func (v *Connections) toggleWideModeCmd(*tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+func (v *Connections) showChannelsCmd(*tcell.EventKey) *tcell.EventKey {
+	if connection, ok := v.GetSelectedResource(); ok {
+		v.App().AddView(channels.NewConnectionChannels(connection.Name))
+	}
+
+	return nil
+}
+
 func (v *Connections) showDetails(connection *ConnectionResource) {
 	details := NewConnectionDetails(connection.Name)
 
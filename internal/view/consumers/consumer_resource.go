@@ -0,0 +1,70 @@
+package consumers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"tbunny/internal/rmq"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+)
+
+type ConsumerResource struct {
+	rmq.ConsumerInfo
+}
+
+func (r *ConsumerResource) GetName() string {
+	return r.ConsumerTag
+}
+
+func (r *ConsumerResource) GetDisplayName() string {
+	return "consumer " + r.ConsumerTag
+}
+
+func (r *ConsumerResource) GetTableRowID() string {
+	return fmt.Sprintf("%s-%s", r.ChannelDetails.Name, r.ConsumerTag)
+}
+
+func (r *ConsumerResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "tag":
+		return r.ConsumerTag
+	case "vhost":
+		return r.Queue.Vhost
+	case "queue":
+		return r.Queue.Name
+	case "channel":
+		return r.ChannelDetails.Name
+	case "connection":
+		return r.ChannelDetails.ConnectionName
+	case "username":
+		return r.ChannelDetails.User
+	case "node":
+		return r.ChannelDetails.Node
+	case "ackRequired":
+		return view.FormatBool(bool(r.AcknowledgementMode))
+	case "exclusive":
+		return view.FormatBool(r.Exclusive)
+	case "prefetch":
+		return strconv.Itoa(r.PrefetchCount)
+	case "active":
+		return view.FormatBool(r.Active)
+	case "activityStatus":
+		return r.ActivityStatus
+	case "arguments":
+		return r.getArguments()
+	default:
+		return ""
+	}
+}
+
+func (r *ConsumerResource) getArguments() string {
+	pairs := utils.NewKeyValuePairsFromMap(r.Arguments).Sort()
+	parts := make([]string, 0, len(pairs))
+
+	for _, kvp := range pairs {
+		parts = append(parts, fmt.Sprintf("%s=%v", kvp.Key, kvp.Value))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,236 @@
+package consumers
+
+import (
+	"fmt"
+	"log/slog"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/skins"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/ui/dialog"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/channels"
+	"tbunny/internal/view/connections"
+	"tbunny/internal/view/vhosts"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type Consumers struct {
+	view.ClusterAwareResourceView[*ConsumerResource]
+
+	queue    string
+	channel  string
+	vhost    string
+	wideMode bool
+}
+
+func init() {
+	// The channels package cannot depend on this one, which depends on it
+	// through the connections package.
+	channels.NewChannelConsumersFn = NewChannelConsumers
+}
+
+// NewConsumers creates a view with consumers of the active virtual host.
+func NewConsumers() model.View {
+	v := &Consumers{
+		ClusterAwareResourceView: vhosts.NewVHostExtender[*ConsumerResource](
+			view.NewClusterAwareResourceTableView[*ConsumerResource]("Consumers", view.NewLiveUpdateStrategy()),
+		),
+	}
+
+	v.init()
+
+	return v
+}
+
+// NewQueueConsumers creates a view with consumers of a single queue.
+func NewQueueConsumers(queue, vhost string) model.View {
+	v := &Consumers{
+		ClusterAwareResourceView: view.NewClusterAwareResourceTableView[*ConsumerResource]("Consumers", view.NewLiveUpdateStrategy()),
+		queue:                    queue,
+		vhost:                    vhost,
+	}
+
+	v.SetPath(view.VhostDisplayName(vhost) + " ⏵ " + queue)
+	v.init()
+
+	return v
+}
+
+// NewChannelConsumers creates a view with consumers of a single channel.
+func NewChannelConsumers(channel, vhost string) model.View {
+	v := &Consumers{
+		ClusterAwareResourceView: view.NewClusterAwareResourceTableView[*ConsumerResource]("Consumers", view.NewLiveUpdateStrategy()),
+		channel:                  channel,
+		vhost:                    vhost,
+	}
+
+	v.SetPath(channel)
+	v.init()
+
+	return v
+}
+
+func (v *Consumers) init() {
+	v.SetResourceProvider(v)
+	v.SetEnterAction("Show connection", v.showConnection)
+	v.AddBindingKeysFn(v.bindKeys)
+}
+
+func (v *Consumers) GetResources() ([]*ConsumerResource, error) {
+	consumers, err := v.getConsumers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumers: %w", err)
+	}
+
+	rows := utils.FilterMap(
+		consumers,
+		func(c rmq.ConsumerInfo) bool {
+			return (v.queue == "" || c.Queue.Name == v.queue) && (v.channel == "" || c.ChannelDetails.Name == v.channel)
+		},
+		func(c rmq.ConsumerInfo) *ConsumerResource {
+			return &ConsumerResource{c}
+		})
+
+	return rows, nil
+}
+
+func (v *Consumers) getConsumers() (consumers []rmq.ConsumerInfo, err error) {
+	c := v.Cluster()
+
+	vhost := v.vhost
+	if !v.isScoped() {
+		vhost = c.ActiveVirtualHost()
+	}
+
+	slog.Debug("Fetching consumers", sl.Component, v.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+
+	if vhost == "" {
+		consumers, err = c.ListConsumers()
+	} else {
+		consumers, err = c.ListConsumersIn(vhost)
+	}
+
+	if err != nil {
+		slog.Error("Failed to fetch consumers", sl.Error, err, sl.Component, v.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+	}
+
+	return consumers, err
+}
+
+func (v *Consumers) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "tag", Title: "CONSUMER TAG", Expansion: 1},
+	}
+
+	if v.queue == "" {
+		c = append(c, ui.TableColumn{Name: "queue", Title: "QUEUE", Expansion: 1})
+	}
+
+	if v.channel == "" {
+		c = append(c, ui.TableColumn{Name: "channel", Title: "CHANNEL", Expansion: 2})
+	}
+
+	if v.wideMode {
+		c = append(c, []ui.TableColumn{
+			{Name: "connection", Title: "CONNECTION", Expansion: 1},
+			{Name: "username", Title: "USER NAME"},
+		}...)
+	}
+
+	c = append(c, []ui.TableColumn{
+		{Name: "ackRequired", Title: "ACK", Align: tview.AlignCenter},
+		{Name: "exclusive", Title: "EXCLUSIVE", Align: tview.AlignCenter},
+		{Name: "prefetch", Title: "PREFETCH", Align: tview.AlignRight},
+		{Name: "active", Title: "ACTIVE", Align: tview.AlignCenter},
+	}...)
+
+	if v.wideMode {
+		c = append(c, ui.TableColumn{Name: "activityStatus", Title: "STATUS"})
+	}
+
+	c = append(c, ui.TableColumn{Name: "arguments", Title: "ARGUMENTS", Expansion: 1, MaxWidth: 40})
+
+	if !v.isScoped() && v.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	if v.wideMode {
+		c = append(c, ui.TableColumn{Name: "node", Title: "NODE"})
+	}
+
+	return c
+}
+
+// isScoped reports whether the view shows the consumers of a queue or a
+// channel, rather than those of the active virtual host.
+func (v *Consumers) isScoped() bool {
+	return v.queue != "" || v.channel != ""
+}
+
+func (v *Consumers) CanDeleteResources() bool {
+	return false
+}
+
+func (v *Consumers) DeleteResource(_ *ConsumerResource) error {
+	return nil
+}
+
+func (v *Consumers) bindKeys(km ui.KeyMap) {
+	if v.Cluster().IsAvailable() {
+		km.Add(ui.KeyX, ui.NewKeyAction("Close connection", v.closeConnectionCmd))
+	}
+
+	km.Add(tcell.KeyCtrlW, ui.NewKeyAction("Toggle wide mode", v.toggleWideModeCmd))
+}
+
+func (v *Consumers) toggleWideModeCmd(*tcell.EventKey) *tcell.EventKey {
+	v.wideMode = !v.wideMode
+
+	v.RequestUpdate(view.FullUpdate)
+
+	return nil
+}
+
+func (v *Consumers) showConnection(consumer *ConsumerResource) {
+	details := connections.NewConnectionDetails(consumer.ChannelDetails.ConnectionName)
+
+	v.App().AddView(details)
+}
+
+func (v *Consumers) closeConnectionCmd(*tcell.EventKey) *tcell.EventKey {
+	consumer, ok := v.GetSelectedResource()
+	if !ok {
+		return nil
+	}
+
+	name := consumer.ChannelDetails.ConnectionName
+	msg := fmt.Sprintf("Close connection %s used by %s?", name, consumer.GetDisplayName())
+
+	modal := dialog.CreateConfirmDialog(
+		skins.Current(),
+		"Confirm Close",
+		msg,
+		func() {
+			v.App().StatusLine().Infof("Closing connection %s...", name)
+
+			_, err := v.Cluster().CloseConnection(name)
+			if err != nil {
+				v.App().StatusLine().Errorf("Failed to close connection: %s", err)
+			} else {
+				v.App().StatusLine().Infof("Closed connection %s", name)
+				v.RequestUpdate(view.PartialUpdate)
+			}
+		},
+		func() {
+			v.App().DismissModal()
+		})
+
+	v.App().ShowModal(modal)
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package definitions
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui/dialog"
+	"tbunny/internal/utils"
+	"time"
+)
+
+const (
+	componentName       = "Definitions"
+	jsonFilePlaceholder = "Path to a JSON file"
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Export asks for a file name and exports definitions of the whole cluster
+// (if vhost is empty) or of a single virtual host into it.
+func Export(app model.App, c *cluster.Cluster, vhost string) {
+	dialog.ShowFileDialog(app, "Export definitions of "+targetName(c, vhost), "Export", defaultFileName(c, vhost), jsonFilePlaceholder, func(path string) {
+		app.StatusLine().Infof("Exporting definitions of %s...", targetName(c, vhost))
+
+		if err := exportDefinitions(c, vhost, path); err != nil {
+			slog.Error("Failed to export definitions", sl.Error, err, sl.Component, componentName, sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+			app.StatusLine().Errorf("Failed to export definitions: %s", err)
+			return
+		}
+
+		app.StatusLine().Infof("Definitions of %s exported to %s", targetName(c, vhost), path)
+		app.DismissModal()
+	})
+}
+
+// Import asks for a file name, shows a preview of its contents and imports
+// definitions into the whole cluster (if vhost is empty) or a single virtual host.
+func Import(app model.App, c *cluster.Cluster, vhost string) {
+	dialog.ShowFileDialog(app, "Import definitions into "+targetName(c, vhost), "Preview", "", jsonFilePlaceholder, func(path string) {
+		data, summary, err := readDefinitions(path)
+		if err != nil {
+			app.StatusLine().Errorf("Failed to read definitions: %s", err)
+			return
+		}
+
+		app.DismissModal()
+
+		if vhost != "" {
+			summary = summary.ForVhost(vhost)
+		}
+
+		ShowImportPreviewDialog(app, targetName(c, vhost), summary, func() {
+			app.StatusLine().Infof("Importing definitions into %s...", targetName(c, vhost))
+
+			if vhost == "" {
+				err = c.ImportDefinitions(data)
+			} else {
+				err = c.ImportVhostDefinitions(vhost, data)
+			}
+
+			if err != nil {
+				slog.Error("Failed to import definitions", sl.Error, err, sl.Component, componentName, sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+				app.StatusLine().Errorf("Failed to import definitions: %s", err)
+				return
+			}
+
+			app.StatusLine().Infof("Definitions imported from %s", path)
+			app.DismissModal()
+
+			c.Refresh()
+		})
+	})
+}
+
+func exportDefinitions(c *cluster.Cluster, vhost, path string) error {
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+
+	if vhost == "" {
+		data, err = c.ExportDefinitions()
+	} else {
+		data, err = c.ExportVhostDefinitions(vhost)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+func readDefinitions(path string) ([]byte, *rmq.DefinitionsSummary, error) {
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary, err := rmq.ParseDefinitionsSummary(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid definitions file: %w", err)
+	}
+
+	return data, summary, nil
+}
+
+func targetName(c *cluster.Cluster, vhost string) string {
+	if vhost == "" {
+		return "cluster " + c.Name()
+	}
+
+	return "virtual host " + vhost
+}
+
+func defaultFileName(c *cluster.Cluster, vhost string) string {
+	name := c.Name()
+	if vhost != "" {
+		name += "-" + vhost
+	}
+
+	name = unsafeFileNameChars.ReplaceAllString(name, "_")
+
+	return fmt.Sprintf("%s-definitions-%s.json", name, time.Now().Format("20060102-150405"))
+}
@@ -0,0 +1,87 @@
+package definitions
+
+import (
+	"fmt"
+	"strings"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+)
+
+const (
+	previewNamesLimit = 10
+	previewHeight     = 14
+)
+
+// ShowImportPreviewDialog shows what a definitions file contains and asks
+// the user to confirm the import.
+func ShowImportPreviewDialog(mm model.ModalManager, target string, summary *rmq.DefinitionsSummary, okFn func()) {
+	f := ui.NewModalForm()
+
+	f.AddTextView("", formatSummary(target, summary), 0, previewHeight, false, true)
+	f.AddButtons([]string{"Cancel", "Import"})
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		okFn()
+	})
+
+	f.SetTitle("Import definitions")
+
+	modal := ui.NewModalDialog(f, 90, previewHeight+5)
+	mm.ShowModal(modal)
+}
+
+func formatSummary(target string, s *rmq.DefinitionsSummary) string {
+	b := &strings.Builder{}
+
+	utils.Sbprintf(b, "The following definitions will be imported into %s", target)
+	if s.RabbitVersion != "" {
+		utils.Sbprintf(b, " (exported from RabbitMQ %s)", s.RabbitVersion)
+	}
+	b.WriteString(":\n\n")
+
+	writeSection(b, "Virtual hosts", utils.Map(s.Vhosts, func(d rmq.NamedDefinition) string { return d.Name }))
+	writeSection(b, "Users", utils.Map(s.Users, func(d rmq.NamedDefinition) string { return d.Name }))
+	writeSection(b, "Permissions", utils.Map(s.Permissions, func(d rmq.PermissionDefinition) string {
+		return fmt.Sprintf("%s@%s", d.User, d.Vhost)
+	}))
+	writeSection(b, "Policies", utils.Map(s.Policies, qualifiedName))
+	writeSection(b, "Parameters", utils.Map(s.Parameters, func(d rmq.NamedDefinition) string {
+		return fmt.Sprintf("%s/%s", d.Component, d.Name)
+	}))
+	writeSection(b, "Queues", utils.Map(s.Queues, qualifiedName))
+	writeSection(b, "Exchanges", utils.Map(s.Exchanges, qualifiedName))
+	writeSection(b, "Bindings", utils.Map(s.Bindings, func(d rmq.BindingDefinition) string {
+		return fmt.Sprintf("%s → %s %s", d.Source, d.DestinationType, d.Destination)
+	}))
+
+	return b.String()
+}
+
+func writeSection(b *strings.Builder, title string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	utils.Sbprintf(b, "%s (%d): ", title, len(names))
+
+	if len(names) > previewNamesLimit {
+		utils.Sbprintf(b, "%s and %d more\n", strings.Join(names[:previewNamesLimit], ", "), len(names)-previewNamesLimit)
+	} else {
+		utils.Sbprintf(b, "%s\n", strings.Join(names, ", "))
+	}
+}
+
+func qualifiedName(d rmq.NamedDefinition) string {
+	if d.Vhost == "" {
+		return d.Name
+	}
+
+	return d.Vhost + "/" + d.Name
+}
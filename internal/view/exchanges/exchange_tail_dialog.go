@@ -0,0 +1,44 @@
+package exchanges
+
+import (
+	"fmt"
+	"tbunny/internal/model"
+	"tbunny/internal/ui"
+	"tbunny/internal/view"
+
+	"github.com/rivo/tview"
+)
+
+type TailExchangeFn func(exchange *ExchangeResource, routingKey string, args map[string]any)
+
+func ShowTailExchangeDialog(mm model.ModalManager, exchange *ExchangeResource, okFn TailExchangeFn) {
+	f := ui.NewModalForm()
+
+	routingKey := ""
+	if exchange.Type == "topic" {
+		routingKey = "#"
+	}
+
+	f.AddInputField("Routing key:", routingKey, 30, nil, nil)
+
+	argsField := ui.NewArguments().SetLabel("Arguments:")
+	f.AddFormItem(argsField)
+
+	f.AddButtons([]string{"Cancel", "Tail"})
+
+	routingKeyField := f.GetFormItem(0).(*tview.InputField)
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		okFn(exchange, routingKeyField.GetText(), argsField.GetValue())
+	})
+
+	f.SetTitle(fmt.Sprintf("Tail exchange %s", view.ExchangeDisplayName(exchange.Name)))
+
+	modal := ui.NewModalDialog(f, 80, 7+argsField.GetFieldHeight())
+	mm.ShowModal(modal)
+}
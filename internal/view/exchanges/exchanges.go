@@ -3,12 +3,15 @@ package exchanges
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"tbunny/internal/model"
+	"tbunny/internal/publish"
 	"tbunny/internal/sl"
 	"tbunny/internal/ui"
 	"tbunny/internal/utils"
 	"tbunny/internal/view"
 	"tbunny/internal/view/bindings"
+	"tbunny/internal/view/queues"
 	"tbunny/internal/view/vhosts"
 
 	"github.com/gdamore/tcell/v2"
@@ -68,6 +71,12 @@ func (e *Exchanges) getExchanges() (exchanges []rabbithole.ExchangeInfo, err err
 }
 
 func (e *Exchanges) GetColumns() []ui.TableColumn {
+	return Columns(e.Cluster().ActiveVirtualHost() == "")
+}
+
+// Columns returns exchange table columns; withVhost adds the virtual host
+// column for tables listing exchanges of all virtual hosts.
+func Columns(withVhost bool) []ui.TableColumn {
 	c := []ui.TableColumn{
 		{Name: "name", Title: "NAME", Expansion: 2},
 		{Name: "type", Title: "TYPE"},
@@ -76,7 +85,7 @@ func (e *Exchanges) GetColumns() []ui.TableColumn {
 		{Name: "msgRateOut", Title: "MO/S", Align: tview.AlignRight},
 	}
 
-	if e.Cluster().ActiveVirtualHost() == "" {
+	if withVhost {
 		c = append(c, []ui.TableColumn{{Name: "vhost", Title: "VHOST"}}...)
 	}
 
@@ -95,6 +104,109 @@ func (e *Exchanges) DeleteResource(resource *ExchangeResource) error {
 
 func (e *Exchanges) bindKeys(km ui.KeyMap) {
 	km.Add(ui.KeyC, ui.NewKeyAction("Create", e.createExchangeCmd))
+
+	if e.Cluster().IsAvailable() {
+		km.Add(ui.KeyP, ui.NewKeyAction("Publish message", e.publishMessageCmd))
+		km.Add(ui.KeyT, ui.NewKeyAction("Tail", e.tailExchangeCmd))
+		km.Add(ui.KeyI, ui.NewKeyAction("Import messages", e.importMessagesCmd))
+	}
+}
+
+func (e *Exchanges) publishMessageCmd(*tcell.EventKey) *tcell.EventKey {
+	exchange, ok := e.GetSelectedResource()
+	if !ok {
+		return nil
+	}
+
+	opts := queues.PublishMessageDialogOptions{
+		Title:          fmt.Sprintf("Publish message to exchange %s", view.ExchangeDisplayName(exchange.Name)),
+		Vhost:          exchange.Vhost,
+		Exchange:       exchange.Name,
+		WithRoutingKey: true,
+		RoutingKeys:    e.getRoutingKeys(exchange),
+	}
+
+	queues.ShowPublishMessageDialog(e.App(), opts, func(msg *publish.Message, repeat publish.Repeat) {
+		e.publishMessage(msg, repeat)
+	})
+
+	return nil
+}
+
+// getRoutingKeys returns the routing keys of the exchange bindings, or queue
+// names for the default exchange.
+func (e *Exchanges) getRoutingKeys(exchange *ExchangeResource) []string {
+	c := e.Cluster()
+
+	if exchange.Name == "" {
+		items, err := c.ListQueuesIn(exchange.Vhost)
+		if err != nil {
+			slog.Error("Failed to fetch queues", sl.Error, err, sl.Cluster, c.Name(), sl.VirtualHost, exchange.Vhost)
+			return nil
+		}
+
+		return utils.Map(items, func(q rabbithole.QueueInfo) string { return q.Name })
+	}
+
+	items, err := c.ListExchangeBindingsWithSource(exchange.Vhost, exchange.Name)
+	if err != nil {
+		slog.Error("Failed to fetch bindings", sl.Error, err, sl.Cluster, c.Name(), sl.VirtualHost, exchange.Vhost, sl.Resource, exchange.Name)
+		return nil
+	}
+
+	keys := utils.Map(items, func(b rabbithole.BindingInfo) string { return b.RoutingKey })
+
+	return slices.Compact(slices.Sorted(slices.Values(keys)))
+}
+
+func (e *Exchanges) publishMessage(msg *publish.Message, repeat publish.Repeat) {
+	if repeat.IsRepeated() {
+		queues.PublishRepeatedly(e.App(), e.Cluster(), msg, repeat, nil)
+		return
+	}
+
+	// The dialog stays open to try another routing key when not routed.
+	queues.Publish(e.App(), e.Cluster(), msg, func(routed bool) {
+		if routed {
+			e.App().DismissModal()
+		}
+	})
+}
+
+func (e *Exchanges) importMessagesCmd(*tcell.EventKey) *tcell.EventKey {
+	exchange, ok := e.GetSelectedResource()
+	if !ok {
+		return nil
+	}
+
+	title := fmt.Sprintf("Publish messages to exchange %s", view.ExchangeDisplayName(exchange.Name))
+
+	queues.ShowImportMessagesDialog(e.App(), title, true, func(path, routingKey string) {
+		queues.ImportMessages(e.App(), e.Cluster(), exchange.Vhost, exchange.Name, routingKey, path, nil)
+	})
+
+	return nil
+}
+
+func (e *Exchanges) tailExchangeCmd(*tcell.EventKey) *tcell.EventKey {
+	exchange, ok := e.GetSelectedResource()
+	if !ok {
+		return nil
+	}
+
+	if exchange.Name == "" {
+		e.App().StatusLine().Error("The default exchange cannot be tailed, tail its queue instead")
+		return nil
+	}
+
+	ShowTailExchangeDialog(e.App(), exchange, e.tailExchange)
+
+	return nil
+}
+
+func (e *Exchanges) tailExchange(exchange *ExchangeResource, routingKey string, args map[string]any) {
+	e.App().DismissModal()
+	e.App().AddView(queues.NewExchangeTail(exchange.Vhost, exchange.Name, routingKey, args))
 }
 
 func (e *Exchanges) createExchangeCmd(*tcell.EventKey) *tcell.EventKey {
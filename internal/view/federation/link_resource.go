@@ -0,0 +1,56 @@
+package federation
+
+import (
+	"fmt"
+	"tbunny/internal/rmq"
+	"tbunny/internal/view"
+)
+
+type LinkResource struct {
+	rmq.FederationLink
+}
+
+func (r *LinkResource) GetName() string {
+	return r.Upstream
+}
+
+func (r *LinkResource) GetDisplayName() string {
+	return "federation link " + r.Id
+}
+
+func (r *LinkResource) GetTableRowID() string {
+	return fmt.Sprintf("%s-%s-%s", r.Vhost, r.Node, r.Id)
+}
+
+func (r *LinkResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "vhost":
+		return r.Vhost
+	case "upstream":
+		return r.Upstream
+	case "type":
+		return r.Type
+	case "status":
+		return r.Status
+	case "local":
+		if r.Type == "queue" {
+			return r.Queue
+		}
+		return view.ExchangeDisplayName(r.Exchange)
+	case "remote":
+		if r.Type == "queue" {
+			return r.UpstreamQueue
+		}
+		return view.ExchangeDisplayName(r.UpstreamExchange)
+	case "uri":
+		return r.Uri
+	case "node":
+		return r.Node
+	case "timestamp":
+		return r.Timestamp
+	case "error":
+		return r.Error
+	default:
+		return ""
+	}
+}
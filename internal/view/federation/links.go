@@ -0,0 +1,116 @@
+package federation
+
+import (
+	"fmt"
+	"log/slog"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/vhosts"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type Links struct {
+	view.ClusterAwareResourceView[*LinkResource]
+
+	wideMode bool
+}
+
+func NewLinks() model.View {
+	l := Links{
+		vhosts.NewVHostExtender[*LinkResource](
+			view.NewClusterAwareResourceTableView[*LinkResource]("Federation links", view.NewLiveUpdateStrategy()),
+		),
+		false,
+	}
+
+	l.SetResourceProvider(&l)
+	l.AddBindingKeysFn(l.bindKeys)
+
+	return &l
+}
+
+func (l *Links) GetResources() ([]*LinkResource, error) {
+	links, err := l.getLinks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list federation links: %w", err)
+	}
+
+	rows := utils.Map(links, func(i rmq.FederationLink) *LinkResource {
+		return &LinkResource{i}
+	})
+
+	return rows, nil
+}
+
+func (l *Links) getLinks() (links []rmq.FederationLink, err error) {
+	c := l.Cluster()
+	vhost := c.ActiveVirtualHost()
+
+	slog.Debug("Fetching federation links", sl.Component, l.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+
+	if vhost == "" {
+		links, err = c.ListFederationLinkStatuses()
+	} else {
+		links, err = c.ListFederationLinkStatusesIn(vhost)
+	}
+
+	if err != nil {
+		slog.Error("Failed to fetch federation links", sl.Error, err, sl.Component, l.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+	}
+
+	return links, err
+}
+
+func (l *Links) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "upstream", Title: "UPSTREAM", Expansion: 1},
+		{Name: "type", Title: "TYPE"},
+		{Name: "status", Title: "STATUS"},
+		{Name: "local", Title: "LOCAL", Expansion: 1},
+		{Name: "remote", Title: "UPSTREAM RESOURCE", Expansion: 1},
+	}
+
+	if l.wideMode {
+		c = append(c, []ui.TableColumn{
+			{Name: "uri", Title: "URI", MaxWidth: 40},
+			{Name: "timestamp", Title: "SINCE"},
+		}...)
+	}
+
+	if l.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	if l.wideMode {
+		c = append(c, ui.TableColumn{Name: "node", Title: "NODE"})
+	}
+
+	c = append(c, ui.TableColumn{Name: "error", Title: "ERROR", Expansion: 2, MaxWidth: 60})
+
+	return c
+}
+
+func (l *Links) CanDeleteResources() bool {
+	return false
+}
+
+func (l *Links) DeleteResource(_ *LinkResource) error {
+	return nil
+}
+
+func (l *Links) bindKeys(km ui.KeyMap) {
+	km.Add(tcell.KeyCtrlW, ui.NewKeyAction("Toggle wide mode", l.toggleWideModeCmd))
+}
+
+func (l *Links) toggleWideModeCmd(*tcell.EventKey) *tcell.EventKey {
+	l.wideMode = !l.wideMode
+
+	l.RequestUpdate(view.FullUpdate)
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package federation
+
+import (
+	"fmt"
+	"strconv"
+	"tbunny/internal/utils"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+type UpstreamResource struct {
+	rabbithole.FederationUpstream
+}
+
+func (r *UpstreamResource) GetName() string {
+	return r.Name
+}
+
+func (r *UpstreamResource) GetDisplayName() string {
+	return "upstream " + r.Name
+}
+
+func (r *UpstreamResource) GetTableRowID() string {
+	return fmt.Sprintf("%s-%s", r.Vhost, r.Name)
+}
+
+func (r *UpstreamResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "vhost":
+		return r.Vhost
+	case "name":
+		return r.Name
+	case "uri":
+		return utils.RedactURIs(r.Definition.Uri)
+	case "prefetch":
+		return formatOptionalInt(r.Definition.PrefetchCount)
+	case "reconnectDelay":
+		return formatOptionalInt(r.Definition.ReconnectDelay)
+	case "ackMode":
+		return r.Definition.AckMode
+	case "maxHops":
+		return formatOptionalInt(r.Definition.MaxHops)
+	case "exchange":
+		return r.Definition.Exchange
+	case "queue":
+		return r.Definition.Queue
+	default:
+		return ""
+	}
+}
+
+func formatOptionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(v)
+}
@@ -0,0 +1,38 @@
+package federation
+
+import (
+	"fmt"
+	"strings"
+	"tbunny/internal/rmq"
+	"tbunny/internal/utils"
+)
+
+type UpstreamSetResource struct {
+	rmq.FederationUpstreamSet
+}
+
+func (r *UpstreamSetResource) GetName() string {
+	return r.Name
+}
+
+func (r *UpstreamSetResource) GetDisplayName() string {
+	return "upstream set " + r.Name
+}
+
+func (r *UpstreamSetResource) GetTableRowID() string {
+	return fmt.Sprintf("%s-%s", r.Vhost, r.Name)
+}
+
+func (r *UpstreamSetResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "vhost":
+		return r.Vhost
+	case "name":
+		return r.Name
+	case "upstreams":
+		names := utils.Map(r.Upstreams, func(m rmq.FederationUpstreamSetMember) string { return m.Upstream })
+		return strings.Join(names, ", ")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,82 @@
+package federation
+
+import (
+	"fmt"
+	"log/slog"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/vhosts"
+)
+
+type UpstreamSets struct {
+	view.ClusterAwareResourceView[*UpstreamSetResource]
+}
+
+func NewUpstreamSets() model.View {
+	u := UpstreamSets{
+		vhosts.NewVHostExtender[*UpstreamSetResource](
+			view.NewClusterAwareResourceTableView[*UpstreamSetResource]("Upstream sets", view.NewLiveUpdateStrategy()),
+		),
+	}
+
+	u.SetResourceProvider(&u)
+
+	return &u
+}
+
+func (u *UpstreamSets) GetResources() ([]*UpstreamSetResource, error) {
+	sets, err := u.getUpstreamSets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upstream sets: %w", err)
+	}
+
+	rows := utils.Map(sets, func(i rmq.FederationUpstreamSet) *UpstreamSetResource {
+		return &UpstreamSetResource{i}
+	})
+
+	return rows, nil
+}
+
+func (u *UpstreamSets) getUpstreamSets() (sets []rmq.FederationUpstreamSet, err error) {
+	c := u.Cluster()
+	vhost := c.ActiveVirtualHost()
+
+	slog.Debug("Fetching upstream sets", sl.Component, u.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+
+	if vhost == "" {
+		sets, err = c.ListFederationUpstreamSets()
+	} else {
+		sets, err = c.ListFederationUpstreamSetsIn(vhost)
+	}
+
+	if err != nil {
+		slog.Error("Failed to fetch upstream sets", sl.Error, err, sl.Component, u.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+	}
+
+	return sets, err
+}
+
+func (u *UpstreamSets) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "name", Title: "NAME", Expansion: 1},
+		{Name: "upstreams", Title: "UPSTREAMS", Expansion: 3},
+	}
+
+	if u.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	return c
+}
+
+func (u *UpstreamSets) CanDeleteResources() bool {
+	return true
+}
+
+func (u *UpstreamSets) DeleteResource(resource *UpstreamSetResource) error {
+	return u.Cluster().DeleteFederationUpstreamSet(resource.Vhost, resource.Name)
+}
@@ -0,0 +1,138 @@
+package federation
+
+import (
+	"fmt"
+	"log/slog"
+	"tbunny/internal/model"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/vhosts"
+
+	"github.com/gdamore/tcell/v2"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/rivo/tview"
+)
+
+type Upstreams struct {
+	view.ClusterAwareResourceView[*UpstreamResource]
+}
+
+func NewUpstreams() model.View {
+	u := Upstreams{
+		vhosts.NewVHostExtender[*UpstreamResource](
+			view.NewClusterAwareResourceTableView[*UpstreamResource]("Federation upstreams", view.NewLiveUpdateStrategy()),
+		),
+	}
+
+	u.SetResourceProvider(&u)
+	u.AddBindingKeysFn(u.bindKeys)
+	u.SetEnterAction("Edit", u.editUpstream)
+
+	return &u
+}
+
+func (u *Upstreams) GetResources() ([]*UpstreamResource, error) {
+	upstreams, err := u.getUpstreams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list federation upstreams: %w", err)
+	}
+
+	rows := utils.Map(upstreams, func(i rabbithole.FederationUpstream) *UpstreamResource {
+		return &UpstreamResource{i}
+	})
+
+	return rows, nil
+}
+
+func (u *Upstreams) getUpstreams() (upstreams []rabbithole.FederationUpstream, err error) {
+	c := u.Cluster()
+	vhost := c.ActiveVirtualHost()
+
+	slog.Debug("Fetching federation upstreams", sl.Component, u.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+
+	if vhost == "" {
+		upstreams, err = c.ListFederationUpstreams()
+	} else {
+		upstreams, err = c.ListFederationUpstreamsIn(vhost)
+	}
+
+	if err != nil {
+		slog.Error("Failed to fetch federation upstreams", sl.Error, err, sl.Component, u.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+	}
+
+	return upstreams, err
+}
+
+func (u *Upstreams) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "name", Title: "NAME", Expansion: 2},
+		{Name: "uri", Title: "URI", Expansion: 2, MaxWidth: 60},
+		{Name: "prefetch", Title: "PREFETCH", Align: tview.AlignRight},
+		{Name: "reconnectDelay", Title: "RECONNECT", Align: tview.AlignRight},
+		{Name: "ackMode", Title: "ACK MODE"},
+		{Name: "maxHops", Title: "MAX HOPS", Align: tview.AlignRight},
+	}
+
+	if u.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	return c
+}
+
+func (u *Upstreams) CanDeleteResources() bool {
+	return true
+}
+
+func (u *Upstreams) DeleteResource(resource *UpstreamResource) error {
+	_, err := u.Cluster().DeleteFederationUpstream(resource.Vhost, resource.Name)
+
+	return err
+}
+
+func (u *Upstreams) bindKeys(km ui.KeyMap) {
+	if u.Cluster().IsAvailable() {
+		km.Add(ui.KeyC, ui.NewKeyAction("Create", u.createUpstreamCmd))
+		km.Add(ui.KeyL, ui.NewKeyAction("Links", u.showLinksCmd))
+		km.Add(ui.KeyS, ui.NewKeyAction("Upstream sets", u.showUpstreamSetsCmd))
+	}
+}
+
+func (u *Upstreams) createUpstreamCmd(*tcell.EventKey) *tcell.EventKey {
+	ShowCreateUpstreamDialog(u.App(), u.putUpstream)
+
+	return nil
+}
+
+func (u *Upstreams) editUpstream(upstream *UpstreamResource) {
+	ShowEditUpstreamDialog(u.App(), upstream, u.putUpstream)
+}
+
+func (u *Upstreams) showLinksCmd(*tcell.EventKey) *tcell.EventKey {
+	u.App().AddView(NewLinks())
+
+	return nil
+}
+
+func (u *Upstreams) showUpstreamSetsCmd(*tcell.EventKey) *tcell.EventKey {
+	u.App().AddView(NewUpstreamSets())
+
+	return nil
+}
+
+func (u *Upstreams) putUpstream(vhost, name string, definition rabbithole.FederationDefinition) {
+	u.App().StatusLine().Infof("Saving upstream %s", name)
+
+	_, err := u.Cluster().PutFederationUpstream(vhost, name, definition)
+	if err != nil {
+		slog.Error("Failed to save federation upstream", sl.Error, err, sl.Component, u.Name(), sl.Cluster, u.Cluster().Name(), sl.VirtualHost, vhost, sl.Resource, name)
+		u.App().StatusLine().Errorf("Failed to save upstream %s: %s", name, err.Error())
+		return
+	}
+
+	u.App().StatusLine().Infof("Saved upstream %s", name)
+	u.App().DismissModal()
+	u.RequestUpdate(view.PartialUpdate)
+}
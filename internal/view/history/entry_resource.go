@@ -0,0 +1,73 @@
+package history
+
+import (
+	"strconv"
+	"strings"
+	"tbunny/internal/publish"
+	"tbunny/internal/view"
+	"time"
+)
+
+// maxPayloadPreview is the number of payload characters shown in the table.
+const maxPayloadPreview = 80
+
+type EntryResource struct {
+	*publish.HistoryEntry
+}
+
+func (r *EntryResource) GetName() string {
+	return r.Time.Format(time.DateTime)
+}
+
+func (r *EntryResource) GetDisplayName() string {
+	return "message published at " + r.GetName()
+}
+
+func (r *EntryResource) GetTableRowID() string {
+	return r.Time.Format(time.RFC3339Nano)
+}
+
+func (r *EntryResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "time":
+		return r.Time.Format(time.DateTime)
+	case "vhost":
+		return r.Vhost
+	case "exchange":
+		return view.ExchangeDisplayName(r.Exchange)
+	case "routingKey":
+		return r.RoutingKey
+	case "routed":
+		return view.FormatBool(r.Routed)
+	case "length":
+		return strconv.Itoa(len(r.Payload))
+	case "payload":
+		return payloadPreview(&r.Message)
+	default:
+		return ""
+	}
+}
+
+func (r *EntryResource) GetTableColumnSortValue(columnName string) (float64, bool) {
+	switch columnName {
+	case "time":
+		return float64(r.Time.UnixNano()), true
+	case "length":
+		return float64(len(r.Payload)), true
+	}
+
+	return 0, false
+}
+
+func payloadPreview(m *publish.Message) string {
+	if m.PayloadEncoding == "base64" {
+		return "(base64)"
+	}
+
+	s := []rune(strings.Join(strings.Fields(m.Payload), " "))
+	if len(s) > maxPayloadPreview {
+		return string(s[:maxPayloadPreview]) + "…"
+	}
+
+	return string(s)
+}
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"fmt"
+	"slices"
+	"tbunny/internal/model"
+	"tbunny/internal/publish"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/queues"
+	"tbunny/internal/view/vhosts"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// History lists the messages published to the current cluster, to publish
+// them again.
+type History struct {
+	view.ClusterAwareResourceView[*EntryResource]
+}
+
+func NewHistory() model.View {
+	h := History{
+		vhosts.NewVHostExtender[*EntryResource](
+			view.NewClusterAwareResourceTableView[*EntryResource]("Publish history", view.NewManualUpdateStrategy()),
+		),
+	}
+
+	h.SetResourceProvider(&h)
+	h.SetEnterAction("Publish again", h.publishAgain)
+	h.AddBindingKeysFn(h.bindKeys)
+
+	return &h
+}
+
+func (h *History) GetResources() ([]*EntryResource, error) {
+	c := h.Cluster()
+
+	entries, err := publish.History(c.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load publish history: %w", err)
+	}
+
+	if vhost := c.ActiveVirtualHost(); vhost != "" {
+		entries = slices.DeleteFunc(entries, func(e *publish.HistoryEntry) bool { return e.Vhost != vhost })
+	}
+
+	return utils.Map(entries, func(e *publish.HistoryEntry) *EntryResource { return &EntryResource{e} }), nil
+}
+
+func (h *History) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "time", Title: "TIME"},
+	}
+
+	if h.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	return append(c, []ui.TableColumn{
+		{Name: "exchange", Title: "EXCHANGE", Expansion: 1},
+		{Name: "routingKey", Title: "ROUTING KEY", Expansion: 1},
+		{Name: "routed", Title: "ROUTED"},
+		{Name: "length", Title: "LENGTH", Align: tview.AlignRight},
+		{Name: "payload", Title: "PAYLOAD", Expansion: 2},
+	}...)
+}
+
+func (h *History) CanDeleteResources() bool {
+	return true
+}
+
+func (h *History) DeleteResource(resource *EntryResource) error {
+	return publish.DeleteFromHistory(h.Cluster().Name(), resource.Time)
+}
+
+func (h *History) bindKeys(km ui.KeyMap) {
+	if h.Cluster().IsAvailable() {
+		km.Add(ui.KeyE, ui.NewKeyAction("Edit and publish", h.editCmd))
+	}
+}
+
+func (h *History) publishAgain(entry *EntryResource) {
+	msg := entry.Message
+
+	queues.Publish(h.App(), h.Cluster(), &msg, func(bool) {
+		h.RequestUpdate(view.FullUpdate)
+	})
+}
+
+func (h *History) editCmd(*tcell.EventKey) *tcell.EventKey {
+	entry, ok := h.GetSelectedResource()
+	if !ok {
+		return nil
+	}
+
+	opts := queues.PublishMessageDialogOptions{
+		Title:          fmt.Sprintf("Publish message to exchange %s", view.ExchangeDisplayName(entry.Exchange)),
+		Vhost:          entry.Vhost,
+		Exchange:       entry.Exchange,
+		WithRoutingKey: true,
+		Message:        &entry.Message,
+	}
+
+	queues.ShowPublishMessageDialog(h.App(), opts, func(msg *publish.Message, repeat publish.Repeat) {
+		if repeat.IsRepeated() {
+			queues.PublishRepeatedly(h.App(), h.Cluster(), msg, repeat, func() {
+				h.RequestUpdate(view.FullUpdate)
+			})
+			return
+		}
+
+		queues.Publish(h.App(), h.Cluster(), msg, func(routed bool) {
+			if routed {
+				h.App().DismissModal()
+			}
+
+			h.RequestUpdate(view.FullUpdate)
+		})
+	})
+
+	return nil
+}
@@ -2,6 +2,7 @@ package view
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"tbunny/internal/skins"
 	"tbunny/internal/utils"
@@ -22,6 +23,7 @@ type JxFormatter struct {
 	braceColor        string
 	bracketColor      string
 	punctuationColor  string
+	textFn            func(s string) string
 }
 
 func NewJxFormatter(skin *skins.Skin) *JxFormatter {
@@ -44,6 +46,14 @@ func NewJxFormatter(skin *skins.Skin) *JxFormatter {
 	return &f
 }
 
+// SetTextFn sets a function applied to property names and values before they
+// are written, e.g. to highlight parts of them.
+func (f *JxFormatter) SetTextFn(fn func(s string) string) *JxFormatter {
+	f.textFn = fn
+
+	return f
+}
+
 func (f *JxFormatter) Format(d *jx.Decoder) (string, error) {
 	err := f.formatValue(d)
 	if err != nil {
@@ -93,7 +103,7 @@ func (f *JxFormatter) formatObject(d *jx.Decoder) error {
 		first = false
 
 		f.writeIndent()
-		utils.Sbprintf(f.b, "[%s:%s:-]%q[%s:%s:-]: ", f.propertyNameColor, f.bgColor, string(iter.Key()), f.punctuationColor, f.bgColor)
+		utils.Sbprintf(f.b, "[%s:%s:-]%s[%s:%s:-]: ", f.propertyNameColor, f.bgColor, f.quoted(string(iter.Key())), f.punctuationColor, f.bgColor)
 
 		err = f.formatValue(d)
 		if err != nil {
@@ -155,7 +165,7 @@ func (f *JxFormatter) formatString(d *jx.Decoder) error {
 		return err
 	}
 
-	utils.Sbprintf(f.b, "[%s:%s:-]%q", f.stringColor, f.bgColor, v)
+	utils.Sbprintf(f.b, "[%s:%s:-]%s", f.stringColor, f.bgColor, f.quoted(v))
 
 	return nil
 }
@@ -166,7 +176,7 @@ func (f *JxFormatter) formatNumber(d *jx.Decoder) error {
 		return err
 	}
 
-	utils.Sbprintf(f.b, "[%s:%s:-]%v", f.numberColor, f.bgColor, v)
+	utils.Sbprintf(f.b, "[%s:%s:-]%s", f.numberColor, f.bgColor, f.text(v.String()))
 
 	return nil
 }
@@ -177,7 +187,7 @@ func (f *JxFormatter) formatBoolean(d *jx.Decoder) error {
 		return err
 	}
 
-	utils.Sbprintf(f.b, "[%s:%s:-]%t", f.booleanColor, f.bgColor, v)
+	utils.Sbprintf(f.b, "[%s:%s:-]%s", f.booleanColor, f.bgColor, f.text(strconv.FormatBool(v)))
 
 	return nil
 }
@@ -193,6 +203,21 @@ func (f *JxFormatter) formatNull(d *jx.Decoder) error {
 	return nil
 }
 
+func (f *JxFormatter) text(s string) string {
+	if f.textFn == nil {
+		return s
+	}
+
+	return f.textFn(s)
+}
+
+// quoted returns a JSON string, applying the text function to its content only.
+func (f *JxFormatter) quoted(s string) string {
+	q := strconv.Quote(s)
+
+	return `"` + f.text(q[1:len(q)-1]) + `"`
+}
+
 func (f *JxFormatter) writeIndent() {
 	n := f.indentLevel * f.indentWidth
 
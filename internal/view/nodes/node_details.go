@@ -3,13 +3,16 @@ package nodes
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"tbunny/internal/metrics"
 	"tbunny/internal/model"
+	"tbunny/internal/rmq"
 	"tbunny/internal/skins"
 	"tbunny/internal/ui"
 	"tbunny/internal/view"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
-	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
 	"github.com/rivo/tview"
 )
 
@@ -18,6 +21,13 @@ const nodeDetailsTitleFmt = " [fg:bg:b]%s[fg:bg:-]([hilite:bg:b]%s[fg:bg:-]) "
 const (
 	// Minimum inner width for the four-gauge columnar layout.
 	minWidthForFourGauges = 105
+
+	// Width of the trends panel, spanning the four gauges (4 x 25 + 3 x 2).
+	trendsPanelWidth = 106
+
+	// Number of points of trend sparklines in four-gauge and scrollable modes.
+	columnarTrendPoints   = 50
+	scrollableTrendPoints = 30
 )
 
 // NodeDetails is a cluster-aware refreshable view that shows a health dashboard for a single node.
@@ -25,9 +35,13 @@ type NodeDetails struct {
 	*view.ClusterAwareRefreshableView[*tview.Flex]
 
 	name string
-	node *rabbithole.NodeInfo
+	node *rmq.NodeInfo
 	skin *skins.Skin
 
+	// history keeps the metrics of the node, displayed as trends over window.
+	history *metrics.History
+	window  atomic.Int64
+
 	useScrollableMode bool
 	relayoutPending   bool
 
@@ -84,6 +98,10 @@ type NodeDetails struct {
 	ioWriteCount *view.LabelAndValue
 	ioWriteBytes *view.LabelAndValue
 
+	// Trends row.
+	trendsPanel *tview.Flex
+	trendsView  *tview.TextView
+
 	// Section headers.
 	memoryHeader    *tview.TextView
 	diskHeader      *tview.TextView
@@ -93,6 +111,7 @@ type NodeDetails struct {
 	connHeader      *tview.TextView
 	queueHeader     *tview.TextView
 	ioHeader        *tview.TextView
+	trendsHeader    *tview.TextView
 
 	// Separators and containers that need background styling.
 	separators []*tview.TextView
@@ -114,8 +133,11 @@ func NewNodeDetails(name string) *NodeDetails {
 		name:                        name,
 	}
 
+	v.window.Store(int64(metrics.DefaultWindow))
+
 	v.SetUpdateFn(v.performUpdate)
 	v.AddBindingKeysFn(v.bindScrollKeys)
+	v.AddBindingKeysFn(v.bindKeys)
 
 	return v
 }
@@ -127,6 +149,7 @@ func (v *NodeDetails) Init(app model.App) error {
 	}
 
 	v.skin = skins.Current()
+	v.history = metrics.For(v.Cluster().Name(), "node", v.name)
 
 	// Start in scrollable mode as a safe fallback.
 	// The actual mode is determined on the first update.
@@ -172,12 +195,17 @@ func (v *NodeDetails) determineLayoutMode() {
 }
 
 func (v *NodeDetails) performUpdate(view.UpdateKind) {
-	node, err := v.Cluster().GetNode(v.name)
+	window := v.trendsWindow()
+	sampleRange := rmq.SampleRange{Age: window, Incr: metrics.SampleInterval(window)}
+
+	node, err := v.Cluster().GetNodeWithSamples(v.name, sampleRange)
 	if err != nil {
 		v.App().StatusLine().Errorf("Failed to fetch node details: %v", err)
 		return
 	}
 
+	v.recordHistory(node)
+
 	v.App().QueueUpdateDraw(func() {
 		v.node = node
 		previousMode := v.useScrollableMode
@@ -195,6 +223,45 @@ func (v *NodeDetails) performUpdate(view.UpdateKind) {
 	})
 }
 
+// recordHistory adds the samples of the node resource usage to its history.
+func (v *NodeDetails) recordHistory(n *rmq.NodeInfo) {
+	now := time.Now()
+
+	v.history.Add("memory", metrics.Gauge(&n.MemUsedDetails, float64(n.MemUsed), now)...)
+	v.history.Add("fd", metrics.Gauge(&n.FdUsedDetails, float64(n.FdUsed), now)...)
+	v.history.Add("sockets", metrics.Gauge(&n.SocketsUsedDetails, float64(n.SocketsUsed), now)...)
+	v.history.Add("disk", metrics.Gauge(&n.DiskFreeDetails, float64(n.DiskFree), now)...)
+}
+
+func (v *NodeDetails) trendsWindow() time.Duration {
+	return time.Duration(v.window.Load())
+}
+
+func (v *NodeDetails) trends(points int) []view.Trend {
+	window := v.trendsWindow()
+
+	return []view.Trend{
+		{Label: "Memory:", Values: v.history.Values("memory", window, points), Format: view.FormatByteSize},
+		{Label: "File desc:", Values: v.history.Values("fd", window, points), Format: view.FormatCount},
+		{Label: "Sockets:", Values: v.history.Values("sockets", window, points), Format: view.FormatCount},
+		{Label: "Disk free:", Values: v.history.Values("disk", window, points), Format: view.FormatByteSize},
+	}
+}
+
+func (v *NodeDetails) bindKeys(km ui.KeyMap) {
+	km.Add(ui.KeyW, ui.NewKeyAction("Cycle trends window", v.cycleTrendsWindowCmd))
+}
+
+func (v *NodeDetails) cycleTrendsWindowCmd(*tcell.EventKey) *tcell.EventKey {
+	window := metrics.NextWindow(v.trendsWindow())
+	v.window.Store(int64(window))
+
+	v.App().StatusLine().Infof("Trends window: %s", view.FormatWindow(window))
+	v.RequestUpdate(view.PartialUpdate)
+
+	return nil
+}
+
 func (v *NodeDetails) updateTitle() {
 	title := view.SkinTitle(fmt.Sprintf(nodeDetailsTitleFmt, v.Name(), v.name))
 	v.Ui().SetTitle(title)
@@ -288,11 +355,25 @@ func (v *NodeDetails) createFourGaugeLayout() {
 	v.detailsPanel.AddItem(v.createIOSection(), 28, 0, false)
 	v.detailsPanel.AddItem(rightPad2, 0, 1, false)
 
+	// Middle row: trends spanning the gauges.
+	v.trendsPanel = tview.NewFlex().SetDirection(tview.FlexColumn)
+	leftPad3 := tview.NewFlex()
+	rightPad3 := tview.NewFlex()
+	v.containers = append(v.containers, leftPad3.Box, rightPad3.Box)
+	v.trendsPanel.AddItem(leftPad3, 0, 1, false)
+	v.trendsPanel.AddItem(v.createTrendsSection(), trendsPanelWidth, 0, false)
+	v.trendsPanel.AddItem(rightPad3, 0, 1, false)
+
 	// Gauges: 1 (header) + 1 (sep) + 3 (grid rows) + 1 (bar) = 6 max (Memory/Disk with alarm).
 	v.Ui().AddItem(v.gaugesPanel, 6, 0, false)
 	blankBox := tview.NewBox()
 	v.containers = append(v.containers, blankBox)
 	v.Ui().AddItem(blankBox, 1, 0, false)
+	// Trends: 1 (header) + 1 (sep) + 4 (trends) = 6.
+	v.Ui().AddItem(v.trendsPanel, 6, 0, false)
+	blankBox2 := tview.NewBox()
+	v.containers = append(v.containers, blankBox2)
+	v.Ui().AddItem(blankBox2, 1, 0, false)
 	v.Ui().AddItem(v.detailsPanel, 0, 1, false)
 }
 
@@ -392,6 +473,19 @@ func (v *NodeDetails) createProcessesGauge() *tview.Flex {
 	return panel
 }
 
+func (v *NodeDetails) createTrendsSection() *tview.Flex {
+	section := tview.NewFlex().SetDirection(tview.FlexRow)
+
+	v.trendsHeader = tview.NewTextView().SetText(view.TrendsCaption(v.trendsWindow()))
+	section.AddItem(v.trendsHeader, 1, 0, false)
+	section.AddItem(v.createSeparator(trendsPanelWidth), 1, 0, false)
+
+	v.trendsView = tview.NewTextView().SetDynamicColors(true).SetWrap(false)
+	section.AddItem(v.trendsView, 4, 0, false)
+
+	return section
+}
+
 func (v *NodeDetails) createOverviewSection() *tview.Flex {
 	section := tview.NewFlex().SetDirection(tview.FlexRow)
 
@@ -554,6 +648,9 @@ func (v *NodeDetails) applyStyles() {
 		v.ioWriteCount.ApplyStyles(labelStyle, valueStyle)
 		v.ioWriteBytes.ApplyStyles(labelStyle, valueStyle)
 
+		// Trends.
+		v.trendsView.SetBackgroundColor(bgColor)
+
 		// Section headers.
 		v.memoryHeader.SetTextStyle(captionStyle)
 		v.diskHeader.SetTextStyle(captionStyle)
@@ -563,6 +660,7 @@ func (v *NodeDetails) applyStyles() {
 		v.connHeader.SetTextStyle(captionStyle)
 		v.queueHeader.SetTextStyle(captionStyle)
 		v.ioHeader.SetTextStyle(captionStyle)
+		v.trendsHeader.SetTextStyle(captionStyle)
 
 		// Separators.
 		sepStyle := tcell.StyleDefault.Foreground(skin.CaptionFgColor.Color()).Background(bgColor)
@@ -576,6 +674,7 @@ func (v *NodeDetails) applyStyles() {
 		}
 
 		v.gaugesPanel.SetBackgroundColor(bgColor)
+		v.trendsPanel.SetBackgroundColor(bgColor)
 		v.detailsPanel.SetBackgroundColor(bgColor)
 	}
 }
@@ -659,6 +758,12 @@ func (v *NodeDetails) updateColumnarView() {
 	v.ioReadBytes.SetBytes(int64(n.IOReadBytes))
 	v.ioWriteCount.SetText(fmt.Sprintf("%d", n.IOWriteCount))
 	v.ioWriteBytes.SetBytes(int64(n.IOWriteBytes))
+
+	// Trends row.
+	trends := new(strings.Builder)
+	view.WriteTrends(trends, v.trends(columnarTrendPoints))
+	v.trendsHeader.SetText(view.TrendsCaption(v.trendsWindow()))
+	v.trendsView.SetText(view.SkinStatsContent(trends.String(), &v.skin.Views.Stats))
 }
 
 func (v *NodeDetails) updateScrollableView() {
@@ -718,6 +823,9 @@ func (v *NodeDetails) updateScrollableView() {
 		{Label: "Usage:", Value: view.GetStateColor(procPct, v.skin) + view.RenderProgressBar(procPct, 20, view.DefaultProgressBarStyle) + "[-]"},
 	})
 
+	// Trends.
+	view.WriteTrendsSection(b, v.trendsWindow(), v.trends(scrollableTrendPoints))
+
 	// Overview.
 	view.WriteTextSection(b, "Overview", []view.TextRow{
 		{Label: "Name:", Value: n.Name},
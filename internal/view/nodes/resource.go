@@ -56,6 +56,25 @@ func (r *Resource) GetTableColumnValue(columnName string) string {
 	return ""
 }
 
+func (r *Resource) GetTableColumnSortValue(columnName string) (float64, bool) {
+	switch columnName {
+	case "mem_used":
+		return float64(r.MemUsed), true
+	case "mem_limit":
+		return float64(r.MemLimit), true
+	case "disk_free":
+		return float64(r.DiskFree), true
+	case "fd_used":
+		return float64(r.FdUsed), true
+	case "proc_used":
+		return float64(r.ProcUsed), true
+	case "uptime":
+		return float64(r.Uptime), true
+	}
+
+	return 0, false
+}
+
 // formatUptime formats a duration given in milliseconds as a human-readable string.
 // e.g. "2d 5h 30m 12s" or "45m 3s".
 func formatUptime(ms uint64) string {
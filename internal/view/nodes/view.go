@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"log/slog"
+	"tbunny/internal/alerts"
 	"tbunny/internal/model"
 	"tbunny/internal/sl"
 	"tbunny/internal/ui"
@@ -43,6 +44,11 @@ func (v *View) GetColumns() []ui.TableColumn {
 	}
 }
 
+// AlertResource returns the type of resources of alert rules applying to the view.
+func (v *View) AlertResource() string {
+	return alerts.Nodes
+}
+
 func (v *View) GetResources() ([]*Resource, error) {
 	nodes, err := v.getNodes()
 	if err != nil {
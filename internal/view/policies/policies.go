@@ -0,0 +1,158 @@
+package policies
+
+import (
+	"fmt"
+	"log/slog"
+	"tbunny/internal/model"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"tbunny/internal/view/vhosts"
+
+	"github.com/gdamore/tcell/v2"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/rivo/tview"
+)
+
+type Policies struct {
+	view.ClusterAwareResourceView[*PolicyResource]
+}
+
+func NewPolicies() model.View {
+	p := Policies{
+		vhosts.NewVHostExtender[*PolicyResource](
+			view.NewClusterAwareResourceTableView[*PolicyResource]("Policies", view.NewLiveUpdateStrategy()),
+		),
+	}
+
+	p.SetResourceProvider(&p)
+	p.AddBindingKeysFn(p.bindKeys)
+	p.SetEnterAction("Edit", p.editPolicy)
+
+	return &p
+}
+
+func (p *Policies) GetResources() ([]*PolicyResource, error) {
+	policies, operatorPolicies, err := p.getPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	rows := make([]*PolicyResource, 0, len(policies)+len(operatorPolicies))
+
+	rows = append(rows, utils.Map(policies, func(i rabbithole.Policy) *PolicyResource {
+		return &PolicyResource{i, RegularPolicy}
+	})...)
+
+	rows = append(rows, utils.Map(operatorPolicies, func(i rabbithole.OperatorPolicy) *PolicyResource {
+		return &PolicyResource{rabbithole.Policy(i), OperatorPolicy}
+	})...)
+
+	return rows, nil
+}
+
+func (p *Policies) getPolicies() (policies []rabbithole.Policy, operatorPolicies []rabbithole.OperatorPolicy, err error) {
+	c := p.Cluster()
+	vhost := c.ActiveVirtualHost()
+
+	slog.Debug("Fetching policies", sl.Component, p.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+
+	if vhost == "" {
+		policies, err = c.ListPolicies()
+		if err == nil {
+			operatorPolicies, err = c.ListOperatorPolicies()
+		}
+	} else {
+		policies, err = c.ListPoliciesIn(vhost)
+		if err == nil {
+			operatorPolicies, err = c.ListOperatorPoliciesIn(vhost)
+		}
+	}
+
+	if err != nil {
+		slog.Error("Failed to fetch policies", sl.Error, err, sl.Component, p.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+	}
+
+	return policies, operatorPolicies, err
+}
+
+func (p *Policies) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "name", Title: "NAME", Expansion: 2},
+		{Name: "kind", Title: "KIND"},
+		{Name: "pattern", Title: "PATTERN", Expansion: 1},
+		{Name: "applyTo", Title: "APPLY TO"},
+		{Name: "priority", Title: "PRIORITY", Align: tview.AlignRight},
+		{Name: "definition", Title: "DEFINITION", Expansion: 3, MaxWidth: 60},
+	}
+
+	if p.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	return c
+}
+
+func (p *Policies) CanDeleteResources() bool {
+	return true
+}
+
+func (p *Policies) DeleteResource(resource *PolicyResource) error {
+	var err error
+
+	if resource.kind == OperatorPolicy {
+		_, err = p.Cluster().DeleteOperatorPolicy(resource.Vhost, resource.Name)
+	} else {
+		_, err = p.Cluster().DeletePolicy(resource.Vhost, resource.Name)
+	}
+
+	return err
+}
+
+func (p *Policies) bindKeys(km ui.KeyMap) {
+	if p.Cluster().IsAvailable() {
+		km.Add(ui.KeyC, ui.NewKeyAction("Create", p.createPolicyCmd))
+		km.Add(ui.KeyE, ui.NewKeyAction("Edit", p.editPolicyCmd))
+	}
+}
+
+func (p *Policies) createPolicyCmd(*tcell.EventKey) *tcell.EventKey {
+	ShowCreatePolicyDialog(p.App(), p.putPolicy)
+
+	return nil
+}
+
+func (p *Policies) editPolicyCmd(*tcell.EventKey) *tcell.EventKey {
+	if policy, ok := p.GetSelectedResource(); ok {
+		p.editPolicy(policy)
+	}
+
+	return nil
+}
+
+func (p *Policies) editPolicy(policy *PolicyResource) {
+	ShowEditPolicyDialog(p.App(), policy, p.putPolicy)
+}
+
+func (p *Policies) putPolicy(kind PolicyKind, policy rabbithole.Policy) {
+	p.App().StatusLine().Infof("Saving %s %s", kind, policy.Name)
+
+	var err error
+
+	if kind == OperatorPolicy {
+		_, err = p.Cluster().PutOperatorPolicy(policy.Vhost, policy.Name, rabbithole.OperatorPolicy(policy))
+	} else {
+		_, err = p.Cluster().PutPolicy(policy.Vhost, policy.Name, policy)
+	}
+
+	if err != nil {
+		slog.Error("Failed to save policy", sl.Error, err, sl.Component, p.Name(), sl.Cluster, p.Cluster().Name(), sl.VirtualHost, policy.Vhost, sl.Resource, policy.Name)
+		p.App().StatusLine().Errorf("Failed to save %s %s: %s", kind, policy.Name, err.Error())
+		return
+	}
+
+	p.App().StatusLine().Infof("Saved %s %s", kind, policy.Name)
+	p.App().DismissModal()
+	p.RequestUpdate(view.PartialUpdate)
+}
@@ -0,0 +1,210 @@
+package policies
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/rivo/tview"
+)
+
+type PolicyFn func(kind PolicyKind, policy rabbithole.Policy)
+
+const (
+	policyNameFieldLabel     = "Name:"
+	policyKindFieldLabel     = "Kind:"
+	policyVhostFieldLabel    = "Virtual host:"
+	policyPatternFieldLabel  = "Pattern:"
+	policyApplyToFieldLabel  = "Apply to:"
+	policyPriorityFieldLabel = "Priority:"
+)
+
+var (
+	policyKindOptions = []string{"User policy", "Operator policy"}
+
+	policyApplyToOptions = []string{
+		string(rabbithole.PolicyTargetAll),
+		string(rabbithole.PolicyTargetExchanges),
+		string(rabbithole.PolicyTargetQueues),
+		string(rabbithole.PolicyTargetClassicQueues),
+		string(rabbithole.PolicyTargetQuorumQueues),
+		string(rabbithole.PolicyTargetStreams),
+	}
+
+	operatorPolicyApplyToOptions = []string{
+		string(rabbithole.PolicyTargetQueues),
+		string(rabbithole.PolicyTargetClassicQueues),
+		string(rabbithole.PolicyTargetQuorumQueues),
+		string(rabbithole.PolicyTargetStreams),
+	}
+)
+
+func ShowCreatePolicyDialog(mm model.ModalManager, okFn PolicyFn) {
+	f := ui.NewModalForm()
+	c := cluster.Current()
+
+	virtualHostNames := utils.Map(c.VirtualHosts(), func(vh rabbithole.VhostInfo) string { return vh.Name })
+	activeVhostNameIndex := max(0, slices.Index(virtualHostNames, c.ActiveVirtualHost()))
+
+	f.AddInputField(policyNameFieldLabel, "", 30, nil, nil)
+	f.AddDropDown(policyKindFieldLabel, policyKindOptions, 0, nil)
+	f.AddDropDown(policyVhostFieldLabel, virtualHostNames, activeVhostNameIndex, nil)
+	f.AddInputField(policyPatternFieldLabel, "", 30, nil, nil)
+	f.AddDropDown(policyApplyToFieldLabel, policyApplyToOptions, 0, nil)
+	f.AddInputField(policyPriorityFieldLabel, "0", 10, tview.InputFieldInteger, nil)
+
+	definitionField := ui.NewArguments().SetLabel("Definition:").SetKeyPlaceholder("Key").SetValuePlaceholder("Value")
+	f.AddFormItem(definitionField)
+
+	f.AddButtons([]string{"Cancel", "Create"})
+
+	nameField := f.GetFormItemByLabel(policyNameFieldLabel).(*tview.InputField)
+	kindField := f.GetFormItemByLabel(policyKindFieldLabel).(*tview.DropDown)
+	vhostField := f.GetFormItemByLabel(policyVhostFieldLabel).(*tview.DropDown)
+	applyToField := f.GetFormItemByLabel(policyApplyToFieldLabel).(*tview.DropDown)
+
+	nameField.SetPlaceholder("Policy name")
+
+	kind := RegularPolicy
+
+	kindField.SetSelectedFunc(func(text string, index int) {
+		newKind := RegularPolicy
+		if index == 1 {
+			newKind = OperatorPolicy
+		}
+
+		if newKind == kind {
+			return
+		}
+
+		kind = newKind
+		applyToField.SetOptions(applyToOptions(kind), nil).SetCurrentOption(0)
+	})
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		name := strings.TrimSpace(nameField.GetText())
+		if name == "" {
+			f.SetFocus(f.GetFormItemIndex(policyNameFieldLabel))
+			return
+		}
+
+		policy, ok := collectPolicy(f, definitionField)
+		if !ok {
+			return
+		}
+
+		_, vhost := vhostField.GetCurrentOption()
+
+		policy.Name = name
+		policy.Vhost = vhost
+
+		okFn(kind, policy)
+	})
+
+	f.SetTitle("Create policy")
+
+	showPolicyDialog(mm, f, definitionField, 6)
+}
+
+func ShowEditPolicyDialog(mm model.ModalManager, policy *PolicyResource, okFn PolicyFn) {
+	f := ui.NewModalForm()
+
+	options := applyToOptions(policy.kind)
+	applyToIndex := max(0, slices.Index(options, policy.ApplyTo))
+
+	f.AddInputField(policyNameFieldLabel, policy.Name, 30, nil, nil)
+	f.AddInputField(policyVhostFieldLabel, policy.Vhost, 30, nil, nil)
+	f.AddInputField(policyPatternFieldLabel, policy.Pattern, 30, nil, nil)
+	f.AddDropDown(policyApplyToFieldLabel, options, applyToIndex, nil)
+	f.AddInputField(policyPriorityFieldLabel, strconv.Itoa(policy.Priority), 10, tview.InputFieldInteger, nil)
+
+	definitionField := ui.NewArguments().SetLabel("Definition:").SetKeyPlaceholder("Key").SetValuePlaceholder("Value")
+	definitionField.SetValue(policy.Definition)
+	f.AddFormItem(definitionField)
+
+	f.AddButtons([]string{"Cancel", "Update"})
+
+	f.GetFormItemByLabel(policyNameFieldLabel).(*tview.InputField).SetDisabled(true)
+	f.GetFormItemByLabel(policyVhostFieldLabel).(*tview.InputField).SetDisabled(true)
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		updated, ok := collectPolicy(f, definitionField)
+		if !ok {
+			return
+		}
+
+		updated.Name = policy.Name
+		updated.Vhost = policy.Vhost
+
+		okFn(policy.kind, updated)
+	})
+
+	f.SetTitle("Edit " + policy.kind.String())
+
+	showPolicyDialog(mm, f, definitionField, 5)
+}
+
+func showPolicyDialog(mm model.ModalManager, f *ui.ModalForm, definitionField *ui.Arguments, fieldsCount int) {
+	const formWidth = 80
+
+	modal := ui.NewModalDialog(f, formWidth, 6+fieldsCount+definitionField.GetFieldHeight())
+	mm.ShowModal(modal)
+
+	definitionField.SetRowsChangedFunc(func(height int) { modal.Resize(formWidth, 6+fieldsCount+height) })
+}
+
+func collectPolicy(f *ui.ModalForm, definitionField *ui.Arguments) (rabbithole.Policy, bool) {
+	patternField := f.GetFormItemByLabel(policyPatternFieldLabel).(*tview.InputField)
+	applyToField := f.GetFormItemByLabel(policyApplyToFieldLabel).(*tview.DropDown)
+	priorityField := f.GetFormItemByLabel(policyPriorityFieldLabel).(*tview.InputField)
+
+	pattern := patternField.GetText()
+	if pattern == "" {
+		f.SetFocus(f.GetFormItemIndex(policyPatternFieldLabel))
+		return rabbithole.Policy{}, false
+	}
+
+	priority, err := strconv.Atoi(strings.TrimSpace(priorityField.GetText()))
+	if err != nil {
+		f.SetFocus(f.GetFormItemIndex(policyPriorityFieldLabel))
+		return rabbithole.Policy{}, false
+	}
+
+	definition := definitionField.GetValue()
+	if len(definition) == 0 {
+		f.SetFocus(f.GetFormItemIndex(definitionField.GetLabel()))
+		return rabbithole.Policy{}, false
+	}
+
+	_, applyTo := applyToField.GetCurrentOption()
+
+	return rabbithole.Policy{
+		Pattern:    pattern,
+		ApplyTo:    applyTo,
+		Priority:   priority,
+		Definition: definition,
+	}, true
+}
+
+func applyToOptions(kind PolicyKind) []string {
+	if kind == OperatorPolicy {
+		return operatorPolicyApplyToOptions
+	}
+
+	return policyApplyToOptions
+}
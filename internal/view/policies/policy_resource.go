@@ -0,0 +1,82 @@
+package policies
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"tbunny/internal/utils"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+const (
+	RegularPolicy  PolicyKind = "policy"
+	OperatorPolicy PolicyKind = "operator"
+)
+
+type PolicyKind string
+
+func (k PolicyKind) String() string {
+	if k == OperatorPolicy {
+		return "operator policy"
+	}
+
+	return "policy"
+}
+
+type PolicyResource struct {
+	rabbithole.Policy
+
+	kind PolicyKind
+}
+
+func (r *PolicyResource) Kind() PolicyKind {
+	return r.kind
+}
+
+func (r *PolicyResource) GetName() string {
+	return r.Name
+}
+
+func (r *PolicyResource) GetDisplayName() string {
+	return r.kind.String() + " " + r.Name
+}
+
+func (r *PolicyResource) GetTableRowID() string {
+	return fmt.Sprintf("%s-%s-%s", r.kind, r.Vhost, r.Name)
+}
+
+func (r *PolicyResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "vhost":
+		return r.Vhost
+	case "name":
+		return r.Name
+	case "kind":
+		if r.kind == OperatorPolicy {
+			return "Operator"
+		}
+		return "User"
+	case "pattern":
+		return r.Pattern
+	case "applyTo":
+		return r.ApplyTo
+	case "priority":
+		return strconv.Itoa(r.Priority)
+	case "definition":
+		return r.getDefinition()
+	default:
+		return ""
+	}
+}
+
+func (r *PolicyResource) getDefinition() string {
+	pairs := utils.NewKeyValuePairsFromMap(r.Definition).Sort()
+	parts := make([]string, 0, len(pairs))
+
+	for _, kvp := range pairs {
+		parts = append(parts, fmt.Sprintf("%s=%v", kvp.Key, kvp.Value))
+	}
+
+	return strings.Join(parts, ", ")
+}
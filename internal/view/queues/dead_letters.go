@@ -0,0 +1,276 @@
+package queues
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// deadLetterGroup is a set of messages dead-lettered from the same queue
+// for the same reason.
+type deadLetterGroup struct {
+	death    rmq.Death
+	messages []*MessageResource
+}
+
+func (g *deadLetterGroup) GetName() string {
+	return fmt.Sprintf("%s (%s)", g.death.Queue, g.death.Reason)
+}
+
+func (g *deadLetterGroup) GetDisplayName() string {
+	return "dead letters from " + g.GetName()
+}
+
+func (g *deadLetterGroup) GetTableRowID() string {
+	return g.death.Queue + "\x00" + g.death.Reason
+}
+
+func (g *deadLetterGroup) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "queue":
+		return g.death.Queue
+	case "reason":
+		return g.death.Reason
+	case "exchange":
+		return view.ExchangeDisplayName(g.death.Exchange)
+	case "routingKeys":
+		return strings.Join(g.routingKeys(), ", ")
+	case "count":
+		return fmt.Sprintf("%d", len(g.messages))
+	}
+
+	return ""
+}
+
+func (g *deadLetterGroup) GetTableColumnSortValue(columnName string) (float64, bool) {
+	if columnName == "count" {
+		return float64(len(g.messages)), true
+	}
+
+	return 0, false
+}
+
+// routingKeys returns the distinct original routing keys of the group messages.
+func (g *deadLetterGroup) routingKeys() []string {
+	var keys []string
+
+	for _, m := range g.messages {
+		if death, ok := m.LastDeath(); ok && len(death.RoutingKeys) > 0 && !slices.Contains(keys, death.RoutingKeys[0]) {
+			keys = append(keys, death.RoutingKeys[0])
+		}
+	}
+
+	return keys
+}
+
+func groupDeadLetters(messages []*MessageResource) []*deadLetterGroup {
+	var groups []*deadLetterGroup
+
+	for _, m := range messages {
+		death, ok := m.LastDeath()
+		if !ok {
+			continue
+		}
+
+		i := slices.IndexFunc(groups, func(g *deadLetterGroup) bool {
+			return g.death.Queue == death.Queue && g.death.Reason == death.Reason
+		})
+
+		if i < 0 {
+			groups = append(groups, &deadLetterGroup{death: death})
+			i = len(groups) - 1
+		}
+
+		groups[i].messages = append(groups[i].messages, m)
+	}
+
+	return groups
+}
+
+// DeadLetters lists dead-lettered messages grouped by the queue they were
+// dead-lettered from and the reason.
+type DeadLetters struct {
+	view.ResourceView[*deadLetterGroup]
+
+	parent *Messages
+}
+
+func newDeadLetters(parent *Messages) model.View {
+	v := DeadLetters{
+		ResourceView: view.NewResourceTableView[*deadLetterGroup]("Dead letters", view.NewManualUpdateStrategy()),
+		parent:       parent,
+	}
+
+	v.SetPath(parent.path)
+	v.SetResourceProvider(&v)
+	v.SetEnterAction("Show messages", v.showMessages)
+
+	return &v
+}
+
+func (v *DeadLetters) GetResources() ([]*deadLetterGroup, error) {
+	return groupDeadLetters(v.parent.messages), nil
+}
+
+func (v *DeadLetters) GetColumns() []ui.TableColumn {
+	return []ui.TableColumn{
+		{Name: "queue", Title: "QUEUE", Expansion: 1},
+		{Name: "reason", Title: "REASON"},
+		{Name: "exchange", Title: "EXCHANGE", Expansion: 1},
+		{Name: "routingKeys", Title: "ROUTING KEYS", Expansion: 2},
+		{Name: "count", Title: "COUNT", Align: tview.AlignRight},
+	}
+}
+
+func (v *DeadLetters) CanDeleteResources() bool {
+	return false
+}
+
+func (v *DeadLetters) DeleteResource(*deadLetterGroup) error {
+	return nil
+}
+
+func (v *DeadLetters) showMessages(group *deadLetterGroup) {
+	messages := newMessages(group.messages, v.parent.source, v.parent.path+" ⏵ "+group.GetName())
+	messages.vhost = v.parent.vhost
+	messages.queue = v.parent.queue
+	messages.withQueue = v.parent.withQueue
+	messages.onRequeued = v.parent.removeMessages
+
+	v.App().AddView(messages)
+}
+
+func (v *Messages) groupDeadLettersCmd(*tcell.EventKey) *tcell.EventKey {
+	v.App().AddView(newDeadLetters(v))
+
+	return nil
+}
+
+// requeueDeadLettersCmd publishes selected dead-lettered messages to the
+// exchange and routing key they were originally published to.
+func (v *Messages) requeueDeadLettersCmd(*tcell.EventKey) *tcell.EventKey {
+	messages := utils.Filter(v.GetSelectedResources(), (*MessageResource).isDeadLettered)
+	if len(messages) == 0 {
+		v.App().StatusLine().Error("No dead-lettered message selected")
+		return nil
+	}
+
+	ShowRequeueDeadLettersDialog(v.App(), messages, func(stripDeaths bool) {
+		v.App().DismissModal()
+		v.requeueDeadLetters(messages, stripDeaths)
+	})
+
+	return nil
+}
+
+func (v *Messages) requeueDeadLetters(messages []*MessageResource, stripDeaths bool) {
+	c := cluster.Current()
+	app := v.App()
+
+	app.StatusLine().Infof("Requeueing %d messages from %s...", len(messages), v.queue)
+
+	go func() {
+		conn, err := c.DialAMQP(v.vhost)
+		if err != nil {
+			app.StatusLine().Errorf("Failed to requeue messages: %s", err)
+			return
+		}
+		defer conn.Close()
+
+		// Messages were fetched from the head of the queue, so they should
+		// still be found among as many messages as were fetched.
+		window := 0
+		for _, m := range messages {
+			window = max(window, m.index+1)
+		}
+
+		opts := rmq.RequeueOptions{
+			Window:      window,
+			StripDeaths: stripDeaths,
+			ProgressFn: func(requeued int) {
+				app.StatusLine().Infof("Requeueing messages from %s (%d/%d)...", v.queue, requeued, len(messages))
+			},
+		}
+
+		fetched := utils.Map(messages, func(m *MessageResource) *rmq.FetchedMessage { return m.FetchedMessage })
+
+		requeued, err := rmq.RequeueDeadLetters(conn, v.queue, fetched, opts)
+
+		switch {
+		case err != nil:
+			slog.Error("Failed to requeue messages", sl.Error, err, sl.VirtualHost, v.vhost, sl.Resource, v.queue)
+			app.StatusLine().Errorf("Requeued %d of %d messages, then failed: %s", len(requeued), len(messages), err)
+		case len(requeued) < len(messages):
+			app.StatusLine().Errorf("Requeued %d of %d messages, others were not found in %s", len(requeued), len(messages), v.queue)
+		default:
+			app.StatusLine().Infof("Requeued %d messages to their original destinations", len(requeued))
+		}
+
+		if len(requeued) > 0 {
+			app.QueueUpdateDraw(func() {
+				v.removeMessages(requeued)
+			})
+		}
+	}()
+}
+
+// removeMessages removes requeued messages, which are not in the queue anymore.
+func (v *Messages) removeMessages(removed []*rmq.FetchedMessage) {
+	v.messages = slices.DeleteFunc(slices.Clone(v.messages), func(m *MessageResource) bool {
+		return slices.Contains(removed, m.FetchedMessage)
+	})
+
+	if v.onRequeued != nil {
+		v.onRequeued(removed)
+	}
+
+	v.RequestUpdate(view.FullUpdate)
+}
+
+// ShowRequeueDeadLettersDialog summarizes where messages are going to be
+// published and asks whether x-death headers should be removed.
+func ShowRequeueDeadLettersDialog(mm model.ModalManager, messages []*MessageResource, okFn func(stripDeaths bool)) {
+	f := ui.NewModalForm()
+
+	groups := groupDeadLetters(messages)
+
+	b := &strings.Builder{}
+	utils.Sbprintf(b, "Publish %d messages to their original exchange and routing key, and remove them from the queue once confirmed:\n", len(messages))
+
+	for _, g := range groups {
+		utils.Sbprintf(b, "\n%d from %s (%s) to exchange %s", len(g.messages), g.death.Queue, g.death.Reason, view.ExchangeDisplayName(g.death.Exchange))
+	}
+
+	height := min(len(groups), 5) + 3
+
+	f.AddTextView("", b.String(), 0, height, false, true)
+	f.AddCheckbox("Strip x-death headers:", true, nil)
+	f.AddButtons([]string{"Cancel", "Requeue"})
+
+	stripField := f.GetFormItem(1).(*tview.Checkbox)
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		okFn(stripField.IsChecked())
+	})
+
+	f.SetTitle("Requeue dead letters")
+
+	modal := ui.NewModalDialog(f, 90, height+7)
+	mm.ShowModal(modal)
+}
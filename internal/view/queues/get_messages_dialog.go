@@ -1,6 +1,7 @@
 package queues
 
 import (
+	"fmt"
 	"strconv"
 	"tbunny/internal/model"
 	"tbunny/internal/rmq"
@@ -9,9 +10,9 @@ import (
 	"github.com/rivo/tview"
 )
 
-type GetMessagesFn func(queue *QueueResource, ackMode rmq.AckMode, encoding rmq.RequestedMessageEncoding, count int)
+type GetMessagesFn func(queues []*QueueResource, ackMode rmq.AckMode, encoding rmq.RequestedMessageEncoding, count int)
 
-func ShowGetMessagesDialog(mm model.ModalManager, queue *QueueResource, okFn GetMessagesFn) {
+func ShowGetMessagesDialog(mm model.ModalManager, queues []*QueueResource, okFn GetMessagesFn) {
 	f := ui.NewModalForm()
 
 	f.AddDropDown("Ack Mode:", []string{"Nack message requeue true", "Automatic ack", "Reject requeue true", "Reject requeue false"}, 0, nil)
@@ -61,10 +62,14 @@ func ShowGetMessagesDialog(mm model.ModalManager, queue *QueueResource, okFn Get
 			return
 		}
 
-		okFn(queue, ackMode, encoding, count)
+		okFn(queues, ackMode, encoding, count)
 	})
 
-	f.SetTitle("Get messages")
+	if len(queues) > 1 {
+		f.SetTitle(fmt.Sprintf("Get messages from %d queues", len(queues)))
+	} else {
+		f.SetTitle("Get messages")
+	}
 
 	modal := ui.NewModalDialog(f, 80, 9)
 	mm.ShowModal(modal)
@@ -0,0 +1,214 @@
+package queues
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/ui/dialog"
+	"tbunny/internal/utils"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const jsonLinesFilePlaceholder = "Path to a JSON Lines file"
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExportMessages asks for a file name and saves messages into it as JSON
+// Lines, so that they can be published again with ImportMessages.
+func ExportMessages(app model.App, source string, messages []*MessageResource) {
+	if len(messages) == 0 {
+		return
+	}
+
+	title := fmt.Sprintf("Export %d messages", len(messages))
+	if len(messages) == 1 {
+		title = "Export message"
+	}
+
+	dialog.ShowFileDialog(app, title, "Export", defaultMessagesFileName(source), jsonLinesFilePlaceholder, func(path string) {
+		if err := writeMessagesFile(path, messages); err != nil {
+			slog.Error("Failed to export messages", sl.Error, err)
+			app.StatusLine().Errorf("Failed to export messages: %s", err)
+			return
+		}
+
+		app.StatusLine().Infof("%d messages exported to %s", len(messages), path)
+		app.DismissModal()
+	})
+}
+
+type ImportMessagesFn func(path, routingKey string)
+
+// ShowImportMessagesDialog asks for a file of exported messages and, if
+// withRoutingKey is set, for a routing key overriding the original ones.
+func ShowImportMessagesDialog(mm model.ModalManager, title string, withRoutingKey bool, okFn ImportMessagesFn) {
+	f := ui.NewModalForm()
+
+	f.AddInputField("File:", "", 60, nil, nil)
+	if withRoutingKey {
+		f.AddInputField("Routing key:", "", 60, nil, nil)
+	}
+
+	f.AddButtons([]string{"Cancel", "Publish"})
+
+	pathField := f.GetFormItem(0).(*tview.InputField)
+	pathField.SetPlaceholder(jsonLinesFilePlaceholder)
+
+	var routingKeyField *tview.InputField
+	if withRoutingKey {
+		routingKeyField = f.GetFormItem(1).(*tview.InputField)
+		routingKeyField.SetPlaceholder("Original routing key")
+	}
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		path := strings.TrimSpace(pathField.GetText())
+		if path == "" {
+			return
+		}
+
+		routingKey := ""
+		if routingKeyField != nil {
+			routingKey = routingKeyField.GetText()
+		}
+
+		okFn(path, routingKey)
+	})
+
+	f.SetTitle(title)
+
+	height := 7
+	if withRoutingKey {
+		height += 2
+	}
+
+	modal := ui.NewModalDialog(f, 80, height)
+	mm.ShowModal(modal)
+}
+
+// ImportMessages publishes messages saved by ExportMessages to the exchange,
+// in their original order, with their original routing keys unless routingKey
+// is set. Messages are published in the background; doneFn is called at the end.
+func ImportMessages(app model.App, c *cluster.Cluster, vhost, exchange, routingKey, path string, doneFn func()) {
+	messages, err := readMessagesFile(path)
+	if err != nil {
+		app.StatusLine().Errorf("Failed to read messages: %s", err)
+		return
+	}
+
+	app.DismissModal()
+
+	if len(messages) == 0 {
+		app.StatusLine().Infof("No messages found in %s", path)
+		return
+	}
+
+	if exchange == "" {
+		exchange = "amq.default"
+	}
+
+	go func() {
+		defer func() {
+			if doneFn != nil {
+				doneFn()
+			}
+		}()
+
+		unrouted := 0
+
+		for i, m := range messages {
+			app.StatusLine().Infof("Publishing messages from %s (%d/%d)...", path, i+1, len(messages))
+
+			routed, err := publishMessage(c, vhost, exchange, routingKey, m)
+			if err != nil {
+				slog.Error("Failed to publish message", sl.Error, err, sl.VirtualHost, vhost, sl.Resource, exchange)
+				app.StatusLine().Errorf("Failed to publish message %d of %d: %s", i+1, len(messages), err)
+				return
+			}
+
+			if !routed {
+				unrouted++
+			}
+		}
+
+		if unrouted > 0 {
+			app.StatusLine().Errorf("Published %d messages, %d of them not routed to any queue", len(messages), unrouted)
+		} else {
+			app.StatusLine().Infof("Published %d messages from %s", len(messages), path)
+		}
+	}()
+}
+
+func publishMessage(c *cluster.Cluster, vhost, exchange, routingKey string, m *rmq.FetchedMessage) (bool, error) {
+	opts, err := m.PublishOptions(routingKey)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.PublishToExchange(vhost, exchange, opts)
+	if err != nil {
+		return false, err
+	}
+
+	return res.Routed, nil
+}
+
+func writeMessagesFile(path string, messages []*MessageResource) error {
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+
+	err = rmq.WriteMessages(w, utils.Map(messages, func(m *MessageResource) *rmq.FetchedMessage { return m.FetchedMessage }))
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func readMessagesFile(path string) ([]*rmq.FetchedMessage, error) {
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return rmq.ReadMessages(bufio.NewReader(file))
+}
+
+func defaultMessagesFileName(source string) string {
+	name := unsafeFileNameChars.ReplaceAllString(source, "_")
+
+	return fmt.Sprintf("%s-messages-%s.jsonl", name, time.Now().Format("20060102-150405"))
+}
@@ -0,0 +1,132 @@
+package queues
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"tbunny/internal/decoders"
+	"tbunny/internal/jsonpath"
+	"tbunny/internal/ui"
+	"tbunny/internal/view"
+
+	"github.com/rivo/tview"
+)
+
+// messageFilterRegexPrefix starts filters which are regular expressions.
+const messageFilterRegexPrefix = "~"
+
+// highlightRegion is the text view region of highlighted matches.
+const highlightRegion = "match"
+
+// messageFilter matches messages on their columns, payload, headers and
+// properties. A filter is either:
+//   - text, matched like other filters, e.g. order-42, or =ord*42 with
+//     wildcards matching whole values;
+//   - a regular expression after ~, e.g. ~customer[-_]id;
+//   - an expression on JSON payloads, e.g. $.order.status == "FAILED".
+type messageFilter struct {
+	// re finds matches in values, it is nil for expressions without a value to highlight.
+	re   *regexp.Regexp
+	expr *jsonpath.Expression
+}
+
+func parseMessageFilter(filter string) (*messageFilter, error) {
+	if jsonpath.IsExpression(filter) {
+		expr, err := jsonpath.Parse(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return &messageFilter{re: expressionRegexp(expr), expr: expr}, nil
+	}
+
+	if pattern, ok := strings.CutPrefix(filter, messageFilterRegexPrefix); ok {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return &messageFilter{re: re}, nil
+	}
+
+	return &messageFilter{re: view.FilterRegexp(filter)}, nil
+}
+
+// expressionRegexp returns a regular expression highlighting the values an
+// expression looks for, when it looks for strings.
+func expressionRegexp(expr *jsonpath.Expression) *regexp.Regexp {
+	s, ok := expr.Literal().(string)
+	if !ok || s == "" {
+		return nil
+	}
+
+	switch expr.Operator() {
+	case jsonpath.OpEqual:
+		return regexp.MustCompile(regexp.QuoteMeta(s))
+	case jsonpath.OpMatch:
+		re, _ := regexp.Compile(s)
+		return re
+	}
+
+	return nil
+}
+
+// matcher returns a matcher of messages displayed with the given columns.
+func (f *messageFilter) matcher(columns []ui.TableColumn) func(*MessageResource) bool {
+	if f.expr != nil {
+		return func(m *MessageResource) bool {
+			p := m.decodedPayload()
+			return p.Format == decoders.FormatJSON && f.expr.MatchJSON(p.Data)
+		}
+	}
+
+	return func(m *MessageResource) bool {
+		for _, column := range columns {
+			if value := m.GetTableColumnValue(column.Name); value != "" && f.re.MatchString(value) {
+				return true
+			}
+		}
+
+		if f.re.Match(m.decodedPayload().Data) {
+			return true
+		}
+
+		for _, value := range m.propertyValues() {
+			if f.re.MatchString(value) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// highlight escapes text for a text view with dynamic colors and regions,
+// surrounding matches of the filter with highlighted regions.
+func (f *messageFilter) highlight(s string) string {
+	if f == nil || f.re == nil {
+		return tview.Escape(s)
+	}
+
+	matches := f.re.FindAllStringIndex(s, -1)
+	if len(matches) == 0 {
+		return tview.Escape(s)
+	}
+
+	b := strings.Builder{}
+	last := 0
+
+	for _, m := range matches {
+		if m[0] == m[1] {
+			continue
+		}
+
+		b.WriteString(tview.Escape(s[last:m[0]]))
+		fmt.Fprintf(&b, `["%s"]%s[""]`, highlightRegion, tview.Escape(s[m[0]:m[1]]))
+		last = m[1]
+	}
+
+	b.WriteString(tview.Escape(s[last:]))
+
+	return b.String()
+}
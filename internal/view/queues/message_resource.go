@@ -1,7 +1,9 @@
 package queues
 
 import (
+	"fmt"
 	"strconv"
+	"tbunny/internal/decoders"
 	"tbunny/internal/rmq"
 	"tbunny/internal/view"
 )
@@ -10,6 +12,15 @@ type MessageResource struct {
 	*rmq.FetchedMessage
 
 	index int
+	// queue is the name of the queue the message was fetched from, only set
+	// when messages of several queues are displayed together.
+	queue string
+	// decoded is the payload decoded by automatically selected decoders, see decodedPayload.
+	decoded *decoders.Payload
+}
+
+func NewMessageResource(message *rmq.FetchedMessage, index int) *MessageResource {
+	return &MessageResource{FetchedMessage: message, index: index}
 }
 
 func (r *MessageResource) GetName() string {
@@ -36,9 +47,99 @@ func (r *MessageResource) GetTableColumnValue(columnName string) string {
 		return view.ExchangeDisplayName(r.Exchange)
 	case "length":
 		return view.FormatBytes(r.PayloadBytes)
+	case "queue":
+		return r.queue
+	case "deathReason":
+		death, _ := r.LastDeath()
+		return death.Reason
+	case "deathQueue":
+		death, _ := r.LastDeath()
+		return death.Queue
 	case "routingKey":
 		return r.RoutingKey
 	}
 
 	return ""
 }
+
+func (r *MessageResource) isDeadLettered() bool {
+	_, ok := r.LastDeath()
+
+	return ok
+}
+
+func (r *MessageResource) GetTableColumnSortValue(columnName string) (float64, bool) {
+	if columnName == "length" {
+		return float64(r.PayloadBytes), true
+	}
+
+	return 0, false
+}
+
+// decodePayload decodes the payload with the named decoder, see decoders.Names.
+func (r *MessageResource) decodePayload(decoder string) *decoders.Payload {
+	props := r.Properties
+	hints := decoders.Hints{ContentType: props.ContentType, ContentEncoding: props.ContentEncoding, Type: props.Type}
+
+	data, err := r.DecodedPayload()
+	if err != nil {
+		// Not valid base64, use the payload as it was received.
+		data = []byte(r.Payload)
+	}
+
+	return decoders.Decode(data, hints, decoder)
+}
+
+// decodedPayload returns the payload decoded by automatically selected
+// decoders, decoding it once.
+func (r *MessageResource) decodedPayload() *decoders.Payload {
+	if r.decoded == nil {
+		r.decoded = r.decodePayload(decoders.Auto)
+	}
+
+	return r.decoded
+}
+
+// propertyValues returns the values of the message properties and headers as text.
+func (r *MessageResource) propertyValues() []string {
+	props := r.Properties
+
+	values := []string{
+		props.AppId,
+		props.ContentEncoding,
+		props.CorrelationId,
+		props.Expiration,
+		props.MessageId,
+		props.ReplyTo,
+		props.Type,
+		props.UserId,
+	}
+
+	for _, v := range props.Headers {
+		values = appendHeaderValues(values, v)
+	}
+
+	return values
+}
+
+// appendHeaderValues appends header values, including values of nested
+// tables and arrays such as x-death.
+func appendHeaderValues(values []string, v any) []string {
+	switch typed := v.(type) {
+	case map[string]any:
+		for _, nested := range typed {
+			values = appendHeaderValues(values, nested)
+		}
+	case []any:
+		for _, nested := range typed {
+			values = appendHeaderValues(values, nested)
+		}
+	case string:
+		values = append(values, typed)
+	case nil:
+	default:
+		values = append(values, fmt.Sprint(typed))
+	}
+
+	return values
+}
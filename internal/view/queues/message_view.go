@@ -2,7 +2,9 @@ package queues
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"tbunny/internal/decoders"
 	"tbunny/internal/rmq"
 	"tbunny/internal/skins"
 	"tbunny/internal/ui"
@@ -16,7 +18,7 @@ import (
 	"github.com/rivo/tview"
 )
 
-const MessageViewTitleFmt = " [fg:bg:b]%s [count:bg:b]%d[fg:bg:-][fg:bg:-] "
+const MessageViewTitleFmt = " [fg:bg:b]%s [count:bg:b]%d[fg:bg:-]([hilite:bg:b]%s[fg:bg:-]) "
 
 type MessageView struct {
 	*view.View[*tview.TextView]
@@ -25,6 +27,11 @@ type MessageView struct {
 	skin           *skins.Skin
 	showProperties bool
 	wrap           bool
+	// decoder is the selected payload decoder, see decoders.Names.
+	decoder string
+	payload *decoders.Payload
+	// filter is the filter of the messages view, its matches are highlighted.
+	filter *messageFilter
 }
 
 func NewMessageView(message *MessageResource) *MessageView {
@@ -33,6 +40,7 @@ func NewMessageView(message *MessageResource) *MessageView {
 	v := MessageView{
 		View:    view.NewView[*tview.TextView]("Message", tv),
 		message: message,
+		decoder: decoders.Auto,
 	}
 
 	tv.SetScrollable(true).SetDynamicColors(true)
@@ -46,6 +54,17 @@ func NewMessageView(message *MessageResource) *MessageView {
 	return &v
 }
 
+// setFilter highlights the matches of a messages filter.
+func (v *MessageView) setFilter(filter string) {
+	if filter == "" {
+		return
+	}
+
+	if f, err := parseMessageFilter(filter); err == nil {
+		v.filter = f
+	}
+}
+
 func (v *MessageView) Start() {
 	v.View.Start()
 
@@ -67,7 +86,7 @@ func (v *MessageView) SkinChanged(skin *skins.Skin) {
 }
 
 func (v *MessageView) updateTitle() {
-	title := view.SkinTitle(fmt.Sprintf(MessageViewTitleFmt, v.Name(), v.message.index))
+	title := view.SkinTitle(fmt.Sprintf(MessageViewTitleFmt, v.Name(), v.message.index, v.decodePayload().Description()))
 
 	v.Ui().SetTitle(title)
 }
@@ -98,6 +117,10 @@ func (v *MessageView) updateText() {
 	b.WriteString(v.formatPayload())
 
 	v.Ui().SetText(b.String())
+
+	if v.filter != nil {
+		v.Ui().Highlight(highlightRegion).ScrollToHighlight()
+	}
 }
 
 func (v *MessageView) formatProperties() string {
@@ -188,7 +211,7 @@ func (v *MessageView) formatKvpValue(value any, b *strings.Builder) {
 		if strings.Contains(typed, "\n") {
 			b.WriteString("\n")
 		}
-		utils.Sbprintf(b, "[%s]%s[-]", v.skin.Views.Stats.ValueFgColor.String(), tview.Escape(typed))
+		utils.Sbprintf(b, "[%s]%s[-]", v.skin.Views.Stats.ValueFgColor.String(), v.filter.highlight(typed))
 	case int, int8, int16, int32, int64:
 		utils.Sbprintf(b, "[%s]%d[-]", v.skin.Views.Stats.ValueFgColor.String(), typed)
 	case float32, float64:
@@ -217,24 +240,52 @@ func (v *MessageView) formatKvpList(list []any, b *strings.Builder) {
 }
 
 func (v *MessageView) formatPayload() string {
-	if v.message.PayloadEncoding == rmq.PayloadEncodingBase64 {
-		return v.message.Payload
+	p := v.decodePayload()
+
+	if p.Format == decoders.FormatJSON {
+		if payload, err := v.formatJsonPayload(p.Data); err == nil {
+			return payload
+		}
 	}
 
-	if payload, err := v.formatJsonPayload(); err == nil {
-		return payload
+	// Hex dumps are not highlighted, matches could span lines.
+	if p.Format == decoders.FormatHexDump {
+		return tview.Escape(string(p.Data))
 	}
 
-	return v.message.Payload
+	return v.filter.highlight(string(p.Data))
 }
 
-func (v *MessageView) formatJsonPayload() (string, error) {
-	d := jx.DecodeStr(v.message.Payload)
-	f := view.NewJxFormatter(v.skin)
+func (v *MessageView) formatJsonPayload(data []byte) (string, error) {
+	d := jx.DecodeBytes(data)
+	f := view.NewJxFormatter(v.skin).SetTextFn(v.filter.highlight)
 
 	return f.Format(d)
 }
 
+// decodePayload decodes the payload with the selected decoder, once.
+func (v *MessageView) decodePayload() *decoders.Payload {
+	if v.payload != nil {
+		return v.payload
+	}
+
+	v.payload = v.message.decodePayload(v.decoder)
+
+	if v.payload.Err != nil {
+		v.App().StatusLine().Errorf("Failed to decode payload: %s", v.payload.Err)
+	}
+
+	return v.payload
+}
+
+func (v *MessageView) setDecoder(decoder string) {
+	v.decoder = decoder
+	v.payload = nil
+
+	v.updateTitle()
+	v.updateText()
+}
+
 func (v *MessageView) bindKeys(km ui.KeyMap) {
 	km.Add(ui.KeyP, ui.NewKeyAction("Toggle headers", v.togglePropertiesCmd))
 
@@ -243,6 +294,8 @@ func (v *MessageView) bindKeys(km ui.KeyMap) {
 	}
 
 	km.Add(ui.KeyW, ui.NewKeyAction("Toggle wrap", v.toggleWrapCmd))
+	km.Add(ui.KeyD, ui.NewKeyAction("Cycle decoders", v.cycleDecoderCmd))
+	km.Add(ui.KeyH, ui.NewKeyAction("Toggle hex dump", v.toggleHexDumpCmd))
 }
 
 func (v *MessageView) togglePropertiesCmd(*tcell.EventKey) *tcell.EventKey {
@@ -253,7 +306,12 @@ func (v *MessageView) togglePropertiesCmd(*tcell.EventKey) *tcell.EventKey {
 }
 
 func (v *MessageView) copyPayloadCmd(*tcell.EventKey) *tcell.EventKey {
-	_ = clipboard.WriteAll(v.message.Payload)
+	payload := v.message.Payload
+	if p := v.decodePayload(); p.Format != decoders.FormatHexDump {
+		payload = string(p.Data)
+	}
+
+	_ = clipboard.WriteAll(payload)
 
 	return nil
 }
@@ -264,3 +322,23 @@ func (v *MessageView) toggleWrapCmd(*tcell.EventKey) *tcell.EventKey {
 
 	return nil
 }
+
+func (v *MessageView) cycleDecoderCmd(*tcell.EventKey) *tcell.EventKey {
+	i := slices.Index(decoders.Names, v.decoder)
+	decoder := decoders.Names[(i+1)%len(decoders.Names)]
+
+	v.setDecoder(decoder)
+	v.App().StatusLine().Infof("Payload decoder: %s", decoder)
+
+	return nil
+}
+
+func (v *MessageView) toggleHexDumpCmd(*tcell.EventKey) *tcell.EventKey {
+	if v.decoder == decoders.Hex {
+		v.setDecoder(decoders.Auto)
+	} else {
+		v.setDecoder(decoders.Hex)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package queues
 
 import (
+	"fmt"
+	"slices"
 	"tbunny/internal/model"
 	"tbunny/internal/rmq"
 	"tbunny/internal/ui"
@@ -15,17 +17,54 @@ type Messages struct {
 	view.ResourceView[*MessageResource]
 
 	messages []*MessageResource
+	// source names where messages come from, e.g. for export file names.
+	source string
+	// vhost and queue are set when messages come from a single queue.
+	vhost string
+	queue string
+	// withQueue shows the queue of each message, when messages come from several queues.
+	withQueue bool
+	path      string
+	// onRequeued is called when messages are requeued from a subset view.
+	onRequeued func(requeued []*rmq.FetchedMessage)
 }
 
 func NewMessages(messages []*rmq.FetchedMessage, queue, vhost string) model.View {
+	resources := utils.MapWithIndex(messages, func(idx int, fm *rmq.FetchedMessage) *MessageResource {
+		return NewMessageResource(fm, idx)
+	})
+
+	v := newMessages(resources, queue, view.VhostDisplayName(vhost)+" ⏵ "+queue)
+	v.vhost = vhost
+	v.queue = queue
+
+	return v
+}
+
+// newQueuesMessages creates a view of messages fetched from several queues.
+func newQueuesMessages(messages []*MessageResource, queues []*QueueResource) model.View {
+	path := fmt.Sprintf("%d queues", len(queues))
+
+	vhost := queues[0].Vhost
+	if !slices.ContainsFunc(queues, func(q *QueueResource) bool { return q.Vhost != vhost }) {
+		path = view.VhostDisplayName(vhost) + " ⏵ " + path
+	}
+
+	v := newMessages(messages, "queues", path)
+	v.withQueue = true
+
+	return v
+}
+
+func newMessages(messages []*MessageResource, source, path string) *Messages {
 	v := Messages{
 		ResourceView: view.NewResourceTableView[*MessageResource]("Messages", view.NewManualUpdateStrategy()),
-		messages: utils.MapWithIndex(messages, func(idx int, fm *rmq.FetchedMessage) *MessageResource {
-			return &MessageResource{fm, idx}
-		}),
+		messages:     messages,
+		source:       source,
+		path:         path,
 	}
 
-	v.SetPath(view.VhostDisplayName(vhost) + " ⏵ " + queue)
+	v.SetPath(path)
 	v.SetResourceProvider(&v)
 	v.AddBindingKeysFn(v.bindKeys)
 
@@ -37,18 +76,53 @@ func (v *Messages) GetResources() ([]*MessageResource, error) {
 }
 
 func (v *Messages) GetColumns() []ui.TableColumn {
+	c := messageColumns(v.withQueue)
+
+	if slices.ContainsFunc(v.messages, (*MessageResource).isDeadLettered) {
+		c = append(c, []ui.TableColumn{
+			{Name: "deathReason", Title: "REASON"},
+			{Name: "deathQueue", Title: "DEAD-LETTERED FROM", Expansion: 1},
+		}...)
+	}
+
+	return c
+}
+
+func messageColumns(withQueue bool) []ui.TableColumn {
 	c := []ui.TableColumn{
 		{Name: "index", Title: "IDX"},
+	}
+
+	if withQueue {
+		c = append(c, ui.TableColumn{Name: "queue", Title: "QUEUE", Expansion: 1})
+	}
+
+	c = append(c, []ui.TableColumn{
 		{Name: "exchange", Title: "EXCHANGE", Expansion: 1},
 		{Name: "routingKey", Title: "ROUTING KEY", Expansion: 1},
 		{Name: "deliveryMode", Title: "MODE"},
 		{Name: "contentType", Title: "CONTENT TYPE"},
 		{Name: "length", Title: "LENGTH", Align: tview.AlignRight},
-	}
+	}...)
 
 	return c
 }
 
+// ResourceMatcher matches messages on their payload, headers and properties
+// as well as their columns, see messageFilter.
+func (v *Messages) ResourceMatcher(filter string) (func(*MessageResource) bool, error) {
+	return messagesMatcher(filter, v.GetColumns())
+}
+
+func messagesMatcher(filter string, columns []ui.TableColumn) (func(*MessageResource) bool, error) {
+	f, err := parseMessageFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.matcher(columns), nil
+}
+
 func (v *Messages) CanDeleteResources() bool {
 	return false
 }
@@ -59,6 +133,27 @@ func (v *Messages) DeleteResource(*MessageResource) error {
 
 func (v *Messages) bindKeys(km ui.KeyMap) {
 	km.Add(tcell.KeyEnter, ui.NewKeyActionWithGroup("View message", v.showMessageCmd, false, 0))
+	km.Add(ui.KeyX, ui.NewKeyAction("Export", v.exportCmd))
+
+	if slices.ContainsFunc(v.messages, (*MessageResource).isDeadLettered) {
+		km.Add(ui.KeyG, ui.NewKeyAction("Group dead letters", v.groupDeadLettersCmd))
+
+		if v.queue != "" {
+			km.Add(ui.KeyR, ui.NewKeyAction("Requeue dead letters", v.requeueDeadLettersCmd))
+		}
+	}
+}
+
+// exportCmd exports marked messages or, if none is marked, all messages.
+func (v *Messages) exportCmd(*tcell.EventKey) *tcell.EventKey {
+	messages := v.GetMarkedResources()
+	if len(messages) == 0 {
+		messages = v.messages
+	}
+
+	ExportMessages(v.App(), v.source, messages)
+
+	return nil
 }
 
 func (v *Messages) showMessageCmd(*tcell.EventKey) *tcell.EventKey {
@@ -68,6 +163,7 @@ func (v *Messages) showMessageCmd(*tcell.EventKey) *tcell.EventKey {
 	}
 
 	messageView := NewMessageView(row)
+	messageView.setFilter(v.GetFilter())
 
 	v.App().AddView(messageView)
 
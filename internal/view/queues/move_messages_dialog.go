@@ -2,6 +2,7 @@ package queues
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"tbunny/internal/model"
 	"tbunny/internal/ui"
@@ -9,9 +10,9 @@ import (
 	"github.com/rivo/tview"
 )
 
-type MoveMessagesFn func(vhost, sourceQueue, destinationQueue string)
+type MoveMessagesFn func(sourceQueues []*QueueResource, destinationQueue string)
 
-func ShowMoveMessagesDialog(mm model.ModalManager, vhost, sourceQueue string, destinationQueues []string, okFn MoveMessagesFn) {
+func ShowMoveMessagesDialog(mm model.ModalManager, sourceQueues []*QueueResource, destinationQueues []string, okFn MoveMessagesFn) {
 	f := ui.NewModalForm()
 
 	f.AddInputField("Destination queue:", "", 30, nil, nil)
@@ -43,10 +44,19 @@ func ShowMoveMessagesDialog(mm model.ModalManager, vhost, sourceQueue string, de
 			return
 		}
 
-		okFn(vhost, sourceQueue, destinationQueue)
+		if slices.ContainsFunc(sourceQueues, func(q *QueueResource) bool { return q.Name == destinationQueue }) {
+			f.SetFocus(0)
+			return
+		}
+
+		okFn(sourceQueues, destinationQueue)
 	})
 
-	f.SetTitle(fmt.Sprintf("Move messages from %s", sourceQueue))
+	if len(sourceQueues) > 1 {
+		f.SetTitle(fmt.Sprintf("Move messages from %d queues", len(sourceQueues)))
+	} else {
+		f.SetTitle(fmt.Sprintf("Move messages from %s", sourceQueues[0].Name))
+	}
 
 	modal := ui.NewModalDialog(f, 60, 7)
 	mm.ShowModal(modal)
@@ -0,0 +1,105 @@
+package queues
+
+import (
+	"errors"
+	"log/slog"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/publish"
+	"tbunny/internal/rmq"
+	"tbunny/internal/sl"
+	"tbunny/internal/view"
+	"time"
+)
+
+// Publish expands the placeholders of a message, publishes it to its
+// exchange in the background, records it in the publish history of the
+// cluster and reports whether it was routed to a queue. doneFn, if any, is
+// then called on the UI goroutine with false if the message was not
+// published or not routed. The history keeps the placeholders, replaced
+// again when the message is published from it.
+func Publish(app model.App, c *cluster.Cluster, msg *publish.Message, doneFn func(routed bool)) {
+	expanded := msg.Expand()
+	name := view.ExchangeDisplayName(expanded.Exchange)
+
+	app.StatusLine().Infof("Publishing message to %s...", name)
+
+	go func() {
+		routed := publishAndRecord(app, c, msg, expanded, name)
+
+		if doneFn != nil {
+			app.QueueUpdateDraw(func() {
+				doneFn(routed)
+			})
+		}
+	}()
+}
+
+// publishAndRecord publishes the expanded message, records the message in the
+// history and reports the result in the status line.
+func publishAndRecord(app model.App, c *cluster.Cluster, msg, expanded *publish.Message, name string) bool {
+	var routed bool
+	var err error
+
+	// The management API doesn't support the mandatory flag, such messages
+	// are published over AMQP to get them back when unroutable.
+	if expanded.Mandatory {
+		routed, err = publishMandatory(c, expanded)
+	} else {
+		routed, err = publishWithAPI(c, expanded)
+	}
+
+	var unroutable *rmq.UnroutableError
+	if errors.As(err, &unroutable) {
+		err = nil
+	}
+
+	if err != nil {
+		app.StatusLine().Errorf("Failed to publish message: %s", err)
+		return false
+	}
+
+	entry := publish.HistoryEntry{Time: time.Now(), Routed: routed, Message: *msg}
+	if err = publish.AddToHistory(c.Name(), &entry); err != nil {
+		slog.Error("Failed to save publish history", sl.Error, err, sl.Cluster, c.Name())
+	}
+
+	switch {
+	case unroutable != nil:
+		app.StatusLine().Errorf("Message returned by %s: %s", name, unroutable.Reason)
+	case !routed:
+		app.StatusLine().Errorf("Message published to %s was not routed to any queue", name)
+	default:
+		app.StatusLine().Infof("Message published to %s and routed", name)
+	}
+
+	return routed
+}
+
+func publishWithAPI(c *cluster.Cluster, msg *publish.Message) (bool, error) {
+	exchange := msg.Exchange
+	if exchange == "" {
+		exchange = "amq.default"
+	}
+
+	res, err := c.PublishToExchange(msg.Vhost, exchange, msg.PublishOptions())
+	if err != nil {
+		return false, err
+	}
+
+	return res.Routed, nil
+}
+
+func publishMandatory(c *cluster.Cluster, msg *publish.Message) (bool, error) {
+	conn, err := c.DialAMQP(msg.Vhost)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if err = rmq.PublishMandatory(conn, msg.Exchange, msg.PublishOptions()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -1,69 +1,297 @@
 package queues
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"tbunny/internal/model"
+	"tbunny/internal/publish"
 	"tbunny/internal/rmq"
 	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-type PublishMessageFn func(vhost, queue string, props map[string]any, payload string, payloadEncoding rmq.PayloadEncoding)
+// PublishMessageFn is called with the message to publish and how many copies
+// of it to publish.
+type PublishMessageFn func(msg *publish.Message, repeat publish.Repeat)
 
-func ShowPublishMessageDialog(mm model.ModalManager, vhost, queue string, okFn PublishMessageFn) {
+// PublishMessageDialogOptions customize the publish dialog.
+type PublishMessageDialogOptions struct {
+	Title string
+	// Vhost and Exchange are where the message is published. They are saved
+	// with templates, and replaced by those of the loaded templates.
+	Vhost    string
+	Exchange string
+	// RoutingKey is the routing key without routing key field, e.g. a queue name.
+	RoutingKey string
+	// WithRoutingKey adds routing key and mandatory fields, for publishing to exchanges.
+	WithRoutingKey bool
+	// RoutingKeys are suggested in the routing key field.
+	RoutingKeys []string
+	// Message prefills the dialog, e.g. with a message of the history.
+	Message *publish.Message
+}
+
+func ShowPublishMessageDialog(app model.App, opts PublishMessageDialogOptions, okFn PublishMessageFn) {
 	f := ui.NewModalForm()
 
-	f.AddDropDown("Delivery mode:", []string{rmq.MessageDeliveryModeNonPersistent.String(), rmq.MessageDeliveryModePersistent.String()}, 0, nil)
+	f.AddInputField("Template:", "", 59, nil, nil)
+
+	// Exchange fields come after the template and shift the other fields.
+	offset := 1
+
+	if opts.WithRoutingKey {
+		f.AddInputField("Routing key:", "", 59, nil, nil)
+		f.AddCheckbox("Mandatory:", false, nil)
+		offset += 2
+	}
+
+	deliveryModes := []string{rmq.MessageDeliveryModeNonPersistent.String(), rmq.MessageDeliveryModePersistent.String()}
+	payloadEncodings := []string{string(rmq.PayloadEncodingString), string(rmq.PayloadEncodingBase64)}
+
+	f.AddDropDown("Delivery mode:", deliveryModes, 0, nil)
 	headersField := ui.NewArguments().SetLabel("Headers:").SetKeyPlaceholder("Header name").SetValuePlaceholder("Header value")
 	f.AddFormItem(headersField)
 	propertiesField := ui.NewProperties().SetLabel("Properties:")
 	f.AddFormItem(propertiesField)
 	f.AddTextArea("Payload:", "", 59, 8, 0, nil)
-	f.AddDropDown("Payload encoding:", []string{string(rmq.PayloadEncodingString), string(rmq.PayloadEncodingBase64)}, 0, nil)
+	f.AddDropDown("Payload encoding:", payloadEncodings, 0, nil)
+	f.AddInputField("Repeat:", "1", 10, tview.InputFieldInteger, nil)
+	f.AddInputField("Rate (msg/s):", "", 10, tview.InputFieldFloat, nil)
+	f.AddInputField("Duration:", "", 10, nil, nil)
 
-	f.AddButtons([]string{"Cancel", "Publish"})
+	f.AddButtons([]string{"Cancel", "Publish", "Save template"})
 
-	deliveryModeField := f.GetFormItem(0).(*tview.DropDown)
-	payloadField := f.GetFormItem(3).(*tview.TextArea)
-	payloadEncodingField := f.GetFormItem(4).(*tview.DropDown)
+	templateField := f.GetFormItem(0).(*tview.InputField)
+	deliveryModeField := f.GetFormItem(offset).(*tview.DropDown)
+	payloadField := f.GetFormItem(offset + 3).(*tview.TextArea)
+	payloadEncodingField := f.GetFormItem(offset + 4).(*tview.DropDown)
+	countField := f.GetFormItem(offset + 5).(*tview.InputField)
+	rateField := f.GetFormItem(offset + 6).(*tview.InputField)
+	durationField := f.GetFormItem(offset + 7).(*tview.InputField)
 
-	payloadField.SetPlaceholder("Message payload")
+	payloadField.SetPlaceholder("Message payload, may contain " + strings.Join(publish.Placeholders, ", "))
+	rateField.SetPlaceholder("Unlimited")
+	durationField.SetPlaceholder("e.g. 30s")
+
+	var routingKeyField *tview.InputField
+	var mandatoryField *tview.Checkbox
+
+	if opts.WithRoutingKey {
+		routingKeyField = f.GetFormItem(1).(*tview.InputField)
+		routingKeyField.SetPlaceholder("Routing key")
+		routingKeyField.SetAutocompleteFunc(func(text string) (items []string) {
+			for _, key := range opts.RoutingKeys {
+				if strings.HasPrefix(key, text) {
+					items = append(items, key)
+				}
+			}
 
-	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-		if buttonIndex != 1 {
-			mm.DismissModal()
 			return
-		}
+		})
+
+		mandatoryField = f.GetFormItem(2).(*tview.Checkbox)
+	}
 
+	// target is where the message is published, as in opts or in the
+	// loaded template.
+	target := publish.Message{Vhost: opts.Vhost, Exchange: opts.Exchange, RoutingKey: opts.RoutingKey}
+
+	getMessage := func() (*publish.Message, bool) {
 		_, deliveryModeText := deliveryModeField.GetCurrentOption()
 		_, payloadEncoding := payloadEncodingField.GetCurrentOption()
 
 		deliveryMode, err := rmq.ParseDeliveryMode(deliveryModeText)
 		if err != nil {
-			f.SetFocus(0)
-			return
+			f.SetFocus(offset)
+			return nil, false
 		}
 
-		headers := headersField.GetValue()
 		props := propertiesField.GetValue()
 		props["delivery_mode"] = deliveryMode
 
-		if len(headers) > 0 {
-			props["headers"] = headers
+		msg := publish.Message{
+			Vhost:           target.Vhost,
+			Exchange:        target.Exchange,
+			RoutingKey:      target.RoutingKey,
+			Headers:         headersField.GetValue(),
+			Properties:      props,
+			Payload:         payloadField.GetText(),
+			PayloadEncoding: payloadEncoding,
+		}
+
+		if opts.WithRoutingKey {
+			msg.RoutingKey = routingKeyField.GetText()
+			msg.Mandatory = mandatoryField.IsChecked()
+		}
+
+		return &msg, true
+	}
+
+	// getRepeat returns how many copies to publish: a count, a duration, or
+	// both, whichever is reached first, optionally at a target rate.
+	getRepeat := func() (publish.Repeat, bool) {
+		var repeat publish.Repeat
+		var err error
+
+		if text := strings.TrimSpace(countField.GetText()); text != "" {
+			if repeat.Count, err = strconv.Atoi(text); err != nil || repeat.Count < 0 {
+				f.SetFocus(offset + 5)
+				return repeat, false
+			}
+		}
+
+		if text := strings.TrimSpace(rateField.GetText()); text != "" {
+			if repeat.Rate, err = strconv.ParseFloat(text, 64); err != nil || repeat.Rate < 0 {
+				f.SetFocus(offset + 6)
+				return repeat, false
+			}
+		}
+
+		if text := strings.TrimSpace(durationField.GetText()); text != "" {
+			if repeat.Duration, err = time.ParseDuration(text); err != nil || repeat.Duration < 0 {
+				f.SetFocus(offset + 7)
+				return repeat, false
+			}
+		}
+
+		if repeat.Count == 0 && repeat.Duration == 0 {
+			repeat.Count = 1
+		}
+
+		return repeat, true
+	}
+
+	setMessage := func(msg *publish.Message) {
+		// Templates saved before they had a target keep the current one.
+		if msg.Vhost != "" && (msg.Vhost != target.Vhost || msg.Exchange != target.Exchange || msg.RoutingKey != target.RoutingKey) {
+			target = publish.Message{Vhost: msg.Vhost, Exchange: msg.Exchange, RoutingKey: msg.RoutingKey}
+			f.SetTitle(publishTitle(&target, opts.WithRoutingKey))
+		}
+
+		if opts.WithRoutingKey {
+			routingKeyField.SetText(msg.RoutingKey)
+			mandatoryField.SetChecked(msg.Mandatory)
+		}
+
+		deliveryModeField.SetCurrentOption(0)
+		if propertiesDeliveryMode(msg.Properties) == rmq.MessageDeliveryModePersistent {
+			deliveryModeField.SetCurrentOption(1)
+		}
+
+		headersField.SetValue(msg.Headers)
+		propertiesField.SetValue(msg.Properties)
+		payloadField.SetText(msg.Payload, false)
+
+		payloadEncodingField.SetCurrentOption(0)
+		if msg.PayloadEncoding == string(rmq.PayloadEncodingBase64) {
+			payloadEncodingField.SetCurrentOption(1)
+		}
+	}
+
+	templates, err := publish.Templates()
+	if err != nil {
+		app.StatusLine().Errorf("Failed to load templates: %s", err)
+	}
+
+	templateNames := utils.Map(templates, func(t *publish.Template) string { return t.Name })
+
+	loadTemplate := func(name string) {
+		for _, t := range templates {
+			if t.Name == name {
+				setMessage(&t.Message)
+				app.StatusLine().Infof("Template %s loaded", name)
+				return
+			}
 		}
+	}
 
-		okFn(vhost, queue, props, payloadField.GetText(), rmq.PayloadEncoding(payloadEncoding))
+	templateField.SetPlaceholder("Template to load or name to save")
+	templateField.SetAutocompleteFunc(func(text string) (items []string) {
+		for _, name := range templateNames {
+			if strings.HasPrefix(name, text) {
+				items = append(items, name)
+			}
+		}
+
+		return
 	})
+	templateField.SetAutocompletedFunc(func(text string, index, source int) bool {
+		templateField.SetText(text)
 
-	f.SetTitle("Publish message")
+		if source != tview.AutocompletedNavigate {
+			loadTemplate(text)
+		}
 
-	const modalHeight = 17
+		return source != tview.AutocompletedNavigate
+	})
+	templateField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			loadTemplate(templateField.GetText())
+		}
+	})
+
+	f.SetTitle(opts.Title)
+
+	if opts.Message != nil {
+		setMessage(opts.Message)
+	}
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		switch buttonIndex {
+		case 1:
+			msg, ok := getMessage()
+			if !ok {
+				return
+			}
+
+			if repeat, ok := getRepeat(); ok {
+				okFn(msg, repeat)
+			}
+		case 2:
+			msg, ok := getMessage()
+			if !ok {
+				return
+			}
+
+			name := strings.TrimSpace(templateField.GetText())
+			if name == "" {
+				app.StatusLine().Error("Enter a template name first")
+				f.SetFocus(0)
+				return
+			}
+
+			t := &publish.Template{Name: name, Message: *msg}
+			if err := publish.SaveTemplate(t); err != nil {
+				app.StatusLine().Errorf("Failed to save template %s: %s", name, err)
+				return
+			}
+
+			templates = append(slices.DeleteFunc(templates, func(o *publish.Template) bool { return o.Name == name }), t)
+			templateNames = utils.Map(templates, func(t *publish.Template) string { return t.Name })
+
+			app.StatusLine().Infof("Template %s saved", name)
+		default:
+			app.DismissModal()
+		}
+	})
+
+	modalHeight := 21
+	if opts.WithRoutingKey {
+		modalHeight += 4
+	}
 
 	headersHeight := headersField.GetFieldHeight()
 	propertiesHeight := propertiesField.GetFieldHeight()
 
 	modal := ui.NewModalDialog(f, 80, modalHeight+headersHeight+propertiesHeight)
-	mm.ShowModal(modal)
+	app.ShowModal(modal)
 
 	resize := func() {
 		modal.Resize(80, modalHeight+headersHeight+propertiesHeight)
@@ -72,3 +300,27 @@ func ShowPublishMessageDialog(mm model.ModalManager, vhost, queue string, okFn P
 	headersField.SetRowsChangedFunc(func(height int) { headersHeight = height; resize() })
 	propertiesField.SetRowsChangedFunc(func(height int) { propertiesHeight = height; resize() })
 }
+
+// publishTitle returns the title of the dialog publishing to the target.
+func publishTitle(target *publish.Message, withRoutingKey bool) string {
+	if target.Exchange == "" && !withRoutingKey {
+		return fmt.Sprintf("Publish message to queue %s in %s", target.RoutingKey, target.Vhost)
+	}
+
+	return fmt.Sprintf("Publish message to exchange %s in %s", view.ExchangeDisplayName(target.Exchange), target.Vhost)
+}
+
+// propertiesDeliveryMode returns the delivery mode of message properties,
+// which may have been read from a file.
+func propertiesDeliveryMode(props map[string]any) rmq.MessageDeliveryMode {
+	switch v := props["delivery_mode"].(type) {
+	case rmq.MessageDeliveryMode:
+		return v
+	case int:
+		return rmq.MessageDeliveryMode(v)
+	case float64:
+		return rmq.MessageDeliveryMode(v)
+	}
+
+	return rmq.MessageDeliveryModeUnknown
+}
@@ -0,0 +1,230 @@
+package queues
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/publish"
+	"tbunny/internal/rmq"
+	"tbunny/internal/skins"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/view"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// repeatProgressInterval specifies how often the progress of repeated publishing is displayed.
+const repeatProgressInterval = 250 * time.Millisecond
+
+// confirmWindow is the number of messages published before waiting for
+// their confirmation.
+const confirmWindow = 256
+
+// PublishRepeatedly publishes copies of a message over AMQP, each with its
+// placeholders expanded, as defined by repeat. The progress is displayed in a
+// modal dialog until all messages are published or Esc is pressed; doneFn is
+// called at the end.
+func PublishRepeatedly(app model.App, c *cluster.Cluster, msg *publish.Message, repeat publish.Repeat, doneFn func()) {
+	name := view.ExchangeDisplayName(msg.Exchange)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	progress := newRepeatProgress(name, repeat, cancel)
+
+	app.DismissModal()
+	app.ShowModal(ui.NewModalDialog(progress, 60, 9))
+
+	go func() {
+		defer cancel()
+
+		err := publishRepeatedly(ctx, c, msg, repeat, progress)
+
+		app.QueueUpdateDraw(func() {
+			app.DismissModal()
+		})
+
+		published, unroutable := progress.published.Load(), progress.unroutable.Load()
+		elapsed := time.Since(progress.start)
+		summary := fmt.Sprintf("%d messages to %s in %s (%.1f msg/s)", published, name, elapsed.Round(time.Millisecond), rate(published, elapsed))
+
+		switch {
+		case err != nil:
+			slog.Error("Failed to publish messages", sl.Error, err, sl.VirtualHost, msg.Vhost, sl.Resource, msg.Exchange)
+			app.StatusLine().Errorf("Failed to publish messages after %s: %s", summary, err)
+		case unroutable > 0:
+			app.StatusLine().Errorf("Published %s, %d of them not routed to any queue", summary, unroutable)
+		case ctx.Err() != nil:
+			app.StatusLine().Warningf("Cancelled after publishing %s", summary)
+		default:
+			app.StatusLine().Infof("Published %s", summary)
+		}
+
+		if doneFn != nil {
+			doneFn()
+		}
+	}()
+
+	go progress.refresh(ctx, app)
+}
+
+func publishRepeatedly(ctx context.Context, c *cluster.Cluster, msg *publish.Message, repeat publish.Repeat, progress *repeatProgress) error {
+	conn, err := c.DialAMQP(msg.Vhost)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	publisher, err := rmq.NewPublisher(conn, msg.Exchange, confirmWindow)
+	if err != nil {
+		return err
+	}
+	defer publisher.Close()
+
+	// The published messages are those confirmed by the broker, also when
+	// publishing is cancelled.
+	update := func() {
+		progress.published.Store(int64(publisher.Confirmed()))
+		progress.unroutable.Store(int64(publisher.Returned()))
+	}
+	defer update()
+
+	start := time.Now()
+
+	for n := 0; !repeat.IsDone(n, time.Since(start)); n++ {
+		// Sends are paced, confirmations are awaited only when the window is full.
+		if wait := time.Until(start.Add(repeat.Delay(n))); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return publisher.Wait()
+			case <-time.After(wait):
+			}
+		} else if ctx.Err() != nil {
+			return publisher.Wait()
+		}
+
+		expanded := msg.Expand()
+
+		if err = publisher.Publish(expanded.PublishOptions(), expanded.Mandatory); err != nil {
+			return err
+		}
+
+		// The first message is kept in the history to be published again,
+		// once confirmed to know whether it was routed.
+		if n == 0 {
+			if err = publisher.Wait(); err != nil {
+				return err
+			}
+
+			entry := publish.HistoryEntry{Time: time.Now(), Routed: publisher.Returned() == 0, Message: *msg}
+			if err = publish.AddToHistory(c.Name(), &entry); err != nil {
+				slog.Error("Failed to save publish history", sl.Error, err, sl.Cluster, c.Name())
+			}
+		}
+
+		update()
+	}
+
+	return publisher.Wait()
+}
+
+// repeatProgress displays the progress of repeated publishing.
+type repeatProgress struct {
+	*tview.TextView
+
+	destination string
+	repeat      publish.Repeat
+	start       time.Time
+	published   atomic.Int64
+	unroutable  atomic.Int64
+}
+
+func newRepeatProgress(destination string, repeat publish.Repeat, cancel context.CancelFunc) *repeatProgress {
+	p := repeatProgress{
+		TextView:    tview.NewTextView(),
+		destination: destination,
+		repeat:      repeat,
+		start:       time.Now(),
+	}
+
+	p.SetDynamicColors(true)
+	p.SetBorder(true)
+	p.SetBorderPadding(1, 0, 2, 2)
+	p.SetTitle(" Publishing messages ")
+	p.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			cancel()
+			return nil
+		}
+
+		return event
+	})
+
+	p.update()
+
+	return &p
+}
+
+// ApplySkin applies the provided skin to the progress dialog.
+func (p *repeatProgress) ApplySkin(skin *skins.Skin) {
+	p.SetBackgroundColor(skin.Dialog.BgColor.Color())
+	p.SetTextColor(skin.Dialog.FgColor.Color())
+	p.SetBorderColor(skin.Dialog.FgColor.Color())
+	p.SetTitleColor(skin.Dialog.FgColor.Color())
+}
+
+func (p *repeatProgress) refresh(ctx context.Context, app model.App) {
+	ticker := time.NewTicker(repeatProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			app.QueueUpdateDraw(p.update)
+		}
+	}
+}
+
+func (p *repeatProgress) update() {
+	published := p.published.Load()
+	elapsed := time.Since(p.start)
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Publishing to %s\n\n", tview.Escape(p.destination))
+	sb.WriteString(view.RenderProgressBar(100*p.repeat.Progress(int(published), elapsed), 40, view.DefaultProgressBarStyle))
+
+	if p.repeat.Count > 0 {
+		fmt.Fprintf(&sb, "\n%d / %d messages", published, p.repeat.Count)
+	} else {
+		fmt.Fprintf(&sb, "\n%d messages", published)
+	}
+
+	fmt.Fprintf(&sb, ", %.1f msg/s", rate(published, elapsed))
+	if p.repeat.Rate > 0 {
+		fmt.Fprintf(&sb, " (target %g)", p.repeat.Rate)
+	}
+
+	if unroutable := p.unroutable.Load(); unroutable > 0 {
+		fmt.Fprintf(&sb, ", %d not routed", unroutable)
+	}
+
+	sb.WriteString("\n\nPress Esc to cancel")
+
+	p.SetText(sb.String())
+}
+
+func rate(n int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(n) / elapsed.Seconds()
+}
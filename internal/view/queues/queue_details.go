@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync/atomic"
+	"tbunny/internal/metrics"
 	"tbunny/internal/model"
 	"tbunny/internal/rmq"
 	"tbunny/internal/skins"
@@ -11,8 +13,11 @@ import (
 	"tbunny/internal/ui"
 	"tbunny/internal/utils"
 	"tbunny/internal/view"
+	"tbunny/internal/view/consumers"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
 	"github.com/rivo/tview"
 )
 
@@ -21,6 +26,10 @@ const QueueDetailsTitleFmt = " [fg:bg:b]%s[fg:bg:-]([hilite:bg:b]%s[fg:bg:-]) "
 const (
 	// Minimum width for four-column mode (4 columns x 28 + padding).
 	minWidthForFourColumns = 117
+
+	// Number of points of trend sparklines in four-column and scrollable modes.
+	columnarTrendPoints   = 60
+	scrollableTrendPoints = 30
 )
 
 type QueueDetails struct {
@@ -30,6 +39,10 @@ type QueueDetails struct {
 	vhost     string
 	queueInfo *rmq.DetailedQueueInfo
 
+	// history keeps the metrics of the queue, displayed as trends over window.
+	history *metrics.History
+	window  atomic.Int64
+
 	skin *skins.Skin
 
 	useScrollableMode bool
@@ -85,6 +98,10 @@ type QueueDetails struct {
 	// Node.
 	nodeView *tview.TextView
 
+	// Trends section (colspan).
+	trendsPanel *tview.Flex
+	trendsView  *tview.TextView
+
 	// Section headers.
 	messagesHeader  *tview.TextView
 	ratesHeader     *tview.TextView
@@ -93,6 +110,7 @@ type QueueDetails struct {
 	argumentsHeader *tview.TextView
 	policyHeader    *tview.TextView
 	nodeHeader      *tview.TextView
+	trendsHeader    *tview.TextView
 
 	// For scrollable mode.
 	scrollableView  *tview.TextView
@@ -111,8 +129,11 @@ func NewQueueDetails(name, vhost string) *QueueDetails {
 		vhost:                       vhost,
 	}
 
+	q.window.Store(int64(metrics.DefaultWindow))
+
 	q.SetUpdateFn(q.performUpdate)
 	q.AddBindingKeysFn(q.bindScrollKeys)
+	q.AddBindingKeysFn(q.bindKeys)
 
 	return &q
 }
@@ -124,6 +145,7 @@ func (q *QueueDetails) Init(app model.App) (err error) {
 	}
 
 	q.skin = skins.Current()
+	q.history = metrics.For(q.Cluster().Name(), "queue", q.vhost, q.name)
 
 	// Start in scrollable mode as a safe fallback.
 	// The actual mode is determined on the first update.
@@ -172,7 +194,10 @@ func (q *QueueDetails) determineLayoutMode() {
 }
 
 func (q *QueueDetails) performUpdate(view.UpdateKind) {
-	i, err := q.Cluster().GetQueue(q.vhost, q.name)
+	window := q.trendsWindow()
+	sampleRange := rmq.SampleRange{Age: window, Incr: metrics.SampleInterval(window)}
+
+	i, err := q.Cluster().GetQueueWithSamples(q.vhost, q.name, sampleRange)
 	if err != nil {
 		q.App().StatusLine().Errorf("Не удалось получить данные очереди: %v", err.Error())
 		slog.Error("Failed to get queue info", sl.Error, err.Error(), sl.VirtualHost, q.vhost, "queue", q.name)
@@ -180,6 +205,7 @@ func (q *QueueDetails) performUpdate(view.UpdateKind) {
 	}
 
 	q.queueInfo = i
+	q.recordHistory(i)
 
 	q.App().QueueUpdateDraw(func() {
 		// Check whether the display mode has changed.
@@ -200,6 +226,47 @@ func (q *QueueDetails) performUpdate(view.UpdateKind) {
 	})
 }
 
+// recordHistory adds the samples of the queue lengths and message rates to its history.
+func (q *QueueDetails) recordHistory(qi *rmq.DetailedQueueInfo) {
+	now := time.Now()
+
+	q.history.Add("ready", metrics.Gauge(qi.MessagesReadyDetails, float64(qi.MessagesReady), now)...)
+	q.history.Add("unacked", metrics.Gauge(qi.MessagesUnacknowledgedDetails, float64(qi.MessagesUnacknowledged), now)...)
+
+	stats := qi.MessageStats
+	if stats == nil {
+		stats = &rabbithole.MessageStats{}
+	}
+
+	q.history.Add("publish", metrics.Rate(&stats.PublishDetails, now)...)
+	q.history.Add("deliver", metrics.Rate(&stats.DeliverGetDetails, now)...)
+	q.history.Add("ack", metrics.Rate(&stats.AckDetails, now)...)
+}
+
+func (q *QueueDetails) trendsWindow() time.Duration {
+	return time.Duration(q.window.Load())
+}
+
+func (q *QueueDetails) trends(points int) []view.Trend {
+	window := q.trendsWindow()
+
+	return []view.Trend{
+		{Label: "Ready:", Values: q.history.Values("ready", window, points), Format: view.FormatCount},
+		{Label: "Unacked:", Values: q.history.Values("unacked", window, points), Format: view.FormatCount},
+		{Label: "Publish:", Values: q.history.Values("publish", window, points), Format: view.FormatMessageRate},
+		{Label: "Deliver:", Values: q.history.Values("deliver", window, points), Format: view.FormatMessageRate},
+		{Label: "Ack:", Values: q.history.Values("ack", window, points), Format: view.FormatMessageRate},
+	}
+}
+
+func (q *QueueDetails) updateTrendsView() {
+	b := new(strings.Builder)
+	view.WriteTrends(b, q.trends(columnarTrendPoints))
+
+	q.trendsHeader.SetText(view.TrendsCaption(q.trendsWindow()))
+	q.trendsView.SetText(view.SkinStatsContent(b.String(), &q.skin.Views.Stats))
+}
+
 func (q *QueueDetails) updateScrollableView() {
 	qi := q.queueInfo
 
@@ -296,6 +363,9 @@ func (q *QueueDetails) updateColumnarView() {
 	} else {
 		q.nodeView.SetText("N/A")
 	}
+
+	// Trends
+	q.updateTrendsView()
 }
 
 func (q *QueueDetails) updateTitle() {
@@ -373,6 +443,13 @@ func (q *QueueDetails) createFourColumnLayout() {
 	mainFlex.AddItem(q.col4Panel, 28, 0, false)
 	mainFlex.AddItem(tview.NewFlex(), 0, 1, false) // Right padding.
 
+	// Trends section with colspan (28*4 = 112).
+	q.trendsPanel = q.createTrendsPanel()
+	trendsFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
+	trendsFlex.AddItem(tview.NewFlex(), 0, 1, false)
+	trendsFlex.AddItem(q.trendsPanel, 112, 0, false)
+	trendsFlex.AddItem(tview.NewFlex(), 0, 1, false)
+
 	// Arguments section with colspan (28*4 = 112).
 	q.argumentsPanel = q.createArgumentsPanel()
 	argumentsFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
@@ -397,7 +474,8 @@ func (q *QueueDetails) createFourColumnLayout() {
 	// Add all sections.
 	// mainFlex: max column height = 10 (Messages).
 	q.Ui().AddItem(mainFlex, 10, 0, false)
-	q.Ui().AddItem(tview.NewBox(), 1, 0, false) // Blank line before Arguments.
+	q.Ui().AddItem(tview.NewBox(), 1, 0, false) // Blank line before Trends.
+	q.Ui().AddItem(trendsFlex, 8, 0, false)
 	q.Ui().AddItem(argumentsFlex, 0, 1, false)
 	q.Ui().AddItem(policyFlex, 4, 0, false)
 	q.Ui().AddItem(nodeFlex, 3, 0, false)
@@ -495,6 +573,22 @@ func (q *QueueDetails) createArgumentsPanel() *tview.Flex {
 	return panel
 }
 
+func (q *QueueDetails) createTrendsPanel() *tview.Flex {
+	panel := tview.NewFlex().SetDirection(tview.FlexRow)
+
+	q.trendsHeader = tview.NewTextView().SetText(view.TrendsCaption(q.trendsWindow())).SetDynamicColors(true)
+	panel.AddItem(q.trendsHeader, 1, 0, false)
+
+	separator := tview.NewTextView().SetText(strings.Repeat("─", 110))
+	panel.AddItem(separator, 1, 0, false)
+
+	// Trends: 5 rows and a blank line before Arguments.
+	q.trendsView = tview.NewTextView().SetDynamicColors(true).SetWrap(false)
+	panel.AddItem(q.trendsView, 6, 0, false)
+
+	return panel
+}
+
 func (q *QueueDetails) createMessageRatesSection() *tview.Flex {
 	section := tview.NewFlex().SetDirection(tview.FlexRow)
 
@@ -683,6 +777,10 @@ func (q *QueueDetails) applyStyles() {
 		q.nodeView.SetTextColor(skin.ValueFgColor.Color())
 		q.nodeView.SetBackgroundColor(bgColor)
 
+		// Trends
+		q.trendsView.SetTextColor(skin.ValueFgColor.Color())
+		q.trendsView.SetBackgroundColor(bgColor)
+
 		// Apply styles to section headers.
 		q.messagesHeader.SetTextStyle(captionStyle)
 		q.ratesHeader.SetTextStyle(captionStyle)
@@ -691,9 +789,31 @@ func (q *QueueDetails) applyStyles() {
 		q.argumentsHeader.SetTextStyle(captionStyle)
 		q.policyHeader.SetTextStyle(captionStyle)
 		q.nodeHeader.SetTextStyle(captionStyle)
+		q.trendsHeader.SetTextStyle(captionStyle)
 	}
 }
 
+func (q *QueueDetails) bindKeys(km ui.KeyMap) {
+	km.Add(ui.KeyO, ui.NewKeyAction("Show consumers", q.showConsumersCmd))
+	km.Add(ui.KeyW, ui.NewKeyAction("Cycle trends window", q.cycleTrendsWindowCmd))
+}
+
+func (q *QueueDetails) cycleTrendsWindowCmd(*tcell.EventKey) *tcell.EventKey {
+	window := metrics.NextWindow(q.trendsWindow())
+	q.window.Store(int64(window))
+
+	q.App().StatusLine().Infof("Trends window: %s", view.FormatWindow(window))
+	q.RequestUpdate(view.PartialUpdate)
+
+	return nil
+}
+
+func (q *QueueDetails) showConsumersCmd(*tcell.EventKey) *tcell.EventKey {
+	q.App().AddView(consumers.NewQueueConsumers(q.name, q.vhost))
+
+	return nil
+}
+
 func (q *QueueDetails) bindScrollKeys(km ui.KeyMap) {
 	scroll := func(delta int) ui.ActionHandler {
 		return func(e *tcell.EventKey) *tcell.EventKey {
@@ -835,6 +955,9 @@ func (q *QueueDetails) formatQueueInfoAsText(qi *rmq.DetailedQueueInfo) string {
 		{Label: "Active consumers:", Value: fmt.Sprintf("%d", qi.ActiveConsumers)},
 	})
 
+	// Trends
+	view.WriteTrendsSection(b, q.trendsWindow(), q.trends(scrollableTrendPoints))
+
 	// Node
 	b.WriteString("[caption]Node[-]\n")
 	b.WriteString(strings.Repeat("─", 30) + "\n")
@@ -3,6 +3,7 @@ package queues
 import (
 	"fmt"
 	"strings"
+	"tbunny/internal/view"
 	"tbunny/internal/view/bindings"
 
 	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
@@ -49,6 +50,10 @@ func (r *QueueResource) GetTableColumnValue(columnName string) string {
 		return fmt.Sprintf("%d", r.MessagesUnacknowledged)
 	case "msgTotal":
 		return fmt.Sprintf("%d", r.Messages)
+	case "consumers":
+		return fmt.Sprintf("%d", r.Consumers)
+	case "memory":
+		return view.FormatBytes(r.Memory)
 	case "msgRateIn":
 		if r.MessageStats == nil {
 			return ""
@@ -69,6 +74,14 @@ func (r *QueueResource) GetTableColumnValue(columnName string) string {
 	}
 }
 
+func (r *QueueResource) GetTableColumnSortValue(columnName string) (float64, bool) {
+	if columnName == "memory" {
+		return float64(r.Memory), true
+	}
+
+	return 0, false
+}
+
 func (r *QueueResource) getFeatures() string {
 	var parts []string
 
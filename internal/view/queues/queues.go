@@ -3,9 +3,12 @@ package queues
 import (
 	"fmt"
 	"log/slog"
-	"net/url"
 	"slices"
+	"strings"
+	"sync"
+	"tbunny/internal/alerts"
 	"tbunny/internal/model"
+	"tbunny/internal/publish"
 	"tbunny/internal/rmq"
 	"tbunny/internal/skins"
 	"tbunny/internal/sl"
@@ -14,6 +17,8 @@ import (
 	"tbunny/internal/utils"
 	"tbunny/internal/view"
 	"tbunny/internal/view/bindings"
+	"tbunny/internal/view/consumers"
+	"tbunny/internal/view/shovels"
 	"tbunny/internal/view/vhosts"
 
 	"github.com/gdamore/tcell/v2"
@@ -21,6 +26,10 @@ import (
 	"github.com/rivo/tview"
 )
 
+// moveAction describes moving messages for status line messages. Messages
+// are moved by shovels in the background, so there is no past tense.
+var moveAction = view.BulkAction{Name: "move messages from", Progress: "Moving messages from", Done: "Moving messages from"}
+
 type Queues struct {
 	view.ClusterAwareResourceView[*QueueResource]
 
@@ -44,6 +53,11 @@ func NewQueues() model.View {
 	return &q
 }
 
+// AlertResource returns the type of resources of alert rules applying to the view.
+func (q *Queues) AlertResource() string {
+	return alerts.Queues
+}
+
 func (q *Queues) GetResources() ([]*QueueResource, error) {
 	queues, err := q.getQueues()
 	if err != nil {
@@ -77,6 +91,12 @@ func (q *Queues) getQueues() (queues []rabbithole.QueueInfo, err error) {
 }
 
 func (q *Queues) GetColumns() []ui.TableColumn {
+	return Columns(q.wideMode, q.Cluster().ActiveVirtualHost() == "")
+}
+
+// Columns returns queue table columns; withVhost adds the virtual host column
+// for tables listing queues of all virtual hosts.
+func Columns(wide, withVhost bool) []ui.TableColumn {
 	c := []ui.TableColumn{
 		{Name: "name", Title: "NAME", Expansion: 2},
 		{Name: "type", Title: "TYPE"},
@@ -86,19 +106,21 @@ func (q *Queues) GetColumns() []ui.TableColumn {
 		{Name: "msgTotal", Title: "MT", Align: tview.AlignRight},
 	}
 
-	if q.wideMode {
+	if wide {
 		c = append(c, []ui.TableColumn{
+			{Name: "consumers", Title: "CONS", Align: tview.AlignRight},
+			{Name: "memory", Title: "MEMORY", Align: tview.AlignRight},
 			{Name: "msgRateIn", Title: "MR/S", Align: tview.AlignRight},
 			{Name: "msgRateDelivered", Title: "MD/S", Align: tview.AlignRight},
 			{Name: "msgRateAcked", Title: "MA/S", Align: tview.AlignRight},
 		}...)
 	}
 
-	if q.Cluster().ActiveVirtualHost() == "" {
+	if withVhost {
 		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
 	}
 
-	if q.wideMode {
+	if wide {
 		c = append(c, ui.TableColumn{Name: "node", Title: "NODE"})
 	}
 
@@ -121,6 +143,9 @@ func (q *Queues) bindKeys(km ui.KeyMap) {
 		km.Add(ui.KeyM, ui.NewKeyAction("Get messages", q.getMessagesCmd))
 		km.Add(ui.KeyP, ui.NewKeyAction("Publish message", q.publishMessageCmd))
 		km.Add(ui.KeyV, ui.NewKeyAction("Move messages", q.moveMessagesCmd))
+		km.Add(ui.KeyO, ui.NewKeyAction("Show consumers", q.showConsumersCmd))
+		km.Add(ui.KeyT, ui.NewKeyAction("Tail", q.tailCmd))
+		km.Add(ui.KeyI, ui.NewKeyAction("Import messages", q.importMessagesCmd))
 		km.Add(tcell.KeyCtrlP, ui.NewKeyAction("Purge", q.purgeQueueCmd))
 		km.Add(tcell.KeyCtrlW, ui.NewKeyAction("Toggle wide mode", q.toggleWideModeCmd))
 	}
@@ -135,25 +160,118 @@ func (q *Queues) toggleWideModeCmd(*tcell.EventKey) *tcell.EventKey {
 }
 
 func (q *Queues) getMessagesCmd(*tcell.EventKey) *tcell.EventKey {
-	if queue, ok := q.GetSelectedResource(); ok {
-		ShowGetMessagesDialog(q.App(), queue, q.getMessages)
+	if queues := q.GetSelectedResources(); len(queues) > 0 {
+		ShowGetMessagesDialog(q.App(), queues, q.getMessages)
 	}
 
 	return nil
 }
 
-func (q *Queues) getMessages(queue *QueueResource, ackMode rmq.AckMode, encoding rmq.RequestedMessageEncoding, count int) {
-	q.App().StatusLine().Infof("Getting messages from %s...", queue.GetDisplayName())
+// getMessages fetches messages from the queues in the background and opens
+// a view of them.
+func (q *Queues) getMessages(queues []*QueueResource, ackMode rmq.AckMode, encoding rmq.RequestedMessageEncoding, count int) {
+	app := q.App()
+	c := q.Cluster()
+
+	app.DismissModal()
+
+	if len(queues) == 1 {
+		queue := queues[0]
+
+		app.StatusLine().Infof("Getting messages from %s...", queue.GetDisplayName())
+
+		go func() {
+			messages, err := c.GetQueueMessages(queue.Vhost, queue.Name, ackMode, encoding, count)
+			if err != nil {
+				app.StatusLine().Errorf("Failed to get messages: %s", err)
+				return
+			}
+
+			app.QueueUpdateDraw(func() {
+				app.AddView(NewMessages(messages, queue.Name, queue.Vhost))
+			})
+		}()
 
-	messages, err := q.Cluster().GetQueueMessages(queue.Vhost, queue.Name, ackMode, encoding, count)
-	if err != nil {
-		q.App().StatusLine().Errorf("Failed to get messages: %s", err)
 		return
 	}
 
-	messagesView := NewMessages(messages, queue.Name, queue.Vhost)
+	app.StatusLine().Infof("Getting messages from %s...", view.DescribeResources("queues", queues))
+
+	go func() {
+		fetched := make([][]*rmq.FetchedMessage, len(queues))
+		errs := make([]error, len(queues))
+
+		var wg sync.WaitGroup
+		for i, queue := range queues {
+			wg.Go(func() {
+				fetched[i], errs[i] = c.GetQueueMessages(queue.Vhost, queue.Name, ackMode, encoding, count)
+			})
+		}
+		wg.Wait()
+
+		var messages []*MessageResource
+		var failed []string
+
+		for i, queue := range queues {
+			if errs[i] != nil {
+				slog.Error("Failed to get messages", sl.Error, errs[i], sl.VirtualHost, queue.Vhost, sl.Resource, queue.Name)
+				failed = append(failed, queue.Name)
+				continue
+			}
+
+			for _, m := range fetched[i] {
+				messages = append(messages, &MessageResource{FetchedMessage: m, index: len(messages), queue: queue.Name})
+			}
+		}
 
-	q.App().AddView(messagesView)
+		if len(failed) == len(queues) {
+			app.StatusLine().Errorf("Failed to get messages: %s", errs[0])
+			return
+		}
+
+		if len(failed) > 0 {
+			app.StatusLine().Errorf("Failed to get messages from %s", strings.Join(failed, ", "))
+		} else {
+			app.StatusLine().Infof("Got %d messages from %d queues", len(messages), len(queues))
+		}
+
+		app.QueueUpdateDraw(func() {
+			app.AddView(newQueuesMessages(messages, queues))
+		})
+	}()
+}
+
+func (q *Queues) showConsumersCmd(*tcell.EventKey) *tcell.EventKey {
+	if queue, ok := q.GetSelectedResource(); ok {
+		q.App().AddView(consumers.NewQueueConsumers(queue.Name, queue.Vhost))
+	}
+
+	return nil
+}
+
+func (q *Queues) tailCmd(*tcell.EventKey) *tcell.EventKey {
+	if queue, ok := q.GetSelectedResource(); ok {
+		q.App().AddView(NewQueueTail(queue))
+	}
+
+	return nil
+}
+
+func (q *Queues) importMessagesCmd(*tcell.EventKey) *tcell.EventKey {
+	queue, ok := q.GetSelectedResource()
+	if !ok {
+		return nil
+	}
+
+	title := fmt.Sprintf("Publish messages to %s", queue.GetDisplayName())
+
+	ShowImportMessagesDialog(q.App(), title, false, func(path, _ string) {
+		ImportMessages(q.App(), q.Cluster(), queue.Vhost, "", queue.Name, path, func() {
+			q.RequestUpdate(view.PartialUpdate)
+		})
+	})
+
+	return nil
 }
 
 func (q *Queues) showDetails(queue *QueueResource) {
@@ -196,26 +314,21 @@ func (q *Queues) createQueue(queueType, vhost, name string, durable bool, args m
 }
 
 func (q *Queues) purgeQueueCmd(*tcell.EventKey) *tcell.EventKey {
-	queue, ok := q.GetSelectedResource()
-	if !ok {
+	queues := q.GetSelectedResources()
+	if len(queues) == 0 {
 		return nil
 	}
 
-	msg := fmt.Sprintf("Purge %s?", queue.GetDisplayName())
+	msg := fmt.Sprintf("Purge %s?", view.DescribeResources("queues", queues))
 
 	modal := dialog.CreateConfirmDialog(
 		skins.Current(),
 		"Confirm Purge",
 		msg,
 		func() {
-			q.App().StatusLine().Infof("Purging %s...", queue.GetDisplayName())
-
-			_, err := q.Cluster().PurgeQueue(queue.Vhost, queue.Name)
-			if err != nil {
-				q.App().StatusLine().Errorf("Failed to purge queue: %s", err)
-			} else {
+			view.RunBulkAction(q.App(), "queues", queues, view.PurgeAction, q.purgeQueue, func() {
 				q.RequestUpdate(view.PartialUpdate)
-			}
+			})
 		},
 		func() {
 			q.App().DismissModal()
@@ -226,54 +339,71 @@ func (q *Queues) purgeQueueCmd(*tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+func (q *Queues) purgeQueue(queue *QueueResource) error {
+	_, err := q.Cluster().PurgeQueue(queue.Vhost, queue.Name)
+
+	return err
+}
+
 func (q *Queues) publishMessageCmd(*tcell.EventKey) *tcell.EventKey {
 	if queue, ok := q.GetSelectedResource(); ok {
-		ShowPublishMessageDialog(q.App(), queue.Vhost, queue.Name, q.publishMessage)
+		opts := PublishMessageDialogOptions{
+			Title:      "Publish message",
+			Vhost:      queue.Vhost,
+			RoutingKey: queue.Name,
+		}
+
+		ShowPublishMessageDialog(q.App(), opts, func(msg *publish.Message, repeat publish.Repeat) {
+			q.publishMessage(msg, repeat)
+		})
 	}
 
 	return nil
 }
 
-func (q *Queues) publishMessage(vhost, queue string, props map[string]any, payload string, payloadEncoding rmq.PayloadEncoding) {
-	opts := rabbithole.PublishOptions{
-		RoutingKey:      queue,
-		Properties:      props,
-		Payload:         payload,
-		PayloadEncoding: string(payloadEncoding),
-	}
-
-	q.App().StatusLine().Infof("Publishing message to %s", queue)
-
-	_, err := q.Cluster().PublishToExchange(vhost, "amq.default", opts)
-	if err != nil {
-		q.App().StatusLine().Errorf("Failed to publish message: %s", err)
+func (q *Queues) publishMessage(msg *publish.Message, repeat publish.Repeat) {
+	if repeat.IsRepeated() {
+		PublishRepeatedly(q.App(), q.Cluster(), msg, repeat, func() {
+			q.RequestUpdate(view.PartialUpdate)
+		})
 		return
 	}
 
-	q.App().StatusLine().Infof("Message published successfully to %s", queue)
-
-	q.App().DismissModal()
-	q.RequestUpdate(view.PartialUpdate)
+	Publish(q.App(), q.Cluster(), msg, func(routed bool) {
+		if routed {
+			q.App().DismissModal()
+			q.RequestUpdate(view.PartialUpdate)
+		}
+	})
 }
 
 func (q *Queues) moveMessagesCmd(*tcell.EventKey) *tcell.EventKey {
-	queue, ok := q.GetSelectedResource()
-	if !ok {
+	sourceQueues := q.GetSelectedResources()
+	if len(sourceQueues) == 0 {
 		return nil
 	}
 
-	node, err := q.Cluster().GetNode(queue.Node)
-	if err != nil {
-		q.App().StatusLine().Errorf("Failed to get node info: %s", err)
+	vhost := sourceQueues[0].Vhost
+	if slices.ContainsFunc(sourceQueues, func(queue *QueueResource) bool { return queue.Vhost != vhost }) {
+		q.App().StatusLine().Error("Messages can only be moved from queues of the same virtual host")
 		return nil
 	}
 
-	if !slices.ContainsFunc(node.ErlangApps, func(app rabbithole.ErlangApp) bool { return app.Name == "rabbitmq_shovel" }) {
-		q.App().StatusLine().Error("Shovel plugin is not enabled on the selected node")
-		return nil
+	nodes := slices.Compact(slices.Sorted(slices.Values(utils.Map(sourceQueues, func(queue *QueueResource) string { return queue.Node }))))
+	for _, nodeName := range nodes {
+		node, err := q.Cluster().GetNode(nodeName)
+		if err != nil {
+			q.App().StatusLine().Errorf("Failed to get node info: %s", err)
+			return nil
+		}
+
+		if !slices.ContainsFunc(node.ErlangApps, func(app rabbithole.ErlangApp) bool { return app.Name == "rabbitmq_shovel" }) {
+			q.App().StatusLine().Errorf("Shovel plugin is not enabled on node %s", nodeName)
+			return nil
+		}
 	}
 
-	destinationQueues, err := q.Cluster().ListQueuesIn(queue.Vhost)
+	destinationQueues, err := q.Cluster().ListQueuesIn(vhost)
 	if err != nil {
 		q.App().StatusLine().Errorf("Failed to list queues: %s", err)
 		return nil
@@ -281,32 +411,71 @@ func (q *Queues) moveMessagesCmd(*tcell.EventKey) *tcell.EventKey {
 
 	destinationQueueNames := utils.FilterMap(
 		destinationQueues,
-		func(q rabbithole.QueueInfo) bool { return q.Name != queue.Name },
+		func(dq rabbithole.QueueInfo) bool {
+			return !slices.ContainsFunc(sourceQueues, func(queue *QueueResource) bool { return queue.Name == dq.Name })
+		},
 		func(q rabbithole.QueueInfo) string { return q.Name })
 
-	ShowMoveMessagesDialog(q.App(), queue.Vhost, queue.Name, destinationQueueNames, q.moveMessages)
+	ShowMoveMessagesDialog(q.App(), sourceQueues, destinationQueueNames, q.moveMessages)
 
 	return nil
 }
 
-func (q *Queues) moveMessages(vhost, sourceQueue, destinationQueue string) {
-	uri := rabbithole.URISet{fmt.Sprintf("amqp:///%s", url.PathEscape(vhost))}
+func (q *Queues) moveMessages(sourceQueues []*QueueResource, destinationQueue string) {
+	vhost := sourceQueues[0].Vhost
+
+	if len(sourceQueues) == 1 {
+		sourceQueue := sourceQueues[0].Name
+
+		name, err := q.declareMoveShovel(sourceQueues[0], destinationQueue)
+		if err != nil {
+			q.App().StatusLine().Errorf("Failed to create shovel: %s", err.Error())
+			return
+		}
+
+		q.App().StatusLine().Infof("Moving messages from %s to %s using shovel %s", sourceQueue, destinationQueue, name)
+
+		q.App().DismissModal()
+		q.App().AddView(shovels.NewShovelsWithSelection(vhost, name))
+
+		return
+	}
+
+	q.App().DismissModal()
+
+	moveFn := func(queue *QueueResource) error {
+		_, err := q.declareMoveShovel(queue, destinationQueue)
+		return err
+	}
+
+	view.RunBulkAction(q.App(), "queues", sourceQueues, moveAction, moveFn, func() {
+		q.App().QueueUpdateDraw(func() {
+			q.App().AddView(shovels.NewShovelsWithSelection(vhost, moveShovelName(sourceQueues[0].Name)))
+		})
+	})
+}
+
+// declareMoveShovel declares a shovel moving all messages of the source
+// queue to the destination queue and returns its name.
+func (q *Queues) declareMoveShovel(sourceQueue *QueueResource, destinationQueue string) (string, error) {
+	uri := rabbithole.URISet{shovels.LocalShovelURI(sourceQueue.Vhost)}
 	sd := rabbithole.ShovelDefinition{
 		SourceURI:        uri,
 		DestinationURI:   uri,
 		AckMode:          "on-confirm",
 		DeleteAfter:      rabbithole.DeleteAfter("queue-length"),
 		SourceProtocol:   "amqp091",
-		SourceQueue:      sourceQueue,
+		SourceQueue:      sourceQueue.Name,
 		DestinationQueue: destinationQueue,
 	}
 
-	_, err := q.Cluster().DeclareShovel(vhost, fmt.Sprintf("move-from-%s", sourceQueue), sd)
-	if err != nil {
-		q.App().StatusLine().Errorf("Failed to create shovel: %s", err.Error())
-		return
-	}
+	name := moveShovelName(sourceQueue.Name)
 
-	q.App().DismissModal()
-	q.RequestUpdate(view.PartialUpdate)
+	_, err := q.Cluster().DeclareShovel(sourceQueue.Vhost, name, sd)
+
+	return name, err
+}
+
+func moveShovelName(sourceQueue string) string {
+	return fmt.Sprintf("move-from-%s", sourceQueue)
 }
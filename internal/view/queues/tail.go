@@ -0,0 +1,287 @@
+package queues
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+	"tbunny/internal/view"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+const (
+	// maxTailMessages is the number of messages a tail keeps, older messages are dropped.
+	maxTailMessages = 1000
+
+	// tailUpdateInterval specifies how often received messages are added to the table.
+	tailUpdateInterval = 500 * time.Millisecond
+)
+
+// TailStartFn starts receiving messages from the cluster.
+type TailStartFn func(c *cluster.Cluster) (*rmq.Tail, error)
+
+// Tail displays messages received live over AMQP 0-9-1, without taking them
+// away from other consumers.
+type Tail struct {
+	view.ClusterAwareResourceView[*MessageResource]
+
+	source   string
+	notice   string
+	startFn  TailStartFn
+	tail     *rmq.Tail
+	messages []*MessageResource
+	received int
+	stopping bool
+	stopped  bool
+	closed   bool
+	mx       sync.RWMutex
+}
+
+// NewExchangeTail creates a view of messages routed by the exchange with the
+// given routing key (binding key pattern) and binding arguments.
+func NewExchangeTail(vhost, exchange, routingKey string, args map[string]any) model.View {
+	startFn := func(c *cluster.Cluster) (*rmq.Tail, error) {
+		conn, err := c.DialAMQP(vhost)
+		if err != nil {
+			return nil, err
+		}
+
+		return rmq.TailExchanges(conn, []rmq.TailBinding{{Exchange: exchange, RoutingKey: routingKey, Arguments: args}})
+	}
+
+	return newTail(vhost, "exchange "+view.ExchangeDisplayName(exchange), startFn)
+}
+
+// NewQueueTail creates a view of messages reaching the queue: a stream is
+// read from its end, other queues are watched through their bindings, so
+// that their consumers still get all messages. The binding of the default
+// exchange cannot be copied: messages published to such queues through the
+// default exchange are not received.
+func NewQueueTail(queue *QueueResource) model.View {
+	startFn := func(c *cluster.Cluster) (*rmq.Tail, error) {
+		if queue.Type == "stream" {
+			conn, err := c.DialAMQP(queue.Vhost)
+			if err != nil {
+				return nil, err
+			}
+
+			return rmq.TailStream(conn, queue.Name, "next")
+		}
+
+		queueBindings, err := c.ListQueueBindings(queue.Vhost, queue.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list bindings: %w", err)
+		}
+
+		// The default exchange binding is implicit and cannot be copied.
+		tailBindings := utils.FilterMap(
+			queueBindings,
+			func(b rabbithole.BindingInfo) bool { return b.Source != "" },
+			func(b rabbithole.BindingInfo) rmq.TailBinding {
+				return rmq.TailBinding{Exchange: b.Source, RoutingKey: b.RoutingKey, Arguments: b.Arguments}
+			})
+
+		if len(tailBindings) == 0 {
+			return nil, fmt.Errorf("%s is only bound to the default exchange, which cannot be tailed", queue.GetDisplayName())
+		}
+
+		conn, err := c.DialAMQP(queue.Vhost)
+		if err != nil {
+			return nil, err
+		}
+
+		return rmq.TailExchanges(conn, tailBindings)
+	}
+
+	v := newTail(queue.Vhost, queue.GetDisplayName(), startFn)
+
+	if queue.Type != "stream" {
+		v.notice = "messages published through the default exchange are not shown"
+	}
+
+	return v
+}
+
+func newTail(vhost, source string, startFn TailStartFn) *Tail {
+	strategy := view.NewLiveUpdateStrategy()
+	strategy.SetUpdateInterval(tailUpdateInterval)
+
+	v := Tail{
+		ClusterAwareResourceView: view.NewClusterAwareResourceTableView[*MessageResource]("Tail", strategy),
+		source:                   source,
+		startFn:                  startFn,
+	}
+
+	v.SetPath(view.VhostDisplayName(vhost) + " ⏵ " + source)
+	v.SetResourceProvider(&v)
+	v.AddBindingKeysFn(v.bindKeys)
+	v.SetEnterAction("View message", v.showMessage)
+
+	return &v
+}
+
+func (v *Tail) Init(app model.App) error {
+	if err := v.ClusterAwareResourceView.Init(app); err != nil {
+		return err
+	}
+
+	v.App().StatusLine().Infof("Connecting to tail %s...", v.source)
+
+	go v.start()
+
+	return nil
+}
+
+// start connects to the cluster and receives messages until the tail stops.
+func (v *Tail) start() {
+	tail, err := v.startFn(v.Cluster())
+	if err != nil {
+		v.mx.Lock()
+		v.stopped = true
+		closed := v.closed
+		v.mx.Unlock()
+
+		if !closed {
+			v.App().StatusLine().Errorf("Failed to tail %s: %s", v.source, err)
+			v.App().QueueUpdateDraw(v.RefreshActions)
+		}
+
+		return
+	}
+
+	v.mx.Lock()
+	v.tail = tail
+	stop := v.stopping || v.closed
+	v.mx.Unlock()
+
+	switch {
+	case stop:
+		tail.Close()
+	case v.notice != "":
+		v.App().StatusLine().Infof("Tailing %s, %s...", v.source, v.notice)
+	default:
+		v.App().StatusLine().Infof("Tailing %s...", v.source)
+	}
+
+	v.receive()
+}
+
+// Close stops receiving messages when the view is closed.
+func (v *Tail) Close() {
+	v.mx.Lock()
+	v.closed = true
+	tail := v.tail
+	v.mx.Unlock()
+
+	if tail != nil {
+		tail.Close()
+	}
+}
+
+// stop stops receiving messages, or connecting to the cluster.
+func (v *Tail) stop() {
+	v.mx.Lock()
+	v.stopping = true
+	tail := v.tail
+	v.mx.Unlock()
+
+	if tail != nil {
+		tail.Close()
+	}
+}
+
+func (v *Tail) receive() {
+	for m := range v.tail.Messages() {
+		v.mx.Lock()
+		v.received++
+		v.messages = append(v.messages, NewMessageResource(m, v.received))
+
+		if len(v.messages) > maxTailMessages {
+			v.messages = slices.Delete(v.messages, 0, len(v.messages)-maxTailMessages)
+		}
+		v.mx.Unlock()
+	}
+
+	v.mx.Lock()
+	v.stopped = true
+	closed := v.closed
+	v.mx.Unlock()
+
+	if closed {
+		return
+	}
+
+	if err := v.tail.Err(); err != nil {
+		v.App().StatusLine().Errorf("Tail of %s stopped: %s", v.source, err)
+	} else {
+		v.App().StatusLine().Infof("Stopped tailing %s", v.source)
+	}
+
+	v.App().QueueUpdateDraw(v.RefreshActions)
+}
+
+func (v *Tail) GetResources() ([]*MessageResource, error) {
+	v.mx.RLock()
+	defer v.mx.RUnlock()
+
+	return slices.Clone(v.messages), nil
+}
+
+func (v *Tail) GetColumns() []ui.TableColumn {
+	return messageColumns(false)
+}
+
+func (v *Tail) ResourceMatcher(filter string) (func(*MessageResource) bool, error) {
+	return messagesMatcher(filter, v.GetColumns())
+}
+
+func (v *Tail) CanDeleteResources() bool {
+	return false
+}
+
+func (v *Tail) DeleteResource(*MessageResource) error {
+	return nil
+}
+
+func (v *Tail) bindKeys(km ui.KeyMap) {
+	v.mx.RLock()
+	defer v.mx.RUnlock()
+
+	if !v.stopped {
+		km.Add(ui.KeyS, ui.NewKeyAction("Stop", v.stopCmd))
+	}
+
+	km.Add(ui.KeyX, ui.NewKeyAction("Export", v.exportCmd))
+}
+
+// exportCmd exports marked messages or, if none is marked, all received messages.
+func (v *Tail) exportCmd(*tcell.EventKey) *tcell.EventKey {
+	messages := v.GetMarkedResources()
+	if len(messages) == 0 {
+		messages, _ = v.GetResources()
+	}
+
+	ExportMessages(v.App(), v.source, messages)
+
+	return nil
+}
+
+func (v *Tail) stopCmd(*tcell.EventKey) *tcell.EventKey {
+	v.stop()
+
+	return nil
+}
+
+func (v *Tail) showMessage(message *MessageResource) {
+	messageView := NewMessageView(message)
+	messageView.setFilter(v.GetFilter())
+
+	v.App().AddView(messageView)
+}
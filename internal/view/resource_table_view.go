@@ -2,6 +2,9 @@ package view
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"tbunny/internal/skins"
@@ -17,8 +20,32 @@ const (
 	titlePathFragmentFmt   = "([hilite:bg:b]%s[fg:bg:-])"
 	titleCountFragmentFmt  = "[fg:bg:-][[count:bg:b]%d[fg:bg:-]][fg:bg:-]"
 	titleFilterFragmentFmt = " [fg:bg:-]<[filter:bg:b]/%s[fg:bg:-]>[fg:bg:-]"
+	titleMarkedFragmentFmt = " [fg:bg:-]<[count:bg:b]%d marked[fg:bg:-]>[fg:bg:-]"
 )
 
+type sortKey struct {
+	key         tcell.Key
+	description string
+	ascending   bool
+}
+
+// sortKeys binds sort shortcuts to well-known columns. Columns that are
+// usually looked at for the largest values are sorted in descending order
+// first.
+var sortKeys = map[string]sortKey{
+	"name":             {ui.KeyAltN, "name", true},
+	"msgReady":         {ui.KeyAltR, "ready messages", false},
+	"msgUnacked":       {ui.KeyAltU, "unacked messages", false},
+	"msgTotal":         {ui.KeyAltT, "total messages", false},
+	"msgRateIn":        {ui.KeyAltI, "incoming rate", false},
+	"msgRatePublish":   {ui.KeyAltI, "publish rate", false},
+	"msgRateDelivered": {ui.KeyAltD, "deliver rate", false},
+	"msgRateAcked":     {ui.KeyAltA, "ack rate", false},
+	"consumers":        {ui.KeyAltC, "consumers", false},
+	"memory":           {ui.KeyAltM, "memory", false},
+	"mem_used":         {ui.KeyAltM, "memory", false},
+}
+
 type ResourceTableView[R Resource] struct {
 	*RefreshableView[*ui.Table[R]]
 
@@ -27,7 +54,12 @@ type ResourceTableView[R Resource] struct {
 	enterActionTitle string
 	enterActionFn    func(R)
 	filter           string
+	sortColumn       string
+	sortAscending    bool
+	pendingRowID     string
+	marked           map[string]struct{}
 	resources        []R
+	alerts           alertState[R]
 	mx               sync.RWMutex
 }
 
@@ -46,6 +78,63 @@ func (b *ResourceTableView[R]) GetSelectedResource() (R, bool) {
 	return b.Ui().GetSelectedRow()
 }
 
+// GetSelectedResources returns marked resources or, if none is marked, the
+// selected resource.
+func (b *ResourceTableView[R]) GetSelectedResources() []R {
+	if marked := b.GetMarkedResources(); len(marked) > 0 {
+		return marked
+	}
+
+	if row, ok := b.GetSelectedResource(); ok {
+		return []R{row}
+	}
+
+	return nil
+}
+
+// GetMarkedResources returns marked resources in the order of the resource
+// provider. Marked resources hidden by the filter are left out.
+func (b *ResourceTableView[R]) GetMarkedResources() []R {
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	visible := b.visibleMarks()
+	if len(visible) == 0 {
+		return nil
+	}
+
+	return utils.Filter(b.resources, func(r R) bool {
+		_, ok := visible[r.GetTableRowID()]
+		return ok
+	})
+}
+
+// visibleMarks returns the marks of the resources shown by the table. It
+// must be called with the lock held.
+func (b *ResourceTableView[R]) visibleMarks() map[string]struct{} {
+	if len(b.marked) == 0 || b.filter == "" {
+		return b.marked
+	}
+
+	visible := make(map[string]struct{}, len(b.marked))
+	for _, r := range b.Ui().Rows() {
+		id := r.GetTableRowID()
+		if _, ok := b.marked[id]; ok {
+			visible[id] = struct{}{}
+		}
+	}
+
+	return visible
+}
+
+// SelectResource selects the resource with the given table row ID. If the
+// resource is not loaded yet, it is selected as soon as it appears.
+func (b *ResourceTableView[R]) SelectResource(rowID string) {
+	b.mx.Lock()
+	b.pendingRowID = rowID
+	b.mx.Unlock()
+}
+
 func (b *ResourceTableView[R]) SetResourceProvider(rp ResourceProvider[R]) {
 	b.resourceProvider = rp
 }
@@ -88,6 +177,14 @@ func (b *ResourceTableView[R]) Filter(filter string) {
 	b.App().QueueUpdateDraw(b.filterAndSet)
 }
 
+// GetFilter returns the current filter.
+func (b *ResourceTableView[R]) GetFilter() string {
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	return b.filter
+}
+
 func (b *ResourceTableView[R]) Clear() bool {
 	if b.filter == "" {
 		return false
@@ -107,12 +204,20 @@ func (b *ResourceTableView[R]) performUpdate(kind UpdateKind) {
 		b.app.StatusLine().Error(err.Error())
 	}
 
+	var alert string
+
 	b.mx.Lock()
 	if err == nil {
 		b.resources = rows
+		b.pruneMarks()
+		alert = b.alerts.update(strings.ToLower(b.Name()), rows)
 	}
 	b.mx.Unlock()
 
+	if alert != "" {
+		b.app.StatusLine().Warningf("Alert %s", alert)
+	}
+
 	b.App().QueueUpdateDraw(func() {
 		table := b.Ui()
 
@@ -127,39 +232,110 @@ func (b *ResourceTableView[R]) performUpdate(kind UpdateKind) {
 	})
 }
 
+// WildcardFilterPrefix starts filters with "*" and "?" wildcards, e.g.
+// =orders-*, which must match whole values.
+const WildcardFilterPrefix = "="
+
+// filterMatcher returns a case-insensitive matcher for a filter. A filter
+// starting with WildcardFilterPrefix must match the whole value, any other
+// filter matches a substring.
+func filterMatcher(filter string) func(value string) bool {
+	if !strings.HasPrefix(filter, WildcardFilterPrefix) {
+		lowerFilter := strings.ToLower(filter)
+
+		return func(value string) bool {
+			return strings.Contains(strings.ToLower(value), lowerFilter)
+		}
+	}
+
+	return FilterRegexp(filter).MatchString
+}
+
+// FilterRegexp returns a case-insensitive regular expression matching values
+// as a filter does, e.g. to highlight what matched a filter.
+func FilterRegexp(filter string) *regexp.Regexp {
+	pattern, wildcards := strings.CutPrefix(filter, WildcardFilterPrefix)
+	pattern = regexp.QuoteMeta(pattern)
+
+	if wildcards {
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+		pattern = "^" + pattern + "$"
+	}
+
+	return regexp.MustCompile("(?i)" + pattern)
+}
+
+// columnsMatcher matches resources having a column value matched by the filter.
+func columnsMatcher[R Resource](filter string, columns []ui.TableColumn) func(R) bool {
+	matches := filterMatcher(filter)
+
+	return func(row R) bool {
+		for _, column := range columns {
+			if value := row.GetTableColumnValue(column.Name); value != "" && matches(value) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
 func (b *ResourceTableView[R]) filterAndSet() {
-	b.mx.RLock()
-	defer b.mx.RUnlock()
+	b.mx.Lock()
+	defer b.mx.Unlock()
 
 	var rows []R
 
 	if b.filter != "" {
+		matches, err := b.resourceMatcher()
+		if err != nil {
+			b.App().StatusLine().Errorf("Invalid filter: %s", err)
+		}
+
 		rows = make([]R, 0, len(b.resources))
-		columns := b.resourceProvider.GetColumns()
-		lowerFilter := strings.ToLower(b.filter)
 
-	r:
 		for _, row := range b.resources {
-			for _, column := range columns {
-				value := row.GetTableColumnValue(column.Name)
-				if value == "" {
-					continue
-				}
-
-				if strings.Contains(strings.ToLower(value), lowerFilter) {
-					rows = append(rows, row)
-					continue r
-				}
+			if matches != nil && matches(row) {
+				rows = append(rows, row)
 			}
 		}
 	} else {
 		rows = b.resources
 	}
 
+	if b.sortColumn != "" {
+		rows = slices.Clone(rows)
+
+		slices.SortStableFunc(rows, func(r1, r2 R) int {
+			if b.sortAscending {
+				return ui.CompareTableRows(r1, r2, b.sortColumn)
+			}
+
+			return ui.CompareTableRows(r2, r1, b.sortColumn)
+		})
+	}
+
+	b.Ui().SetRowColors(b.alerts.colors)
 	b.Ui().SetRows(rows)
+
+	if b.pendingRowID != "" && b.Ui().SelectRowByID(b.pendingRowID) {
+		b.pendingRowID = ""
+	}
+
 	b.updateTitle()
 }
 
+// resourceMatcher returns a matcher for the current filter. It must be called
+// with the lock held.
+func (b *ResourceTableView[R]) resourceMatcher() (func(R) bool, error) {
+	if rf, ok := b.resourceProvider.(ResourceFilterer[R]); ok {
+		return rf.ResourceMatcher(b.filter)
+	}
+
+	return columnsMatcher[R](b.filter, b.resourceProvider.GetColumns()), nil
+}
+
 func (b *ResourceTableView[R]) bindKeys(km ui.KeyMap) {
 	if b.enterActionTitle != "" {
 		km.Add(tcell.KeyEnter, ui.NewKeyAction(b.enterActionTitle, b.enterCmd))
@@ -168,6 +344,74 @@ func (b *ResourceTableView[R]) bindKeys(km ui.KeyMap) {
 	if b.resourceProviderWithCheck().CanDeleteResources() {
 		km.Add(tcell.KeyCtrlD, ui.NewKeyAction("Delete", b.deleteCmd))
 	}
+
+	km.Add(ui.KeySpace, ui.NewKeyActionWithGroup("Mark", b.toggleMarkCmd, false, 0))
+	km.Add(tcell.KeyCtrlA, ui.NewKeyActionWithGroup("Mark all", b.markAllCmd, false, 0))
+	km.Add(tcell.KeyCtrlBackslash, ui.NewKeyActionWithGroup("Clear marks", b.clearMarksCmd, false, 0))
+
+	columns := b.resourceProviderWithCheck().GetColumns()
+
+	for _, column := range columns {
+		if sk, ok := sortKeys[column.Name]; ok {
+			km.Add(sk.key, ui.NewKeyActionWithGroup("Sort by "+sk.description, func(*tcell.EventKey) *tcell.EventKey {
+				b.sortBy(column.Name, sk.ascending)
+				return nil
+			}, false, 0))
+		}
+	}
+
+	if len(columns) > 0 {
+		km.Add(ui.KeyLeftBracket, ui.NewKeyActionWithGroup("Sort by previous column", b.sortByPreviousColumnCmd, false, 0))
+		km.Add(ui.KeyRightBracket, ui.NewKeyActionWithGroup("Sort by next column", b.sortByNextColumnCmd, false, 0))
+	}
+}
+
+// sortBy sorts rows by a column. Sorting by the current column again
+// reverses the order; otherwise the column's default order is used.
+func (b *ResourceTableView[R]) sortBy(column string, ascending bool) {
+	b.mx.Lock()
+
+	if b.sortColumn == column {
+		b.sortAscending = !b.sortAscending
+	} else {
+		b.sortColumn = column
+		b.sortAscending = ascending
+	}
+
+	b.Ui().SetSortColumn(b.sortColumn, b.sortAscending)
+
+	b.mx.Unlock()
+
+	b.filterAndSet()
+}
+
+func (b *ResourceTableView[R]) sortByPreviousColumnCmd(*tcell.EventKey) *tcell.EventKey {
+	b.sortByAdjacentColumn(-1)
+
+	return nil
+}
+
+func (b *ResourceTableView[R]) sortByNextColumnCmd(*tcell.EventKey) *tcell.EventKey {
+	b.sortByAdjacentColumn(1)
+
+	return nil
+}
+
+func (b *ResourceTableView[R]) sortByAdjacentColumn(delta int) {
+	columns := b.resourceProviderWithCheck().GetColumns()
+
+	b.mx.RLock()
+	idx := slices.IndexFunc(columns, func(c ui.TableColumn) bool { return c.Name == b.sortColumn })
+	b.mx.RUnlock()
+
+	if idx < 0 && delta < 0 {
+		idx = len(columns)
+	}
+
+	idx = (idx + delta + len(columns)) % len(columns)
+
+	sk, ok := sortKeys[columns[idx].Name]
+	b.sortBy(columns[idx].Name, !ok || sk.ascending)
 }
 
 func (b *ResourceTableView[R]) enterCmd(*tcell.EventKey) *tcell.EventKey {
@@ -178,32 +422,103 @@ func (b *ResourceTableView[R]) enterCmd(*tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
-func (b *ResourceTableView[R]) deleteCmd(*tcell.EventKey) *tcell.EventKey {
+func (b *ResourceTableView[R]) toggleMarkCmd(*tcell.EventKey) *tcell.EventKey {
 	row, ok := b.GetSelectedResource()
 	if !ok {
 		return nil
 	}
 
+	id := row.GetTableRowID()
+
+	b.mx.Lock()
+	if _, ok = b.marked[id]; ok {
+		delete(b.marked, id)
+	} else {
+		if b.marked == nil {
+			b.marked = make(map[string]struct{})
+		}
+		b.marked[id] = struct{}{}
+	}
+	b.mx.Unlock()
+
+	b.updateMarks()
+
+	if rowIdx, _ := b.Ui().GetSelection(); rowIdx < b.Ui().GetRowCount()-1 {
+		b.Ui().Select(rowIdx+1, 0)
+	}
+
+	return nil
+}
+
+func (b *ResourceTableView[R]) markAllCmd(*tcell.EventKey) *tcell.EventKey {
+	b.mx.Lock()
+	b.marked = make(map[string]struct{})
+	for _, row := range b.Ui().Rows() {
+		b.marked[row.GetTableRowID()] = struct{}{}
+	}
+	b.mx.Unlock()
+
+	b.updateMarks()
+
+	return nil
+}
+
+func (b *ResourceTableView[R]) clearMarksCmd(*tcell.EventKey) *tcell.EventKey {
+	b.mx.Lock()
+	b.marked = nil
+	b.mx.Unlock()
+
+	b.updateMarks()
+
+	return nil
+}
+
+// pruneMarks forgets marks of resources that no longer exist. It must be
+// called with the lock held.
+func (b *ResourceTableView[R]) pruneMarks() {
+	if len(b.marked) == 0 {
+		return
+	}
+
+	existing := make(map[string]struct{}, len(b.marked))
+	for _, r := range b.resources {
+		id := r.GetTableRowID()
+		if _, ok := b.marked[id]; ok {
+			existing[id] = struct{}{}
+		}
+	}
+
+	b.marked = existing
+}
+
+func (b *ResourceTableView[R]) updateMarks() {
+	b.mx.RLock()
+	b.Ui().SetMarked(maps.Clone(b.marked))
+	b.mx.RUnlock()
+
+	b.updateTitle()
+}
+
+func (b *ResourceTableView[R]) deleteCmd(*tcell.EventKey) *tcell.EventKey {
+	resources := b.GetSelectedResources()
+	if len(resources) == 0 {
+		return nil
+	}
+
 	b.Stop()
 	defer b.Start()
 
-	displayName := row.GetDisplayName()
-	msg := fmt.Sprintf("Delete %s?", displayName)
+	kind := strings.ToLower(b.Name())
+	msg := fmt.Sprintf("Delete %s?", DescribeResources(kind, resources))
 
 	modal := dialog.CreateConfirmDialog(
 		skins.Current(),
 		"Confirm Delete",
 		msg,
 		func() {
-			b.App().StatusLine().Infof("Deleting %s...", displayName)
-
-			err := b.resourceProviderWithCheck().DeleteResource(row)
-			if err != nil {
-				b.App().StatusLine().Errorf("Failed to delete %s: %s", displayName, err.Error())
-			} else {
-				b.App().StatusLine().Infof("Deleted %s", displayName)
+			RunBulkAction(b.App(), kind, resources, DeleteAction, b.resourceProviderWithCheck().DeleteResource, func() {
 				b.RequestUpdate(PartialUpdate)
-			}
+			})
 		},
 		func() {
 			b.App().DismissModal()
@@ -235,6 +550,10 @@ func (b *ResourceTableView[R]) updateTitle() {
 		utils.Sbprintf(sb, titleFilterFragmentFmt, b.filter)
 	}
 
+	if marked := len(b.visibleMarks()); marked > 0 {
+		utils.Sbprintf(sb, titleMarkedFragmentFmt, marked)
+	}
+
 	sb.WriteString(" ")
 
 	b.Ui().SetTitle(SkinTitle(sb.String()))
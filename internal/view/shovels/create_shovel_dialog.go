@@ -0,0 +1,159 @@
+package shovels
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"tbunny/internal/cluster"
+	"tbunny/internal/model"
+	"tbunny/internal/ui"
+	"tbunny/internal/utils"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/rivo/tview"
+)
+
+type CreateShovelFn func(vhost, name string, definition rabbithole.ShovelDefinition)
+
+const (
+	shovelNameFieldLabel           = "Name:"
+	shovelVhostFieldLabel          = "Virtual host:"
+	shovelSourceURIFieldLabel      = "Source URI:"
+	shovelSourceTypeFieldLabel     = "Source type:"
+	shovelSourceFieldLabel         = "Source:"
+	shovelSourceKeyFieldLabel      = "Source routing key:"
+	shovelDestinationURIFieldLabel = "Destination URI:"
+	shovelDestinationTypeLabel     = "Destination type:"
+	shovelDestinationFieldLabel    = "Destination:"
+	shovelDestinationKeyFieldLabel = "Destination routing key:"
+	shovelAckModeFieldLabel        = "Ack mode:"
+	shovelDeleteAfterFieldLabel    = "Delete after:"
+)
+
+var (
+	shovelEndpointTypeOptions = []string{"Queue", "Exchange"}
+	shovelAckModeOptions      = []string{"on-confirm", "on-publish", "no-ack"}
+	shovelDeleteAfterOptions  = []string{"Never", "Initial transfer"}
+)
+
+func ShowCreateShovelDialog(mm model.ModalManager, okFn CreateShovelFn) {
+	f := ui.NewModalForm()
+	c := cluster.Current()
+
+	virtualHostNames := utils.Map(c.VirtualHosts(), func(vh rabbithole.VhostInfo) string { return vh.Name })
+	activeVhostNameIndex := max(0, slices.Index(virtualHostNames, c.ActiveVirtualHost()))
+
+	f.AddInputField(shovelNameFieldLabel, "", 30, nil, nil)
+	f.AddDropDown(shovelVhostFieldLabel, virtualHostNames, activeVhostNameIndex, nil)
+	f.AddInputField(shovelSourceURIFieldLabel, "", 50, nil, nil)
+	f.AddDropDown(shovelSourceTypeFieldLabel, shovelEndpointTypeOptions, 0, nil)
+	f.AddInputField(shovelSourceFieldLabel, "", 30, nil, nil)
+	f.AddInputField(shovelSourceKeyFieldLabel, "", 30, nil, nil)
+	f.AddInputField(shovelDestinationURIFieldLabel, "", 50, nil, nil)
+	f.AddDropDown(shovelDestinationTypeLabel, shovelEndpointTypeOptions, 0, nil)
+	f.AddInputField(shovelDestinationFieldLabel, "", 30, nil, nil)
+	f.AddInputField(shovelDestinationKeyFieldLabel, "", 30, nil, nil)
+	f.AddDropDown(shovelAckModeFieldLabel, shovelAckModeOptions, 0, nil)
+	f.AddDropDown(shovelDeleteAfterFieldLabel, shovelDeleteAfterOptions, 0, nil)
+
+	f.AddButtons([]string{"Cancel", "Create"})
+
+	nameField := f.GetFormItemByLabel(shovelNameFieldLabel).(*tview.InputField)
+	vhostField := f.GetFormItemByLabel(shovelVhostFieldLabel).(*tview.DropDown)
+	sourceURIField := f.GetFormItemByLabel(shovelSourceURIFieldLabel).(*tview.InputField)
+	sourceTypeField := f.GetFormItemByLabel(shovelSourceTypeFieldLabel).(*tview.DropDown)
+	sourceField := f.GetFormItemByLabel(shovelSourceFieldLabel).(*tview.InputField)
+	sourceKeyField := f.GetFormItemByLabel(shovelSourceKeyFieldLabel).(*tview.InputField)
+	destinationURIField := f.GetFormItemByLabel(shovelDestinationURIFieldLabel).(*tview.InputField)
+	destinationTypeField := f.GetFormItemByLabel(shovelDestinationTypeLabel).(*tview.DropDown)
+	destinationField := f.GetFormItemByLabel(shovelDestinationFieldLabel).(*tview.InputField)
+	destinationKeyField := f.GetFormItemByLabel(shovelDestinationKeyFieldLabel).(*tview.InputField)
+	ackModeField := f.GetFormItemByLabel(shovelAckModeFieldLabel).(*tview.DropDown)
+	deleteAfterField := f.GetFormItemByLabel(shovelDeleteAfterFieldLabel).(*tview.DropDown)
+
+	sourceURIField.SetPlaceholder("Selected virtual host")
+	destinationURIField.SetPlaceholder("Selected virtual host")
+	sourceKeyField.SetPlaceholder("Exchange source only")
+	destinationKeyField.SetPlaceholder("Keep original routing key")
+
+	f.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex != 1 {
+			mm.DismissModal()
+			return
+		}
+
+		name := strings.TrimSpace(nameField.GetText())
+		if name == "" {
+			f.SetFocus(f.GetFormItemIndex(shovelNameFieldLabel))
+			return
+		}
+
+		source := strings.TrimSpace(sourceField.GetText())
+		if source == "" {
+			f.SetFocus(f.GetFormItemIndex(shovelSourceFieldLabel))
+			return
+		}
+
+		destination := strings.TrimSpace(destinationField.GetText())
+		if destination == "" {
+			f.SetFocus(f.GetFormItemIndex(shovelDestinationFieldLabel))
+			return
+		}
+
+		_, vhost := vhostField.GetCurrentOption()
+		_, ackMode := ackModeField.GetCurrentOption()
+
+		sd := rabbithole.ShovelDefinition{
+			SourceURI:           shovelURIs(sourceURIField.GetText(), vhost),
+			DestinationURI:      shovelURIs(destinationURIField.GetText(), vhost),
+			SourceProtocol:      "amqp091",
+			DestinationProtocol: "amqp091",
+			AckMode:             ackMode,
+		}
+
+		if sourceIndex, _ := sourceTypeField.GetCurrentOption(); sourceIndex == 0 {
+			sd.SourceQueue = source
+		} else {
+			sd.SourceExchange = source
+			sd.SourceExchangeKey = sourceKeyField.GetText()
+		}
+
+		if destinationIndex, _ := destinationTypeField.GetCurrentOption(); destinationIndex == 0 {
+			sd.DestinationQueue = destination
+		} else {
+			sd.DestinationExchange = destination
+			sd.DestinationExchangeKey = destinationKeyField.GetText()
+		}
+
+		if deleteAfterIndex, _ := deleteAfterField.GetCurrentOption(); deleteAfterIndex == 1 {
+			sd.DeleteAfter = "queue-length"
+		}
+
+		okFn(vhost, name, sd)
+	})
+
+	f.SetTitle("Create shovel")
+
+	modal := ui.NewModalDialog(f, 80, 18)
+	mm.ShowModal(modal)
+}
+
+// LocalShovelURI returns the URI that points a shovel to the given virtual host
+// of the cluster the shovel is running in.
+func LocalShovelURI(vhost string) string {
+	return fmt.Sprintf("amqp:///%s", url.PathEscape(vhost))
+}
+
+func shovelURIs(text, vhost string) rabbithole.URISet {
+	uris := utils.FilterMap(
+		strings.Split(text, ","),
+		func(s string) bool { return strings.TrimSpace(s) != "" },
+		strings.TrimSpace)
+
+	if len(uris) == 0 {
+		return rabbithole.URISet{LocalShovelURI(vhost)}
+	}
+
+	return uris
+}
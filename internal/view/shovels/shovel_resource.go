@@ -0,0 +1,160 @@
+package shovels
+
+import (
+	"fmt"
+	"tbunny/internal/rmq"
+	"tbunny/internal/utils"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+)
+
+const (
+	shovelStateNotStarted = "not started"
+	shovelTypeDynamic     = "dynamic"
+)
+
+// ShovelResource combines a dynamic shovel definition with its runtime status.
+// Static shovels have no definition, and shovels that haven't started yet
+// have no status.
+type ShovelResource struct {
+	Name  string
+	Vhost string
+
+	definition *rabbithole.ShovelDefinition
+	status     *rmq.ShovelStatus
+}
+
+func ShovelRowID(vhost, name string) string {
+	return fmt.Sprintf("%s-%s", vhost, name)
+}
+
+func (r *ShovelResource) IsDynamic() bool {
+	return r.definition != nil || (r.status != nil && r.status.Type == shovelTypeDynamic)
+}
+
+func (r *ShovelResource) GetName() string {
+	return r.Name
+}
+
+func (r *ShovelResource) GetDisplayName() string {
+	return "shovel " + r.Name
+}
+
+func (r *ShovelResource) GetTableRowID() string {
+	return ShovelRowID(r.Vhost, r.Name)
+}
+
+func (r *ShovelResource) GetTableColumnValue(columnName string) string {
+	switch columnName {
+	case "vhost":
+		return r.Vhost
+	case "name":
+		return r.Name
+	case "type":
+		if r.status != nil {
+			return r.status.Type
+		}
+		return shovelTypeDynamic
+	case "state":
+		if r.status == nil {
+			return shovelStateNotStarted
+		}
+		return r.status.State
+	case "source":
+		return r.getSource()
+	case "destination":
+		return r.getDestination()
+	case "sourceUri":
+		return r.getSourceURI()
+	case "destinationUri":
+		return r.getDestinationURI()
+	case "forwarded":
+		if r.status == nil {
+			return ""
+		}
+		return fmt.Sprintf("%d", r.status.Forwarded)
+	case "remaining":
+		if r.status == nil || r.status.Remaining == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d", r.status.Remaining)
+	case "node":
+		if r.status == nil {
+			return ""
+		}
+		return r.status.Node
+	case "lastError":
+		if r.status == nil {
+			return ""
+		}
+		return r.status.Reason
+	default:
+		return ""
+	}
+}
+
+func (r *ShovelResource) getSource() string {
+	if d := r.definition; d != nil {
+		return formatEndpoint(d.SourceQueue, d.SourceExchange, d.SourceExchangeKey, d.SourceAddress)
+	}
+
+	if s := r.status; s != nil {
+		return formatEndpoint(s.SourceQueue, s.SourceExchange, s.SourceExchangeKey, "")
+	}
+
+	return ""
+}
+
+func (r *ShovelResource) getDestination() string {
+	if d := r.definition; d != nil {
+		return formatEndpoint(d.DestinationQueue, d.DestinationExchange, d.DestinationExchangeKey, d.DestinationAddress)
+	}
+
+	if s := r.status; s != nil {
+		return formatEndpoint(s.DestinationQueue, s.DestinationExchange, s.DestinationExchangeKey, "")
+	}
+
+	return ""
+}
+
+// The status URIs have credentials stripped, so we prefer them to the definition ones.
+// Definition URIs are redacted before being displayed.
+
+func (r *ShovelResource) getSourceURI() string {
+	if r.status != nil && r.status.SourceURI != "" {
+		return r.status.SourceURI
+	}
+
+	if r.definition != nil {
+		return utils.RedactURIs(r.definition.SourceURI)
+	}
+
+	return ""
+}
+
+func (r *ShovelResource) getDestinationURI() string {
+	if r.status != nil && r.status.DestinationURI != "" {
+		return r.status.DestinationURI
+	}
+
+	if r.definition != nil {
+		return utils.RedactURIs(r.definition.DestinationURI)
+	}
+
+	return ""
+}
+
+func formatEndpoint(queue, exchange, exchangeKey, address string) string {
+	switch {
+	case queue != "":
+		return "queue " + queue
+	case exchange != "" && exchangeKey != "":
+		return fmt.Sprintf("exchange %s (%s)", exchange, exchangeKey)
+	case exchange != "":
+		return "exchange " + exchange
+	case address != "":
+		return "address " + address
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,236 @@
+package shovels
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"tbunny/internal/model"
+	"tbunny/internal/rmq"
+	"tbunny/internal/skins"
+	"tbunny/internal/sl"
+	"tbunny/internal/ui"
+	"tbunny/internal/ui/dialog"
+	"tbunny/internal/view"
+	"tbunny/internal/view/vhosts"
+
+	"github.com/gdamore/tcell/v2"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
+	"github.com/rivo/tview"
+)
+
+type Shovels struct {
+	view.ClusterAwareResourceView[*ShovelResource]
+
+	wideMode bool
+}
+
+func NewShovels() model.View {
+	return newShovels()
+}
+
+// NewShovelsWithSelection creates a shovels view that selects the given shovel
+// as soon as it shows up.
+func NewShovelsWithSelection(vhost, name string) model.View {
+	s := newShovels()
+	s.SelectResource(ShovelRowID(vhost, name))
+
+	return s
+}
+
+func newShovels() *Shovels {
+	s := Shovels{
+		vhosts.NewVHostExtender[*ShovelResource](
+			view.NewClusterAwareResourceTableView[*ShovelResource]("Shovels", view.NewLiveUpdateStrategy()),
+		),
+		false,
+	}
+
+	s.SetResourceProvider(&s)
+	s.AddBindingKeysFn(s.bindKeys)
+
+	return &s
+}
+
+func (s *Shovels) GetResources() ([]*ShovelResource, error) {
+	definitions, statuses, err := s.getShovels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shovels: %w", err)
+	}
+
+	rows := make([]*ShovelResource, 0, len(definitions))
+	byID := make(map[string]*ShovelResource, len(definitions))
+
+	for i := range definitions {
+		d := &definitions[i]
+		r := &ShovelResource{Name: d.Name, Vhost: d.Vhost, definition: &d.Definition}
+
+		rows = append(rows, r)
+		byID[r.GetTableRowID()] = r
+	}
+
+	for i := range statuses {
+		st := &statuses[i]
+
+		if r, ok := byID[ShovelRowID(st.Vhost, st.Name)]; ok {
+			r.status = st
+		} else {
+			rows = append(rows, &ShovelResource{Name: st.Name, Vhost: st.Vhost, status: st})
+		}
+	}
+
+	slices.SortFunc(rows, func(a, b *ShovelResource) int {
+		if c := strings.Compare(a.Vhost, b.Vhost); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return rows, nil
+}
+
+func (s *Shovels) getShovels() (definitions []rabbithole.ShovelInfo, statuses []rmq.ShovelStatus, err error) {
+	c := s.Cluster()
+	vhost := c.ActiveVirtualHost()
+
+	slog.Debug("Fetching shovels", sl.Component, s.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+
+	if vhost == "" {
+		definitions, err = c.ListShovels()
+		if err == nil {
+			statuses, err = c.ListShovelStatuses()
+		}
+	} else {
+		definitions, err = c.ListShovelsIn(vhost)
+		if err == nil {
+			statuses, err = c.ListShovelStatusesIn(vhost)
+		}
+	}
+
+	if err != nil {
+		slog.Error("Failed to fetch shovels", sl.Error, err, sl.Component, s.Name(), sl.Cluster, c.Name(), sl.VirtualHost, vhost)
+	}
+
+	return definitions, statuses, err
+}
+
+func (s *Shovels) GetColumns() []ui.TableColumn {
+	c := []ui.TableColumn{
+		{Name: "name", Title: "NAME", Expansion: 2},
+		{Name: "state", Title: "STATE"},
+		{Name: "source", Title: "SOURCE", Expansion: 1},
+		{Name: "destination", Title: "DESTINATION", Expansion: 1},
+		{Name: "forwarded", Title: "FORWARDED", Align: tview.AlignRight},
+		{Name: "remaining", Title: "REMAINING", Align: tview.AlignRight},
+	}
+
+	if s.wideMode {
+		c = append(c, []ui.TableColumn{
+			{Name: "type", Title: "TYPE"},
+			{Name: "sourceUri", Title: "SOURCE URI", MaxWidth: 40},
+			{Name: "destinationUri", Title: "DESTINATION URI", MaxWidth: 40},
+		}...)
+	}
+
+	if s.Cluster().ActiveVirtualHost() == "" {
+		c = append(c, ui.TableColumn{Name: "vhost", Title: "VHOST"})
+	}
+
+	if s.wideMode {
+		c = append(c, ui.TableColumn{Name: "node", Title: "NODE"})
+	}
+
+	c = append(c, ui.TableColumn{Name: "lastError", Title: "LAST ERROR", Expansion: 2, MaxWidth: 60})
+
+	return c
+}
+
+func (s *Shovels) CanDeleteResources() bool {
+	return true
+}
+
+func (s *Shovels) DeleteResource(resource *ShovelResource) error {
+	if !resource.IsDynamic() {
+		return errors.New("static shovels are configured in the broker configuration file")
+	}
+
+	_, err := s.Cluster().DeleteShovel(resource.Vhost, resource.Name)
+
+	return err
+}
+
+func (s *Shovels) bindKeys(km ui.KeyMap) {
+	if s.Cluster().IsAvailable() {
+		km.Add(ui.KeyC, ui.NewKeyAction("Create", s.createShovelCmd))
+		km.Add(ui.KeyR, ui.NewKeyAction("Restart", s.restartShovelCmd))
+		km.Add(tcell.KeyCtrlW, ui.NewKeyAction("Toggle wide mode", s.toggleWideModeCmd))
+	}
+}
+
+func (s *Shovels) toggleWideModeCmd(*tcell.EventKey) *tcell.EventKey {
+	s.wideMode = !s.wideMode
+
+	s.RequestUpdate(view.FullUpdate)
+
+	return nil
+}
+
+func (s *Shovels) createShovelCmd(*tcell.EventKey) *tcell.EventKey {
+	ShowCreateShovelDialog(s.App(), s.createShovel)
+
+	return nil
+}
+
+func (s *Shovels) createShovel(vhost, name string, definition rabbithole.ShovelDefinition) {
+	s.App().StatusLine().Infof("Creating shovel %s", name)
+
+	_, err := s.Cluster().DeclareShovel(vhost, name, definition)
+	if err != nil {
+		s.App().StatusLine().Errorf("Failed to create shovel %s: %s", name, err.Error())
+		return
+	}
+
+	s.SelectResource(ShovelRowID(vhost, name))
+	s.RequestUpdate(view.PartialUpdate)
+
+	s.App().DismissModal()
+}
+
+func (s *Shovels) restartShovelCmd(*tcell.EventKey) *tcell.EventKey {
+	shovel, ok := s.GetSelectedResource()
+	if !ok {
+		return nil
+	}
+
+	if !shovel.IsDynamic() {
+		s.App().StatusLine().Error("Only dynamic shovels can be restarted")
+		return nil
+	}
+
+	msg := fmt.Sprintf("Restart %s?", shovel.GetDisplayName())
+
+	modal := dialog.CreateConfirmDialog(
+		skins.Current(),
+		"Confirm Restart",
+		msg,
+		func() {
+			s.App().StatusLine().Infof("Restarting %s...", shovel.GetDisplayName())
+
+			err := s.Cluster().RestartShovel(shovel.Vhost, shovel.Name)
+			if err != nil {
+				s.App().StatusLine().Errorf("Failed to restart shovel: %s", err)
+			} else {
+				s.App().StatusLine().Infof("Restarted %s", shovel.GetDisplayName())
+				s.RequestUpdate(view.PartialUpdate)
+			}
+		},
+		func() {
+			s.App().DismissModal()
+		})
+
+	s.App().ShowModal(modal)
+
+	return nil
+}
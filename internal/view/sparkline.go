@@ -0,0 +1,118 @@
+package view
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"tbunny/internal/utils"
+	"time"
+)
+
+var sparklineRunes = []rune("▁▂▃▄▅▆▇█")
+
+// RenderSparkline renders values as a line of bars scaled between their
+// minimum and maximum. NaN values, e.g. before the first sample, are blank.
+func RenderSparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+
+	var b strings.Builder
+
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi == lo:
+			// A flat line is drawn low when it is zero and in the middle otherwise.
+			if v == 0 {
+				b.WriteRune(sparklineRunes[0])
+			} else {
+				b.WriteRune(sparklineRunes[len(sparklineRunes)/2])
+			}
+		default:
+			i := int((v - lo) / (hi - lo) * float64(len(sparklineRunes)-1))
+			b.WriteRune(sparklineRunes[i])
+		}
+	}
+
+	return b.String()
+}
+
+// Trend is the history of a metric, displayed as a sparkline.
+type Trend struct {
+	Label  string
+	Values []float64
+	// Format formats the values of the metric, e.g. FormatBytes.
+	Format func(v float64) string
+}
+
+// TrendsCaption returns the caption of trends over a window, e.g. "Trends (5m)".
+func TrendsCaption(window time.Duration) string {
+	return fmt.Sprintf("Trends (%s)", FormatWindow(window))
+}
+
+// FormatWindow formats a window of history, e.g. 5m or 1h.
+func FormatWindow(window time.Duration) string {
+	if window >= time.Hour && window%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(window.Hours()))
+	}
+
+	return fmt.Sprintf("%dm", int(window.Minutes()))
+}
+
+// WriteTrends writes a line per trend into b: its label, its sparkline, its
+// last value and its range, with the [label] and [value] tags of
+// SkinStatsContent.
+func WriteTrends(b *strings.Builder, trends []Trend) {
+	maxLen := 0
+	for _, t := range trends {
+		maxLen = max(maxLen, len(t.Label))
+	}
+
+	for _, t := range trends {
+		last, lo, hi := math.NaN(), math.Inf(1), math.Inf(-1)
+
+		for _, v := range t.Values {
+			if !math.IsNaN(v) {
+				last, lo, hi = v, min(lo, v), max(hi, v)
+			}
+		}
+
+		utils.Sbprintf(b, "[label]%s[-] [value]%s[-]", utils.PadRight(t.Label, maxLen), RenderSparkline(t.Values))
+
+		if !math.IsNaN(last) {
+			utils.Sbprintf(b, " [value]%s[-] [label](%s – %s)[-]", t.Format(last), t.Format(lo), t.Format(hi))
+		}
+
+		b.WriteString("\n")
+	}
+}
+
+// WriteTrendsSection writes trends over a window as a section like
+// WriteTextSection, with a caption and a separator.
+func WriteTrendsSection(b *strings.Builder, window time.Duration, trends []Trend) {
+	utils.Sbprintf(b, "[caption]%s[-]\n", TrendsCaption(window))
+	b.WriteString(strings.Repeat("─", 30) + "\n")
+	WriteTrends(b, trends)
+	b.WriteString("\n")
+}
+
+// FormatCount formats a number of messages, sockets, etc.
+func FormatCount(v float64) string {
+	return fmt.Sprintf("%.0f", v)
+}
+
+// FormatMessageRate formats a rate in messages per second.
+func FormatMessageRate(v float64) string {
+	return fmt.Sprintf("%.1f/s", v)
+}
+
+// FormatByteSize formats a size in bytes, see FormatBytes.
+func FormatByteSize(v float64) string {
+	return FormatBytes(int64(v))
+}
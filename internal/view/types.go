@@ -29,6 +29,22 @@ type ResourceProvider[R Resource] interface {
 	DeleteResource(resource R) error
 }
 
+// ResourceFilterer is implemented by resource providers whose resources are
+// filtered on more than their column values, e.g. message payloads.
+type ResourceFilterer[R Resource] interface {
+	// ResourceMatcher returns a matcher for the filter, or an error if the
+	// filter is not valid.
+	ResourceMatcher(filter string) (func(R) bool, error)
+}
+
+// ResourceAlerter is implemented by resource providers whose resources are
+// checked against the alert rules of the cluster, which color the rows of
+// matching resources.
+type ResourceAlerter interface {
+	// AlertResource returns the type of resources of alert rules, e.g. alerts.Queues.
+	AlertResource() string
+}
+
 // ResourceView represents a view that displays resources.
 type ResourceView[R Resource] interface {
 	model.View
@@ -39,6 +55,8 @@ type ResourceView[R Resource] interface {
 
 	// SetPath sets the path for the view.
 	SetPath(path string)
+	// GetFilter returns the current filter of the view.
+	GetFilter() string
 
 	// SetResourceProvider sets the resource provider for the view.
 	SetResourceProvider(rp ResourceProvider[R])
@@ -48,6 +66,12 @@ type ResourceView[R Resource] interface {
 	SetEnterAction(title string, fn func(R))
 	// GetSelectedResource returns the selected resource.
 	GetSelectedResource() (row R, ok bool)
+	// GetSelectedResources returns the marked resources or, if none is marked, the selected resource.
+	GetSelectedResources() []R
+	// GetMarkedResources returns the marked resources which are not hidden by the filter.
+	GetMarkedResources() []R
+	// SelectResource selects the resource with the given table row ID.
+	SelectResource(rowID string)
 
 	// RequestUpdate requests an update for the view.
 	RequestUpdate(kind UpdateKind)
@@ -3,11 +3,13 @@ package vhosts
 import (
 	"slices"
 	"strings"
+	"tbunny/internal/alerts"
 	"tbunny/internal/cluster"
 	"tbunny/internal/model"
 	"tbunny/internal/ui"
 	"tbunny/internal/utils"
 	"tbunny/internal/view"
+	"tbunny/internal/view/definitions"
 
 	"github.com/gdamore/tcell/v2"
 	rabbithole "github.com/michaelklishin/rabbit-hole/v3"
@@ -43,6 +45,11 @@ func (v *VHosts) Stop() {
 	v.ClusterAwareResourceView.Stop()
 }
 
+// AlertResource returns the type of resources of alert rules applying to the view.
+func (v *VHosts) AlertResource() string {
+	return alerts.VHosts
+}
+
 func (v *VHosts) GetResources() ([]*VHostResource, error) {
 	activeVhost := v.Cluster().ActiveVirtualHost()
 	clusterVhosts := v.Cluster().VirtualHosts()
@@ -108,6 +115,27 @@ func (v *VHosts) ClusterActiveVirtualHostChanged(*cluster.Cluster) {
 
 func (v *VHosts) bindKeys(km ui.KeyMap) {
 	km.Add(ui.KeyC, ui.NewKeyAction("Create", v.createVHostCmd))
+
+	if v.Cluster().IsAvailable() {
+		km.Add(ui.KeyX, ui.NewKeyAction("Export definitions", v.exportDefinitionsCmd))
+		km.Add(ui.KeyI, ui.NewKeyAction("Import definitions", v.importDefinitionsCmd))
+	}
+}
+
+func (v *VHosts) exportDefinitionsCmd(*tcell.EventKey) *tcell.EventKey {
+	if row, ok := v.GetSelectedResource(); ok {
+		definitions.Export(v.App(), v.Cluster(), row.Name)
+	}
+
+	return nil
+}
+
+func (v *VHosts) importDefinitionsCmd(*tcell.EventKey) *tcell.EventKey {
+	if row, ok := v.GetSelectedResource(); ok {
+		definitions.Import(v.App(), v.Cluster(), row.Name)
+	}
+
+	return nil
 }
 
 func (v *VHosts) selectVHost(row *VHostResource) {